@@ -1,9 +1,72 @@
 package cmd
 
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/bundle"
+	"github.com/offlinefirst/limitless-context/pkg/prompts"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
 func newBundleCommand() command {
 	return command{
 		name:        "bundle",
 		description: "Generate task bundles from a completed capture run",
-		run:         stubRun("bundle"),
+		configure: func(fs *flag.FlagSet) {
+			fs.String("run", "", "Run identifier under runs_dir to bundle")
+		},
+		run: runBundle,
+	}
+}
+
+func runBundle(fs *flag.FlagSet, args []string, ctx *AppContext, stdout io.Writer, stderr io.Writer) error {
+	if ctx == nil {
+		return fmt.Errorf("application context unavailable")
+	}
+
+	layout, man, err := loadRun(ctx, stringFlag(fs, "run"))
+	if err != nil {
+		return err
+	}
+	ctx.Logger.Info("bundle command invoked", "run_id", man.RunID, "root", layout.Root)
+
+	result, err := bundle.Build(bundleOptions(ctx, layout, man))
+	if err != nil {
+		ctx.Logger.Error("bundle generation failed", "run_id", man.RunID, "error", err)
+		return fmt.Errorf("build bundles: %w", err)
+	}
+	ctx.Logger.Info("bundles written", "run_id", man.RunID, "tasks", len(result.Tasks))
+
+	fmt.Fprintf(stdout, "Bundled run %s: %d task(s) -> %s\n", man.RunID, len(result.Tasks), result.BundlesDir)
+	for _, task := range result.Tasks {
+		fmt.Fprintf(stdout, "  - %s: %s -> %s, %d events, %d OCR, %d ASR, %d context tokens\n",
+			task.ID, task.Start.Format(time.RFC3339), task.End.Format(time.RFC3339),
+			task.EventCount, task.OCRCount, task.ASRCount, task.ContextTokens)
+		if task.DroppedCount > 0 {
+			fmt.Fprintf(stdout, "    %d item(s) trimmed to fit the token budget; see %s\n", task.DroppedCount, filepath.Join(task.Dir, "metrics.json"))
+		}
+	}
+	fmt.Fprintf(stdout, "Day summary: %s (%d of %d task output(s) inlined)\n", result.DaySummaryDir, result.InlinedOutputs, len(result.Tasks))
+	fmt.Fprintf(stdout, "Instructions: %s\n", result.ReadmePath)
+	return nil
+}
+
+// bundleOptions maps the summarizer config onto bundle.Options for a run.
+func bundleOptions(ctx *AppContext, layout runmanifest.Layout, man runmanifest.Manifest) bundle.Options {
+	return bundle.Options{
+		Layout:    layout,
+		Manifest:  man,
+		Tokenizer: ctx.Config.Summarizer.Tokenizer,
+		Templates: prompts.NewSet(prompts.Options{Dirs: []string{
+			ctx.Config.Summarizer.TemplatesDir,
+			filepath.Join(ctx.Config.Paths.CacheDir, "prompts"),
+		}}),
+		IdleGap:            time.Duration(ctx.Config.Summarizer.IdleGapSeconds) * time.Second,
+		PerTaskTokenBudget: ctx.Config.Summarizer.PerTaskTokenBudget,
+		MaxContextTokens:   ctx.Config.Summarizer.MaxContextTokens,
 	}
 }
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/config"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+// captureTestRun performs a synthetic capture and returns the context and run id.
+func captureTestRun(t *testing.T) (*AppContext, string) {
+	t.Helper()
+	installCmdVideoFake(t)
+
+	cfg := config.Default()
+	cfg.Capture.Screenshots.IntervalSeconds = 1
+	cfg.Capture.Screenshots.MaxPerMinute = 1
+	cfg.Paths.RunsDir = t.TempDir()
+	ctx := &AppContext{Config: cfg, Logger: newTestLogger()}
+
+	now := time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+	origTime := timeNow
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = origTime })
+
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.Bool("plan-only", false, "")
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if err := runCapture(fs, nil, ctx, io.Discard, io.Discard); err != nil {
+		t.Fatalf("runCapture returned error: %v", err)
+	}
+	return ctx, now.Format("20060102_150405")
+}
+
+func TestBundleCommandWritesBundles(t *testing.T) {
+	ctx, runID := captureTestRun(t)
+
+	fs := flag.NewFlagSet("bundle", flag.ContinueOnError)
+	fs.String("run", "", "")
+	if err := fs.Parse([]string{"-run", runID}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	if err := runBundle(fs, nil, ctx, &stdout, io.Discard); err != nil {
+		t.Fatalf("runBundle returned error: %v", err)
+	}
+
+	layout := runmanifest.BuildLayout(ctx.Config.Paths.RunsDir, runID)
+	for _, rel := range []string{
+		"task_001/prompt.txt",
+		"task_001/context.md",
+		"task_001/metrics.json",
+		"day_summary/prompt.txt",
+		"day_summary/context_index.json",
+		"README_bundles.md",
+	} {
+		if _, err := os.Stat(filepath.Join(layout.BundlesDir, rel)); err != nil {
+			t.Fatalf("expected %s: %v", rel, err)
+		}
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte("Bundled run "+runID)) {
+		t.Fatalf("expected bundle summary, got %q", stdout.String())
+	}
+}
+
+func TestBundleCommandRequiresRun(t *testing.T) {
+	ctx := &AppContext{Config: config.Default(), Logger: newTestLogger()}
+	ctx.Config.Paths.RunsDir = t.TempDir()
+
+	fs := flag.NewFlagSet("bundle", flag.ContinueOnError)
+	fs.String("run", "", "")
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if err := runBundle(fs, nil, ctx, io.Discard, io.Discard); err == nil {
+		t.Fatalf("expected error when --run is missing")
+	}
+
+	if err := fs.Parse([]string{"-run", "missing"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if err := runBundle(fs, nil, ctx, io.Discard, io.Discard); err == nil {
+		t.Fatalf("expected error for unknown run")
+	}
+}
+
+func TestDaySummaryCommandInlinesSavedOutputs(t *testing.T) {
+	ctx, runID := captureTestRun(t)
+	if err := runBundle(runFlags(t, "bundle", runID), nil, ctx, io.Discard, io.Discard); err != nil {
+		t.Fatalf("runBundle returned error: %v", err)
+	}
+	layout := runmanifest.BuildLayout(ctx.Config.Paths.RunsDir, runID)
+	output := `{"task_id":"task_001","title":"Capture","summary":"Ran a synthetic capture."}`
+	if err := os.WriteFile(filepath.Join(layout.BundlesDir, "task_001", "output.json"), []byte(output), 0o644); err != nil {
+		t.Fatalf("write output: %v", err)
+	}
+
+	if err := runBundle(runFlags(t, "bundle", runID), nil, ctx, io.Discard, io.Discard); err == nil {
+		t.Fatalf("expected bundle to refuse once an output is saved")
+	}
+	var stdout bytes.Buffer
+	if err := runDaySummary(runFlags(t, "day-summary", runID), nil, ctx, &stdout, io.Discard); err != nil {
+		t.Fatalf("runDaySummary returned error: %v", err)
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte("(1 of ")) {
+		t.Fatalf("expected one inlined output, got %q", stdout.String())
+	}
+	contextDoc, err := os.ReadFile(filepath.Join(layout.BundlesDir, "day_summary", "context.md"))
+	if err != nil || !bytes.Contains(contextDoc, []byte("Ran a synthetic capture.")) {
+		t.Fatalf("expected the saved output inlined (%v):\n%s", err, contextDoc)
+	}
+}
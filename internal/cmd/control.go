@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/offlinefirst/limitless-context/pkg/control"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+func newPauseCommand() command {
+	return newControlCommand(control.ActionPause, "Pause the running capture")
+}
+
+func newResumeCommand() command {
+	return newControlCommand(control.ActionResume, "Resume a paused capture")
+}
+
+func newStopCommand() command {
+	return newControlCommand(control.ActionStop, "Stop the running capture early and finalise its artifacts")
+}
+
+func newControlCommand(action, description string) command {
+	return command{
+		name:        action,
+		description: description,
+		configure: func(fs *flag.FlagSet) {
+			fs.String("run", "", "Run identifier under runs_dir (default: the latest run)")
+			fs.String("reason", "", "Reason recorded in the controller timeline")
+		},
+		run: func(fs *flag.FlagSet, args []string, ctx *AppContext, stdout io.Writer, stderr io.Writer) error {
+			return runControl(action, fs, ctx, stdout)
+		},
+	}
+}
+
+// runControl sends action to the capture listening on the run's control
+// socket. The transition, with its reason, lands in capture.log and the
+// manifest's controller timeline.
+func runControl(action string, fs *flag.FlagSet, ctx *AppContext, stdout io.Writer) error {
+	if ctx == nil {
+		return fmt.Errorf("application context unavailable")
+	}
+
+	runID, err := resolveRunID(ctx, stringFlag(fs, "run"))
+	if err != nil {
+		return err
+	}
+	layout, _, err := loadRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	reason := strings.TrimSpace(stringFlag(fs, "reason"))
+	if reason == "" {
+		reason = "requested via tester " + action
+	}
+	ctx.Logger.Info("control command invoked", "action", action, "run_id", runID, "reason", reason)
+
+	resp, err := control.Send(control.SocketPath(layout), control.Request{Action: action, Reason: reason})
+	if errors.Is(err, control.ErrNotRunning) {
+		return fmt.Errorf("run %s is not capturing: %w", runID, err)
+	}
+	if err != nil {
+		return fmt.Errorf("%s run %s: %w", action, runID, err)
+	}
+	if !resp.Changed {
+		fmt.Fprintf(stdout, "Run %s is already %s\n", runID, resp.State)
+		return nil
+	}
+	fmt.Fprintf(stdout, "Run %s: %s (%s)\n", runID, resp.State, reason)
+	return nil
+}
+
+// resolveRunID returns runID, or the newest run when it is empty.
+func resolveRunID(ctx *AppContext, runID string) (string, error) {
+	if runID = strings.TrimSpace(runID); runID != "" {
+		return runID, nil
+	}
+	return runmanifest.LatestRunID(ctx.Config.Paths.RunsDir)
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/offlinefirst/limitless-context/pkg/capture"
+	"github.com/offlinefirst/limitless-context/pkg/config"
+	"github.com/offlinefirst/limitless-context/pkg/control"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+func controlFlags(t *testing.T, action string, args ...string) *flag.FlagSet {
+	t.Helper()
+	fs := flag.NewFlagSet(action, flag.ContinueOnError)
+	newControlCommand(action, "").configure(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	return fs
+}
+
+func TestControlCommandsDriveCapture(t *testing.T) {
+	// Unix socket paths are short-lived and length-limited, so avoid the
+	// long per-test temp directory.
+	runsDir, err := os.MkdirTemp("", "runs")
+	if err != nil {
+		t.Fatalf("mkdir temp: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(runsDir) })
+	cfg := config.Default()
+	cfg.Paths.RunsDir = runsDir
+	ctx := &AppContext{Config: cfg, Logger: newTestLogger()}
+
+	layout := runmanifest.BuildLayout(runsDir, "20240512_093000")
+	if err := runmanifest.EnsureFilesystem(layout); err != nil {
+		t.Fatalf("ensure filesystem: %v", err)
+	}
+	if err := runmanifest.Save(runmanifest.Manifest{RunID: "20240512_093000", Status: runmanifest.Status{State: "running"}}, layout.ManifestPath); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	if err := runControl(control.ActionStop, controlFlags(t, control.ActionStop), ctx, &stdout); !errors.Is(err, control.ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning without a listening capture, got %v", err)
+	}
+
+	controller := capture.NewController()
+	server, err := control.Listen(control.SocketPath(layout), controller)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer server.Close()
+	changes, cancel := controller.Subscribe()
+	defer cancel()
+	<-changes
+
+	if err := runControl(control.ActionPause, controlFlags(t, control.ActionPause, "-reason", "lunch"), ctx, &stdout); err != nil {
+		t.Fatalf("pause returned error: %v", err)
+	}
+	if err := runControl(control.ActionPause, controlFlags(t, control.ActionPause, "-run", "20240512_093000"), ctx, &stdout); err != nil {
+		t.Fatalf("second pause returned error: %v", err)
+	}
+	if err := runControl(control.ActionResume, controlFlags(t, control.ActionResume), ctx, &stdout); err != nil {
+		t.Fatalf("resume returned error: %v", err)
+	}
+	if err := runControl(control.ActionStop, controlFlags(t, control.ActionStop), ctx, &stdout); err != nil {
+		t.Fatalf("stop returned error: %v", err)
+	}
+
+	out := stdout.String()
+	for _, want := range []string{
+		"Run 20240512_093000: paused (lunch)",
+		"Run 20240512_093000 is already paused",
+		"Run 20240512_093000: running (requested via tester resume)",
+		"Run 20240512_093000: stopping (requested via tester stop)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q:\n%s", want, out)
+		}
+	}
+	var reasons []string
+	for i := 0; i < 3; i++ {
+		change := <-changes
+		reasons = append(reasons, change.State+": "+change.Reason)
+	}
+	if strings.Join(reasons, ", ") != "paused: lunch, running: requested via tester resume, stopping: requested via tester stop" {
+		t.Fatalf("unexpected transitions: %v", reasons)
+	}
+}
+
+func TestRunRemovesControlSocket(t *testing.T) {
+	ctx, runID := captureTestRun(t)
+	layout := runmanifest.BuildLayout(ctx.Config.Paths.RunsDir, runID)
+	if _, err := os.Stat(control.SocketPath(layout)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected control socket to be removed after capture, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/offlinefirst/limitless-context/pkg/bundle"
+)
+
+func newDaySummaryCommand() command {
+	return command{
+		name:        "day-summary",
+		description: "Refresh the day summary bundle from saved task outputs",
+		configure: func(fs *flag.FlagSet) {
+			fs.String("run", "", "Run identifier under runs_dir whose day summary to refresh")
+		},
+		run: runDaySummary,
+	}
+}
+
+func runDaySummary(fs *flag.FlagSet, args []string, ctx *AppContext, stdout io.Writer, stderr io.Writer) error {
+	if ctx == nil {
+		return fmt.Errorf("application context unavailable")
+	}
+
+	layout, man, err := loadRun(ctx, stringFlag(fs, "run"))
+	if err != nil {
+		return err
+	}
+	ctx.Logger.Info("day-summary command invoked", "run_id", man.RunID, "root", layout.Root)
+
+	result, err := bundle.RefreshDaySummary(bundleOptions(ctx, layout, man))
+	if err != nil {
+		ctx.Logger.Error("day summary refresh failed", "run_id", man.RunID, "error", err)
+		return fmt.Errorf("refresh day summary: %w", err)
+	}
+	ctx.Logger.Info("day summary written", "run_id", man.RunID, "inlined", result.InlinedOutputs)
+
+	fmt.Fprintf(stdout, "Day summary: %s (%d of %d task output(s) inlined)\n", result.DaySummaryDir, result.InlinedOutputs, len(result.Tasks))
+	return nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/offlinefirst/limitless-context/pkg/privacyscan"
+)
+
+func newPrivacyScanCommand() command {
+	return command{
+		name:        "privacy-scan",
+		description: "Search a run's text artifacts for unmasked sensitive data",
+		configure: func(fs *flag.FlagSet) {
+			fs.String("run", "", "Run identifier under runs_dir to scan")
+		},
+		run: runPrivacyScan,
+	}
+}
+
+// runPrivacyScan writes report/privacy_scan.json and fails when any
+// unmasked match was found, so the check can gate scripts.
+func runPrivacyScan(fs *flag.FlagSet, args []string, ctx *AppContext, stdout io.Writer, stderr io.Writer) error {
+	if ctx == nil {
+		return fmt.Errorf("application context unavailable")
+	}
+
+	layout, man, err := loadRun(ctx, stringFlag(fs, "run"))
+	if err != nil {
+		return err
+	}
+	ctx.Logger.Info("privacy scan command invoked", "run_id", man.RunID, "root", layout.Root)
+
+	scan, err := privacyscan.Scan(privacyscan.Options{Layout: layout, Manifest: man, Patterns: ctx.Config.Capture.Events.RedactPatterns, Clock: timeNow})
+	if err != nil {
+		return fmt.Errorf("privacy scan: %w", err)
+	}
+	path := filepath.Join(layout.ReportDir, privacyscan.FileName)
+	if err := privacyscan.Save(scan, path); err != nil {
+		return err
+	}
+	ctx.Logger.Info("privacy scan written", "run_id", man.RunID, "path", path, "unmasked", scan.Summary.Unmasked, "masked", scan.Summary.Masked)
+
+	fmt.Fprintf(stdout, "Privacy scan for run %s: %d text file(s), %d unmasked match(es), %d redaction mask(s)\n",
+		man.RunID, scan.Summary.Files, scan.Summary.Unmasked, scan.Summary.Masked)
+	for _, detector := range scan.Detectors {
+		if detector.Unmasked > 0 {
+			fmt.Fprintf(stdout, "  %s: %d\n", detector.Name, detector.Unmasked)
+		}
+	}
+	for _, violation := range scan.Violations {
+		fmt.Fprintf(stdout, "    %s:%d %s %s\n", violation.Path, violation.Line, violation.Detector, violation.Preview)
+	}
+	if scan.Truncated {
+		fmt.Fprintf(stderr, "Warning: only the first %d matches are listed\n", len(scan.Violations))
+	}
+	fmt.Fprintf(stdout, "Privacy scan: %s\n", path)
+	if !scan.Clean() {
+		return fmt.Errorf("privacy scan found %d unmasked match(es)", scan.Summary.Unmasked)
+	}
+	return nil
+}
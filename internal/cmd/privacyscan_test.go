@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/offlinefirst/limitless-context/pkg/privacyscan"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+func TestPrivacyScanCommandReportsViolations(t *testing.T) {
+	ctx, runID := captureTestRun(t)
+
+	var stdout bytes.Buffer
+	if err := runPrivacyScan(runFlags(t, "privacy-scan", runID), nil, ctx, &stdout, io.Discard); err != nil {
+		t.Fatalf("captured run should scan clean: %v\n%s", err, stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "0 unmasked match(es)") {
+		t.Fatalf("unexpected stdout: %q", stdout.String())
+	}
+
+	layout := runmanifest.BuildLayout(ctx.Config.Paths.RunsDir, runID)
+	if err := os.WriteFile(filepath.Join(layout.ASRDir, "notes.txt"), []byte("ping ops@example.com\n"), 0o644); err != nil {
+		t.Fatalf("write notes: %v", err)
+	}
+	stdout.Reset()
+	err := runPrivacyScan(runFlags(t, "privacy-scan", runID), nil, ctx, &stdout, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "1 unmasked match(es)") {
+		t.Fatalf("expected violation error, got %v", err)
+	}
+	if !strings.Contains(stdout.String(), "asr/notes.txt:1 email op…om (15 chars)") {
+		t.Fatalf("unexpected stdout: %q", stdout.String())
+	}
+	scan, err := privacyscan.Load(filepath.Join(layout.ReportDir, privacyscan.FileName))
+	if err != nil {
+		t.Fatalf("load privacy scan: %v", err)
+	}
+	if scan.RunID != runID || scan.Summary.Unmasked != 1 {
+		t.Fatalf("unexpected privacy_scan.json: %+v", scan.Summary)
+	}
+}
@@ -1,9 +1,71 @@
 package cmd
 
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/offlinefirst/limitless-context/pkg/importer"
+)
+
 func newProcessCommand() command {
 	return command{
 		name:        "process",
 		description: "Validate LLM outputs and update run artifacts",
-		run:         stubRun("process"),
+		configure: func(fs *flag.FlagSet) {
+			fs.String("run", "", "Run identifier under runs_dir to process")
+		},
+		run: runProcess,
+	}
+}
+
+func runProcess(fs *flag.FlagSet, args []string, ctx *AppContext, stdout io.Writer, stderr io.Writer) error {
+	if ctx == nil {
+		return fmt.Errorf("application context unavailable")
+	}
+
+	layout, man, err := loadRun(ctx, stringFlag(fs, "run"))
+	if err != nil {
+		return err
+	}
+	ctx.Logger.Info("process command invoked", "run_id", man.RunID, "root", layout.Root)
+
+	report, err := importer.Run(importer.Options{Layout: layout, Manifest: man, Clock: timeNow})
+	if err != nil {
+		ctx.Logger.Error("output import failed", "run_id", man.RunID, "error", err)
+		return fmt.Errorf("process outputs: %w", err)
+	}
+	ctx.Logger.Info("outputs imported", "run_id", man.RunID, "valid", report.Summary.Valid, "invalid", report.Summary.Invalid, "missing", report.Summary.Missing)
+
+	fmt.Fprintf(stdout, "Processed run %s: %d of %d task output(s) valid, %d invalid, %d missing\n",
+		man.RunID, report.Summary.Valid, report.Summary.Tasks, report.Summary.Invalid, report.Summary.Missing)
+	fmt.Fprintf(stdout, "Evidence: %d of %d citation(s) resolved, %d dangling\n", report.Summary.Resolved, report.Summary.Cited, report.Summary.Dangling)
+	for _, result := range append(report.Tasks, report.DaySummary) {
+		fmt.Fprintf(stdout, "  - %s: %s\n", result.TaskID, result.Status)
+		if result.Integrity != nil && result.Integrity.Modified() {
+			files := make([]string, 0, len(result.Integrity.Mismatches))
+			for _, mismatch := range result.Integrity.Mismatches {
+				files = append(files, mismatch.File)
+			}
+			ctx.Logger.Warn("bundle inputs modified after bundling", "run_id", man.RunID, "task_id", result.TaskID, "files", strings.Join(files, ","))
+			fmt.Fprintf(stderr, "Warning: %s was edited after bundling (%s); its output is not comparable\n", result.TaskID, strings.Join(files, ", "))
+		}
+		if result.Evidence != nil && len(result.Evidence.Dangling) > 0 {
+			fmt.Fprintf(stdout, "    dangling evidence: %s\n", strings.Join(result.Evidence.Dangling, ", "))
+		}
+		if result.OK() {
+			continue
+		}
+		ctx.Logger.Warn("output rejected", "run_id", man.RunID, "task_id", result.TaskID, "status", result.Status, "errors", strings.Join(result.Errors, "; "))
+		for _, problem := range result.Errors {
+			fmt.Fprintf(stdout, "    %s\n", problem)
+		}
+	}
+	if report.DaySummary.Status == importer.StatusMissing {
+		fmt.Fprintf(stderr, "Warning: %s is missing; the report will only cover task outputs\n", report.DaySummary.Output)
 	}
+	fmt.Fprintf(stdout, "Import report: %s\n", filepath.Join(layout.ImportDir, importer.ReportFileName))
+	return nil
 }
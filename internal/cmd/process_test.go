@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/importer"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+	"github.com/offlinefirst/limitless-context/pkg/screenshots"
+	"github.com/offlinefirst/limitless-context/pkg/video"
+)
+
+func runFlags(t *testing.T, name, runID string) *flag.FlagSet {
+	t.Helper()
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.String("run", "", "")
+	if err := fs.Parse([]string{"-run", runID}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	return fs
+}
+
+func TestProcessCommandRecordsCorruptOutput(t *testing.T) {
+	ctx, runID := captureTestRun(t)
+	if err := runBundle(runFlags(t, "bundle", runID), nil, ctx, io.Discard, io.Discard); err != nil {
+		t.Fatalf("runBundle returned error: %v", err)
+	}
+	layout := runmanifest.BuildLayout(ctx.Config.Paths.RunsDir, runID)
+	if err := os.WriteFile(filepath.Join(layout.BundlesDir, "task_001", "output.json"), []byte(`{"task_id": "task_001",`), 0o644); err != nil {
+		t.Fatalf("write output: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := runProcess(runFlags(t, "process", runID), nil, ctx, &stdout, &stderr); err != nil {
+		t.Fatalf("runProcess returned error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "task_001: invalid") || !strings.Contains(stdout.String(), "day_summary: missing") {
+		t.Fatalf("unexpected process output:\n%s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "bundles/day_summary/output.json is missing") {
+		t.Fatalf("expected missing day summary warning, got %q", stderr.String())
+	}
+
+	report, err := importer.Load(filepath.Join(layout.ImportDir, importer.ReportFileName))
+	if err != nil {
+		t.Fatalf("load report: %v", err)
+	}
+	if report.RunID != runID || report.Tasks[0].Status != importer.StatusInvalid {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
+
+func TestProcessCommandListsDanglingEvidence(t *testing.T) {
+	ctx, runID := captureTestRun(t)
+	if err := runBundle(runFlags(t, "bundle", runID), nil, ctx, io.Discard, io.Discard); err != nil {
+		t.Fatalf("runBundle returned error: %v", err)
+	}
+	layout := runmanifest.BuildLayout(ctx.Config.Paths.RunsDir, runID)
+	cited := `{"task_id":"task_001","title":"Review","summary":"Read docs.","key_actions":[],"evidence":["event:evt_0001","event:evt_9999"],"confidence":0.5}`
+	if err := os.WriteFile(filepath.Join(layout.BundlesDir, "task_001", "output.json"), []byte(cited), 0o644); err != nil {
+		t.Fatalf("write output: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	if err := runProcess(runFlags(t, "process", runID), nil, ctx, &stdout, io.Discard); err != nil {
+		t.Fatalf("runProcess returned error: %v", err)
+	}
+	for _, want := range []string{"task_001: valid", "Evidence: 1 of 2 citation(s) resolved, 1 dangling", "dangling evidence: event:evt_9999"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, stdout.String())
+		}
+	}
+}
+
+func TestProcessCommandResolvesKeyframeShots(t *testing.T) {
+	ctx, runID := captureTestRun(t)
+	if err := runBundle(runFlags(t, "bundle", runID), nil, ctx, io.Discard, io.Discard); err != nil {
+		t.Fatalf("runBundle returned error: %v", err)
+	}
+	layout := runmanifest.BuildLayout(ctx.Config.Paths.RunsDir, runID)
+	// A keyframe 2m07s into the run, when no screenshot was taken.
+	frame, err := json.Marshal(screenshots.Metadata{CapturedAt: time.Date(2024, 5, 12, 9, 32, 7, 0, time.UTC), ImagePath: "frame_0001.png", Backend: video.KeyframeBackend})
+	if err != nil {
+		t.Fatalf("marshal keyframe: %v", err)
+	}
+	framesDir := filepath.Join(layout.VideoDir, video.FramesDirName)
+	if err := os.MkdirAll(framesDir, 0o755); err != nil {
+		t.Fatalf("mkdir frames: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(framesDir, "frame_0001.json"), frame, 0o644); err != nil {
+		t.Fatalf("write keyframe: %v", err)
+	}
+	cited := `{"task_id":"task_001","title":"Review","summary":"Read docs.","key_actions":[],"evidence":["shot:02:07"],"confidence":0.5}`
+	if err := os.WriteFile(filepath.Join(layout.BundlesDir, "task_001", "output.json"), []byte(cited), 0o644); err != nil {
+		t.Fatalf("write output: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	if err := runProcess(runFlags(t, "process", runID), nil, ctx, &stdout, io.Discard); err != nil {
+		t.Fatalf("runProcess returned error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Evidence: 1 of 1 citation(s) resolved, 0 dangling") {
+		t.Fatalf("expected the keyframe citation to resolve:\n%s", stdout.String())
+	}
+}
+
+func TestProcessCommandWarnsAboutEditedContext(t *testing.T) {
+	ctx, runID := captureTestRun(t)
+	if err := runBundle(runFlags(t, "bundle", runID), nil, ctx, io.Discard, io.Discard); err != nil {
+		t.Fatalf("runBundle returned error: %v", err)
+	}
+	layout := runmanifest.BuildLayout(ctx.Config.Paths.RunsDir, runID)
+	contextPath := filepath.Join(layout.BundlesDir, "task_001", "context.md")
+	file, err := os.OpenFile(contextPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open context: %v", err)
+	}
+	file.WriteString("- a note added by hand\n")
+	file.Close()
+
+	var stderr bytes.Buffer
+	if err := runProcess(runFlags(t, "process", runID), nil, ctx, io.Discard, &stderr); err != nil {
+		t.Fatalf("runProcess returned error: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "task_001 was edited after bundling (context.md)") {
+		t.Fatalf("expected tamper warning, got %q", stderr.String())
+	}
+	report, err := importer.Load(filepath.Join(layout.ImportDir, importer.ReportFileName))
+	if err != nil {
+		t.Fatalf("load report: %v", err)
+	}
+	if report.Summary.Modified != 1 || !report.Tasks[0].Integrity.Modified() {
+		t.Fatalf("expected modified task in report: %+v", report.Tasks[0].Integrity)
+	}
+}
+
+func TestProcessCommandRequiresBundles(t *testing.T) {
+	ctx, runID := captureTestRun(t)
+	err := runProcess(runFlags(t, "process", runID), nil, ctx, io.Discard, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "tester bundle") {
+		t.Fatalf("expected missing bundles error, got %v", err)
+	}
+}
@@ -1,9 +1,53 @@
 package cmd
 
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/offlinefirst/limitless-context/pkg/report"
+)
+
 func newReportCommand() command {
 	return command{
 		name:        "report",
 		description: "Render the HTML report for a processed run",
-		run:         stubRun("report"),
+		configure: func(fs *flag.FlagSet) {
+			fs.String("run", "", "Run identifier under runs_dir to report on")
+		},
+		run: runReport,
+	}
+}
+
+func runReport(fs *flag.FlagSet, args []string, ctx *AppContext, stdout io.Writer, stderr io.Writer) error {
+	if ctx == nil {
+		return fmt.Errorf("application context unavailable")
+	}
+
+	layout, man, err := loadRun(ctx, stringFlag(fs, "run"))
+	if err != nil {
+		return err
+	}
+	ctx.Logger.Info("report command invoked", "run_id", man.RunID, "root", layout.Root)
+
+	result, err := report.Generate(report.Options{Layout: layout, Manifest: man, Weights: ctx.Config.Report.Weights, RedactPatterns: ctx.Config.Capture.Events.RedactPatterns, Clock: timeNow})
+	if err != nil {
+		ctx.Logger.Error("report generation failed", "run_id", man.RunID, "error", err)
+		return fmt.Errorf("generate report: %w", err)
+	}
+	ctx.Logger.Info("report written", "run_id", man.RunID, "html", result.HTMLPath, "warnings", len(result.Report.Warnings))
+
+	fmt.Fprintf(stdout, "Report for run %s: %d task(s), %d total tokens\n", man.RunID, len(result.Report.Tasks), result.Report.TokenTotals.Total)
+	for _, row := range result.Report.Scorecard.Modes {
+		if row.Total != nil {
+			fmt.Fprintf(stdout, "  %s: weighted score %.0f\n", row.Mode, *row.Total)
+		}
+	}
+	for _, warning := range result.Report.Warnings {
+		fmt.Fprintf(stderr, "Warning: %s\n", warning)
 	}
+	fmt.Fprintf(stdout, "HTML: %s\n", result.HTMLPath)
+	fmt.Fprintf(stdout, "JSON: %s\n", result.JSONPath)
+	fmt.Fprintf(stdout, "Privacy scan: %s\n", result.PrivacyPath)
+	return nil
 }
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+func TestReportCommandRendersProcessedRun(t *testing.T) {
+	ctx, runID := captureTestRun(t)
+	if err := runBundle(runFlags(t, "bundle", runID), nil, ctx, io.Discard, io.Discard); err != nil {
+		t.Fatalf("runBundle returned error: %v", err)
+	}
+	if err := runProcess(runFlags(t, "process", runID), nil, ctx, io.Discard, io.Discard); err != nil {
+		t.Fatalf("runProcess returned error: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := runReport(runFlags(t, "report", runID), nil, ctx, &stdout, &stderr); err != nil {
+		t.Fatalf("runReport returned error: %v", err)
+	}
+	layout := runmanifest.BuildLayout(ctx.Config.Paths.RunsDir, runID)
+	page, err := os.ReadFile(filepath.Join(layout.ReportDir, "report.html"))
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	if !strings.Contains(string(page), "Run "+runID) || !strings.Contains(string(page), "task_001") {
+		t.Fatalf("unexpected report.html")
+	}
+	if !strings.Contains(stdout.String(), "Report for run "+runID) || !strings.Contains(stdout.String(), "report.json") {
+		t.Fatalf("unexpected stdout: %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "Hybrid: weighted score") {
+		t.Fatalf("expected scorecard totals, got %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "day summary output is missing") {
+		t.Fatalf("expected missing day summary warning, got %q", stderr.String())
+	}
+}
+
+func TestReportCommandRejectsUnknownWeight(t *testing.T) {
+	ctx, runID := captureTestRun(t)
+	ctx.Config.Report.Weights = map[string]int{"speed": 10}
+	err := runReport(runFlags(t, "report", runID), nil, ctx, io.Discard, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "report.weights.speed") {
+		t.Fatalf("expected unknown weight error, got %v", err)
+	}
+}
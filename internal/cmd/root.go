@@ -49,9 +49,15 @@ func NewRootCommand() *RootCommand {
 
 	rc.register(newBootstrapCommand())
 	rc.register(newRunCommand())
+	rc.register(newStatusCommand())
+	rc.register(newPauseCommand())
+	rc.register(newResumeCommand())
+	rc.register(newStopCommand())
 	rc.register(newBundleCommand())
+	rc.register(newDaySummaryCommand())
 	rc.register(newProcessCommand())
 	rc.register(newReportCommand())
+	rc.register(newPrivacyScanCommand())
 	rc.register(newCleanCommand())
 	rc.register(newDoctorCommand())
 	rc.register(newVersionCommand())
@@ -183,7 +189,7 @@ func (rc *RootCommand) printHelp() {
 	sort.Strings(names)
 
 	for _, name := range names {
-		fmt.Fprintf(rc.stdout, "  %-10s %s\n", name, rc.commands[name].description)
+		fmt.Fprintf(rc.stdout, "  %-13s %s\n", name, rc.commands[name].description)
 	}
 }
 
@@ -11,6 +11,7 @@ import (
 
 	"github.com/offlinefirst/limitless-context/internal/buildinfo"
 	"github.com/offlinefirst/limitless-context/pkg/capture"
+	"github.com/offlinefirst/limitless-context/pkg/control"
 	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
 )
 
@@ -82,12 +83,30 @@ func runCapture(fs *flag.FlagSet, args []string, ctx *AppContext, stdout io.Writ
 		return fmt.Errorf("update manifest status: %w", err)
 	}
 
+	controller := capture.NewController()
+	stopWatching := watchSignals(ctx, controller, stderr)
+	defer stopWatching()
+	server, err := control.Listen(control.SocketPath(layout), controller)
+	if err != nil {
+		ctx.Logger.Warn("control socket unavailable; pause/resume/stop disabled for this run", "error", err)
+		fmt.Fprintf(stderr, "Warning: %v; tester pause/resume/stop will not reach this run\n", err)
+	} else {
+		ctx.Logger.Info("control socket listening", "path", server.Path())
+		fmt.Fprintf(stdout, "Capturing run %s; control it with tester pause|resume|stop --run %s\n", runID, runID)
+	}
+
 	summary, err := capture.Run(context.Background(), capture.Options{
-		Config: ctx.Config,
-		Layout: layout,
-		Logger: ctx.Logger,
-		Clock:  timeNow,
+		Config:  ctx.Config,
+		Layout:  layout,
+		Logger:  ctx.Logger,
+		Clock:   timeNow,
+		Control: controller,
 	})
+	if server != nil {
+		if closeErr := server.Close(); closeErr != nil {
+			ctx.Logger.Warn("close control socket", "error", closeErr)
+		}
+	}
 
 	if summary.Lifecycle != nil {
 		started := summary.Lifecycle.StartedAt.UTC()
@@ -103,6 +122,8 @@ func runCapture(fs *flag.FlagSet, args []string, ctx *AppContext, stdout io.Writ
 		manifest.Status.Subsystems = append([]runmanifest.SubsystemStatus(nil), summary.Subsystems...)
 	}
 
+	recordStorage(ctx, layout, &manifest)
+
 	if err != nil {
 		manifest.Status.State = "failed"
 		manifest.Status.Summary = err.Error()
@@ -137,6 +158,7 @@ func runCapture(fs *flag.FlagSet, args []string, ctx *AppContext, stdout io.Writ
 	fmt.Fprintf(stdout, "  asr: %s\n", layout.ASRDir)
 	fmt.Fprintf(stdout, "  ocr: %s\n", layout.OCRDir)
 	fmt.Fprintf(stdout, "  bundles: %s\n", layout.BundlesDir)
+	fmt.Fprintf(stdout, "  import: %s\n", layout.ImportDir)
 	fmt.Fprintf(stdout, "  report: %s\n", layout.ReportDir)
 
 	if len(summary.Subsystems) > 0 {
@@ -170,7 +192,10 @@ func runCapture(fs *flag.FlagSet, args []string, ctx *AppContext, stdout io.Writ
 	}
 
 	if summary.Video != nil {
-		fmt.Fprintf(stdout, "Video: segment recorded -> %s\n", summary.Video.File)
+		fmt.Fprintf(stdout, "Video: %d segments recorded -> %s\n", len(summary.Video.Segments), summary.Video.IndexPath)
+		if summary.Keyframes != nil {
+			fmt.Fprintf(stdout, "  keyframes: %d extracted -> %s\n", len(summary.Keyframes.Files), summary.Keyframes.Dir)
+		}
 	} else {
 		fmt.Fprintln(stdout, "Video: disabled via config")
 	}
@@ -212,9 +237,32 @@ func runCapture(fs *flag.FlagSet, args []string, ctx *AppContext, stdout io.Writ
 		}
 	}
 
+	if storage := manifest.Storage; storage != nil {
+		fmt.Fprintf(stdout, "Storage: %s in %d files", runmanifest.FormatBytes(storage.Total.Bytes), storage.Total.Files)
+		if storage.BytesPerHour > 0 {
+			fmt.Fprintf(stdout, " (about %s per hour of capture)", runmanifest.FormatBytes(storage.BytesPerHour))
+		}
+		fmt.Fprintln(stdout)
+		for _, entry := range storage.Subsystems {
+			fmt.Fprintf(stdout, "  %s: %s in %d files\n", entry.Name, runmanifest.FormatBytes(entry.Bytes), entry.Files)
+		}
+	}
+
 	return nil
 }
 
+// recordStorage measures the run's footprint into the manifest. A failed
+// measurement is logged rather than failing the run.
+func recordStorage(ctx *AppContext, layout runmanifest.Layout, manifest *runmanifest.Manifest) {
+	storage, err := runmanifest.MeasureStorage(layout, *manifest, timeNow())
+	if err != nil {
+		ctx.Logger.Warn("storage measurement failed", "run_id", manifest.RunID, "error", err)
+		return
+	}
+	manifest.Storage = &storage
+	ctx.Logger.Info("storage measured", "run_id", manifest.RunID, "bytes", storage.Total.Bytes, "files", storage.Total.Files, "bytes_per_hour", storage.BytesPerHour)
+}
+
 func printRunPlan(ctx *AppContext, stdout io.Writer) {
 	fmt.Fprintf(stdout, "Resolved configuration (source: %s)\n", ctx.Config.Source)
 	fmt.Fprintf(stdout, "  runs_dir: %s\n", ctx.Config.Paths.RunsDir)
@@ -239,3 +287,23 @@ func boolFlag(fs *flag.FlagSet, name string) bool {
 	}
 	return value
 }
+
+func stringFlag(fs *flag.FlagSet, name string) string {
+	f := fs.Lookup(name)
+	if f == nil {
+		return ""
+	}
+	return f.Value.String()
+}
+
+func durationFlag(fs *flag.FlagSet, name string) time.Duration {
+	f := fs.Lookup(name)
+	if f == nil {
+		return 0
+	}
+	value, err := time.ParseDuration(f.Value.String())
+	if err != nil {
+		return 0
+	}
+	return value
+}
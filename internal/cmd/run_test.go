@@ -92,7 +92,7 @@ func TestRunCommandPreparesLayout(t *testing.T) {
 		t.Fatalf("expected lifecycle timestamps in manifest")
 	}
 
-	for _, dir := range []string{layout.VideoDir, layout.EventsDir, layout.ScreensDir, layout.ASRDir, layout.OCRDir, layout.BundlesDir, layout.ReportDir} {
+	for _, dir := range []string{layout.VideoDir, layout.EventsDir, layout.ScreensDir, layout.ASRDir, layout.OCRDir, layout.BundlesDir, layout.ImportDir, layout.ReportDir} {
 		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
 			t.Fatalf("expected directory %s: %v", dir, err)
 		}
@@ -126,6 +126,12 @@ func TestRunCommandPreparesLayout(t *testing.T) {
 	if len(man.Status.Controller) == 0 {
 		t.Fatalf("expected controller timeline persisted to manifest")
 	}
+	if man.Storage == nil || man.Storage.Total.Files == 0 || man.Storage.Subsystem("events").Files == 0 {
+		t.Fatalf("expected storage footprint persisted to manifest: %+v", man.Storage)
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte("Storage: ")) || !bytes.Contains(stdout.Bytes(), []byte("  events: ")) {
+		t.Fatalf("expected storage summary in output, got %q", stdout.String())
+	}
 }
 
 func installCmdVideoFake(t *testing.T) {
@@ -137,6 +143,13 @@ func installCmdVideoFake(t *testing.T) {
 
 type cmdFakeRecorder struct {
 	format string
+	last   time.Duration
+}
+
+// LastDuration reports the requested length: the fake writes instantly, so
+// the clock cannot measure it.
+func (f *cmdFakeRecorder) LastDuration() time.Duration {
+	return f.last
 }
 
 func (f *cmdFakeRecorder) Record(ctx context.Context, dest, filename string, started time.Time, duration time.Duration) (string, error) {
@@ -153,5 +166,6 @@ func (f *cmdFakeRecorder) Record(ctx context.Context, dest, filename string, sta
 	if err := os.WriteFile(path, []byte("cmd fake video"), 0o644); err != nil {
 		return "", err
 	}
+	f.last = duration
 	return path, nil
 }
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+// loadRun resolves a run identifier under the configured runs directory and reads its manifest.
+func loadRun(ctx *AppContext, runID string) (runmanifest.Layout, runmanifest.Manifest, error) {
+	runID = strings.TrimSpace(runID)
+	if runID == "" {
+		return runmanifest.Layout{}, runmanifest.Manifest{}, errors.New("--run is required")
+	}
+
+	layout := runmanifest.BuildLayout(ctx.Config.Paths.RunsDir, runID)
+	if info, err := os.Stat(layout.Root); err != nil || !info.IsDir() {
+		return runmanifest.Layout{}, runmanifest.Manifest{}, fmt.Errorf("run %q not found under %s", runID, ctx.Config.Paths.RunsDir)
+	}
+
+	man, err := runmanifest.Load(layout.ManifestPath)
+	if err != nil {
+		return runmanifest.Layout{}, runmanifest.Manifest{}, fmt.Errorf("load run %q: %w", runID, err)
+	}
+	return layout, man, nil
+}
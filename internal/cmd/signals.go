@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/offlinefirst/limitless-context/pkg/capture"
+)
+
+var (
+	signalNotify = signal.Notify
+	signalStop   = signal.Stop
+	forceExit    = os.Exit
+)
+
+// watchSignals turns the first SIGINT or SIGTERM into an orderly interrupt
+// of the capture so subsystems flush and the manifest is finalised. A second
+// signal exits immediately. The returned function stops watching.
+func watchSignals(ctx *AppContext, controller *capture.Controller, stderr io.Writer) func() {
+	signals := make(chan os.Signal, 2)
+	signalNotify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		interrupted := false
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				name := signalName(sig)
+				if !interrupted {
+					interrupted = true
+					ctx.Logger.Warn("signal received; stopping capture", "signal", name)
+					fmt.Fprintf(stderr, "Received %s; finishing capture and writing the manifest. Send it again to exit immediately.\n", name)
+					controller.Interrupt("received " + name)
+					continue
+				}
+				ctx.Logger.Error("second signal received; exiting without finalising the run", "signal", name)
+				fmt.Fprintf(stderr, "Received %s again; exiting without finalising the run.\n", name)
+				forceExit(signalExitCode(sig))
+				return
+			}
+		}
+	}()
+	return func() {
+		signalStop(signals)
+		close(done)
+		<-finished
+	}
+}
+
+func signalName(sig os.Signal) string {
+	switch sig {
+	case os.Interrupt:
+		return "SIGINT"
+	case syscall.SIGTERM:
+		return "SIGTERM"
+	default:
+		return sig.String()
+	}
+}
+
+// signalExitCode follows the shell convention of 128 plus the signal number.
+func signalExitCode(sig os.Signal) int {
+	if sig == syscall.SIGTERM {
+		return 128 + int(syscall.SIGTERM)
+	}
+	return 128 + int(syscall.SIGINT)
+}
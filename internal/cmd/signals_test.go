@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/capture"
+	"github.com/offlinefirst/limitless-context/pkg/config"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+	"github.com/offlinefirst/limitless-context/pkg/video"
+)
+
+// fakeSignals captures the channel registered by watchSignals so tests can
+// deliver signals without touching the test process.
+func fakeSignals(t *testing.T, onNotify func(chan<- os.Signal)) {
+	t.Helper()
+	origNotify, origStop := signalNotify, signalStop
+	signalNotify = func(c chan<- os.Signal, _ ...os.Signal) { onNotify(c) }
+	signalStop = func(chan<- os.Signal) {}
+	t.Cleanup(func() { signalNotify, signalStop = origNotify, origStop })
+}
+
+func TestWatchSignalsInterruptsThenForcesExit(t *testing.T) {
+	var signals chan<- os.Signal
+	fakeSignals(t, func(c chan<- os.Signal) { signals = c })
+	exits := make(chan int, 1)
+	origExit := forceExit
+	forceExit = func(code int) { exits <- code }
+	t.Cleanup(func() { forceExit = origExit })
+
+	controller := capture.NewController()
+	changes, cancel := controller.Subscribe()
+	defer cancel()
+	<-changes
+
+	var stderr bytes.Buffer
+	stop := watchSignals(&AppContext{Config: config.Default(), Logger: newTestLogger()}, controller, &stderr)
+	signals <- os.Interrupt
+	if change := <-changes; change.State != "stopping" || change.Reason != "received SIGINT" {
+		t.Fatalf("unexpected transition: %+v", change)
+	}
+	if !errors.Is(controller.Err(), capture.ErrInterrupted) {
+		t.Fatalf("expected ErrInterrupted, got %v", controller.Err())
+	}
+	signals <- syscall.SIGTERM
+	if code := <-exits; code != 143 {
+		t.Fatalf("expected exit code 143, got %d", code)
+	}
+	stop()
+
+	out := stderr.String()
+	for _, want := range []string{"Received SIGINT; finishing capture", "Received SIGTERM again; exiting"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("stderr missing %q:\n%s", want, out)
+		}
+	}
+}
+
+// blockingRecorder records until capture is stopped, running onStart once
+// recording begins.
+type blockingRecorder struct {
+	onStart func()
+}
+
+func (r blockingRecorder) Record(ctx context.Context, dest, filename string, started time.Time, duration time.Duration) (string, error) {
+	r.onStart()
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestRunCaptureFinalisesManifestOnInterrupt(t *testing.T) {
+	var signals chan<- os.Signal
+	fakeSignals(t, func(c chan<- os.Signal) { signals = c })
+	video.SetNativeFactory(func(string) (video.NativeRecorder, error) {
+		return blockingRecorder{onStart: func() { signals <- os.Interrupt }}, nil
+	})
+	t.Cleanup(func() { video.SetNativeFactory(nil) })
+
+	cfg := config.Default()
+	cfg.Paths.RunsDir = t.TempDir()
+	ctx := &AppContext{Config: cfg, Logger: newTestLogger()}
+
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	newRunCommand().configure(fs)
+	var stderr bytes.Buffer
+	if err := runCapture(fs, nil, ctx, io.Discard, &stderr); err != nil {
+		t.Fatalf("runCapture returned error: %v", err)
+	}
+
+	runID, err := runmanifest.LatestRunID(cfg.Paths.RunsDir)
+	if err != nil {
+		t.Fatalf("latest run: %v", err)
+	}
+	layout := runmanifest.BuildLayout(cfg.Paths.RunsDir, runID)
+	man, err := runmanifest.Load(layout.ManifestPath)
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+	if man.Status.State != "completed" || man.Status.Termination != "interrupted" {
+		t.Fatalf("expected a completed, interrupted run, got %+v", man.Status)
+	}
+	if _, err := os.Stat(filepath.Join(layout.EventsDir, "events_coarse.json")); err != nil {
+		t.Fatalf("expected events to be flushed: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "Received SIGINT") {
+		t.Fatalf("expected interrupt notice, got %q", stderr.String())
+	}
+}
+
+func TestSignalNames(t *testing.T) {
+	if signalName(os.Interrupt) != "SIGINT" || signalName(syscall.SIGTERM) != "SIGTERM" {
+		t.Fatalf("unexpected signal names")
+	}
+	if signalExitCode(os.Interrupt) != 130 {
+		t.Fatalf("unexpected SIGINT exit code")
+	}
+}
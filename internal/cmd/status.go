@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/control"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+	"github.com/offlinefirst/limitless-context/pkg/runstatus"
+)
+
+// statusSleep waits between --watch refreshes; tests replace it.
+var statusSleep = time.Sleep
+
+// statusFinishGrace is how long past its deadline a run may stay marked
+// running while capture shuts down and writes its final manifest.
+const statusFinishGrace = 10 * time.Minute
+
+func newStatusCommand() command {
+	return command{
+		name:        "status",
+		description: "Show the live state of the latest or a named run",
+		configure: func(fs *flag.FlagSet) {
+			fs.String("run", "", "Run identifier under runs_dir (default: the latest run)")
+			fs.Bool("watch", false, "Refresh until the run finishes")
+			fs.Duration("interval", 2*time.Second, "Refresh interval for --watch")
+		},
+		run: runStatus,
+	}
+}
+
+func runStatus(fs *flag.FlagSet, args []string, ctx *AppContext, stdout io.Writer, stderr io.Writer) error {
+	if ctx == nil {
+		return fmt.Errorf("application context unavailable")
+	}
+
+	runID, err := resolveRunID(ctx, stringFlag(fs, "run"))
+	if err != nil {
+		return err
+	}
+	watch := boolFlag(fs, "watch")
+	interval := durationFlag(fs, "interval")
+	if watch && interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	ctx.Logger.Info("status command invoked", "run_id", runID, "watch", watch)
+
+	for refresh := 0; ; refresh++ {
+		layout, man, err := loadRun(ctx, runID)
+		if err != nil {
+			return err
+		}
+		snap, err := runstatus.Read(layout, man, timeNow())
+		if err != nil {
+			return fmt.Errorf("read run status: %w", err)
+		}
+		if watch && refresh > 0 {
+			clearScreen(stdout)
+		}
+		printStatus(stdout, snap)
+		if !watch || !snap.Active() {
+			return nil
+		}
+		if err := checkCaptureAlive(layout, snap, timeNow()); err != nil {
+			return err
+		}
+		statusSleep(interval)
+	}
+}
+
+// checkCaptureAlive reports a run whose manifest still says running although
+// its capture process is gone, which would otherwise keep --watch polling
+// forever. The checks wait until capture has logged its start, since the
+// control socket only opens just before then.
+func checkCaptureAlive(layout runmanifest.Layout, snap runstatus.Snapshot, now time.Time) error {
+	if snap.State != "running" || snap.StartedAt == nil {
+		return nil
+	}
+	if snap.Deadline != nil && now.After(snap.Deadline.Add(statusFinishGrace)) {
+		return fmt.Errorf("run %s is still marked running %s after its deadline; its capture process probably exited without finishing", snap.RunID, now.Sub(*snap.Deadline).Round(time.Second))
+	}
+	_, err := control.Send(control.SocketPath(layout), control.Request{Action: control.ActionState})
+	if errors.Is(err, control.ErrNotRunning) {
+		return fmt.Errorf("run %s is marked running but its control socket is unreachable; its capture process probably exited without finishing: %w", snap.RunID, err)
+	}
+	return nil
+}
+
+func printStatus(w io.Writer, snap runstatus.Snapshot) {
+	fmt.Fprintf(w, "Run %s: %s", snap.RunID, snap.State)
+	if snap.Controller != "" {
+		fmt.Fprintf(w, " (controller %s", snap.Controller)
+		if snap.ControllerReason != "" {
+			fmt.Fprintf(w, ": %s", snap.ControllerReason)
+		}
+		fmt.Fprint(w, ")")
+	}
+	fmt.Fprintln(w)
+
+	if snap.StartedAt != nil {
+		fmt.Fprintf(w, "Elapsed: %s since %s", snap.Elapsed.Round(time.Second), snap.StartedAt.Format(time.RFC3339))
+		switch {
+		case snap.Remaining != nil:
+			fmt.Fprintf(w, ", %s remaining (deadline %s)", snap.Remaining.Round(time.Second), snap.Deadline.Format(time.RFC3339))
+		case snap.Active() && snap.Deadline == nil:
+			fmt.Fprint(w, ", no duration limit")
+		}
+		fmt.Fprintln(w)
+	} else {
+		fmt.Fprintln(w, "Elapsed: capture has not started")
+	}
+
+	fmt.Fprintln(w, "Subsystems:")
+	for _, sub := range snap.Subsystems {
+		fmt.Fprintf(w, "  %s: %s", sub.Name, sub.State)
+		if sub.Message != "" {
+			fmt.Fprintf(w, " (%s)", sub.Message)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "Artifacts: %s in %d files\n", runmanifest.FormatBytes(snap.Artifacts.Total.Bytes), snap.Artifacts.Total.Files)
+	for _, entry := range snap.Artifacts.Subsystems {
+		fmt.Fprintf(w, "  %s: %d files, %s\n", entry.Name, entry.Files, runmanifest.FormatBytes(entry.Bytes))
+	}
+	if entry := snap.LastLog; entry != nil {
+		fmt.Fprintf(w, "Last log: [%s] %s %s\n", entry.Timestamp.Format(time.RFC3339), entry.Subsystem, entry.Message)
+	}
+}
+
+// clearScreen redraws --watch output in place on a terminal and leaves
+// redirected output as a plain sequence of snapshots.
+func clearScreen(w io.Writer) {
+	if file, ok := w.(*os.File); ok {
+		if info, err := file.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			fmt.Fprint(w, "\033[H\033[2J")
+			return
+		}
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/capture"
+	"github.com/offlinefirst/limitless-context/pkg/control"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+func statusFlags(t *testing.T, args ...string) *flag.FlagSet {
+	t.Helper()
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	newStatusCommand().configure(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	return fs
+}
+
+func TestStatusCommandDefaultsToLatestRun(t *testing.T) {
+	ctx, runID := captureTestRun(t)
+
+	var stdout bytes.Buffer
+	if err := runStatus(statusFlags(t), nil, ctx, &stdout, &stdout); err != nil {
+		t.Fatalf("runStatus returned error: %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{"Run " + runID + ": completed", "Subsystems:", "  screenshots: completed", "Artifacts: ", "Last log: "} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("status output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+// liveStatusRun captures a run and rewinds its manifest and capture.log to
+// mid-capture, returning the finished manifest for the test to restore. The
+// run moves to a short temp directory so a control socket fits in it.
+func liveStatusRun(t *testing.T) (*AppContext, runmanifest.Layout, runmanifest.Manifest) {
+	t.Helper()
+	ctx, runID := captureTestRun(t)
+	runsDir, err := os.MkdirTemp("", "runs")
+	if err != nil {
+		t.Fatalf("mkdir temp: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(runsDir) })
+	if err := os.Rename(filepath.Join(ctx.Config.Paths.RunsDir, runID), filepath.Join(runsDir, runID)); err != nil {
+		t.Fatalf("move run: %v", err)
+	}
+	ctx.Config.Paths.RunsDir = runsDir
+
+	layout := runmanifest.BuildLayout(runsDir, runID)
+	final, err := runmanifest.Load(layout.ManifestPath)
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+	live := final
+	live.Status = runmanifest.Status{State: "running", Summary: "capture in progress"}
+	if err := runmanifest.Save(live, layout.ManifestPath); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	if err := os.WriteFile(layout.CaptureLogPath, []byte("[2024-05-12T09:30:00Z] subsystem=controller state=running reason=initial\n[2024-05-12T09:30:00Z] subsystem=controller run_started duration=1h0m0s (deadline=2024-05-12T10:30:00Z)\n"), 0o644); err != nil {
+		t.Fatalf("write capture log: %v", err)
+	}
+	return ctx, layout, final
+}
+
+// listenForStatus stands in for the capture process's control socket.
+func listenForStatus(t *testing.T, layout runmanifest.Layout) {
+	t.Helper()
+	server, err := control.Listen(control.SocketPath(layout), capture.NewController())
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+}
+
+func TestStatusCommandWatchesUntilRunFinishes(t *testing.T) {
+	ctx, layout, final := liveStatusRun(t)
+	runID := final.RunID
+	listenForStatus(t, layout)
+
+	var sleeps []time.Duration
+	orig := statusSleep
+	statusSleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+		if err := runmanifest.Save(final, layout.ManifestPath); err != nil {
+			t.Fatalf("save manifest: %v", err)
+		}
+	}
+	t.Cleanup(func() { statusSleep = orig })
+
+	var stdout bytes.Buffer
+	if err := runStatus(statusFlags(t, "-run", runID, "-watch", "-interval", "5s"), nil, ctx, &stdout, &stdout); err != nil {
+		t.Fatalf("runStatus returned error: %v", err)
+	}
+	out := stdout.String()
+	if len(sleeps) != 1 || sleeps[0] != 5*time.Second {
+		t.Fatalf("expected one 5s refresh, got %v", sleeps)
+	}
+	for _, want := range []string{"Run " + runID + ": running (controller running: initial)", "1h0m0s remaining", "  video: running", "Run " + runID + ": completed"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("watch output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestStatusWatchStopsWhenCaptureIsGone(t *testing.T) {
+	ctx, layout, final := liveStatusRun(t)
+	orig := statusSleep
+	statusSleep = func(time.Duration) { t.Fatal("watch kept polling a run with no capture process") }
+	t.Cleanup(func() { statusSleep = orig })
+
+	var stdout bytes.Buffer
+	err := runStatus(statusFlags(t, "-run", final.RunID, "-watch"), nil, ctx, &stdout, &stdout)
+	if !errors.Is(err, control.ErrNotRunning) || !strings.Contains(err.Error(), "control socket is unreachable") {
+		t.Fatalf("expected an unreachable control socket error, got %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Run "+final.RunID+": running") {
+		t.Fatalf("expected the snapshot before stopping:\n%s", stdout.String())
+	}
+
+	listenForStatus(t, layout)
+	origTime := timeNow
+	timeNow = func() time.Time {
+		return time.Date(2024, 5, 12, 10, 30, 0, 0, time.UTC).Add(statusFinishGrace + time.Minute)
+	}
+	t.Cleanup(func() { timeNow = origTime })
+	err = runStatus(statusFlags(t, "-run", final.RunID, "-watch"), nil, ctx, &stdout, &stdout)
+	if err == nil || !strings.Contains(err.Error(), "still marked running 11m0s after its deadline") {
+		t.Fatalf("expected an overdue run error, got %v", err)
+	}
+}
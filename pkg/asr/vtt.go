@@ -0,0 +1,140 @@
+package asr
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cue is a single timed transcript segment from a WebVTT file.
+type Cue struct {
+	Index int
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// ParseVTT decodes the subset of WebVTT emitted by the agent: a header
+// followed by blank-line separated cues with optional numeric identifiers.
+func ParseVTT(r io.Reader) ([]Cue, error) {
+	if r == nil {
+		return nil, errors.New("reader must not be nil")
+	}
+	scanner := bufio.NewScanner(r)
+
+	var cues []Cue
+	var current *Cue
+	var text []string
+	headerSeen := false
+	lineNo := 0
+
+	flush := func() {
+		if current != nil {
+			current.Text = strings.TrimSpace(strings.Join(text, " "))
+			cues = append(cues, *current)
+		}
+		current = nil
+		text = text[:0]
+	}
+
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if !headerSeen {
+			if line == "" {
+				continue
+			}
+			if !strings.HasPrefix(line, "WEBVTT") {
+				return nil, fmt.Errorf("line %d: missing WEBVTT header", lineNo)
+			}
+			headerSeen = true
+			continue
+		}
+		if line == "" {
+			flush()
+			continue
+		}
+		if strings.Contains(line, "-->") {
+			flush()
+			start, end, err := parseCueTiming(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			current = &Cue{Index: len(cues) + 1, Start: start, End: end}
+			continue
+		}
+		if current == nil {
+			// Cue identifiers and NOTE blocks precede the timing line.
+			continue
+		}
+		text = append(text, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read vtt: %w", err)
+	}
+	if !headerSeen {
+		return nil, errors.New("missing WEBVTT header")
+	}
+	flush()
+	return cues, nil
+}
+
+// ReadVTT loads and parses a WebVTT transcript from disk.
+func ReadVTT(path string) ([]Cue, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open transcript: %w", err)
+	}
+	defer file.Close()
+	return ParseVTT(file)
+}
+
+func parseCueTiming(line string) (time.Duration, time.Duration, error) {
+	parts := strings.SplitN(line, "-->", 2)
+	start, err := parseTimestamp(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, err
+	}
+	endField := strings.Fields(strings.TrimSpace(parts[1]))
+	if len(endField) == 0 {
+		return 0, 0, errors.New("cue end timestamp missing")
+	}
+	end, err := parseTimestamp(endField[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("cue ends before it starts (%s --> %s)", parts[0], endField[0])
+	}
+	return start, end, nil
+}
+
+func parseTimestamp(value string) (time.Duration, error) {
+	fields := strings.Split(value, ":")
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, fmt.Errorf("invalid cue timestamp %q", value)
+	}
+	var hours, minutes int
+	var err error
+	if len(fields) == 3 {
+		if hours, err = strconv.Atoi(fields[0]); err != nil {
+			return 0, fmt.Errorf("invalid cue timestamp %q", value)
+		}
+		fields = fields[1:]
+	}
+	if minutes, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, fmt.Errorf("invalid cue timestamp %q", value)
+	}
+	seconds, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cue timestamp %q", value)
+	}
+	total := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	total += time.Duration(seconds * float64(time.Second))
+	return total.Round(time.Millisecond), nil
+}
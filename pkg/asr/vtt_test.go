@@ -0,0 +1,62 @@
+package asr
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseVTT(t *testing.T) {
+	input := "WEBVTT\n\n1\n00:00:00.000 --> 00:00:05.000\nTeam sync kicks off\nwith launch checklist.\n\n2\n00:05.500 --> 00:00:10.000 align:start\nAction item: send recap.\n"
+
+	cues, err := ParseVTT(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parse vtt: %v", err)
+	}
+	if len(cues) != 2 {
+		t.Fatalf("expected 2 cues, got %d", len(cues))
+	}
+	if cues[0].Text != "Team sync kicks off with launch checklist." {
+		t.Fatalf("unexpected first cue text %q", cues[0].Text)
+	}
+	if cues[1].Start != 5500*time.Millisecond || cues[1].End != 10*time.Second {
+		t.Fatalf("unexpected second cue timing %s --> %s", cues[1].Start, cues[1].End)
+	}
+	if cues[1].Index != 2 {
+		t.Fatalf("expected sequential cue index, got %d", cues[1].Index)
+	}
+}
+
+func TestParseVTTRejectsMissingHeader(t *testing.T) {
+	if _, err := ParseVTT(strings.NewReader("00:00:00.000 --> 00:00:01.000\nhello\n")); err == nil {
+		t.Fatalf("expected error for missing header")
+	}
+	if _, err := ParseVTT(strings.NewReader("WEBVTT\n\n00:00:02.000 --> 00:00:01.000\nbackwards\n")); err == nil {
+		t.Fatalf("expected error for inverted cue timing")
+	}
+}
+
+func TestReadVTTParsesAgentTranscript(t *testing.T) {
+	dir := t.TempDir()
+	agent, err := NewAgent(Options{
+		MeetingKeywords: []string{"Zoom"},
+		WindowTitles:    []string{"Weekly Sync - Zoom"},
+		LookPath:        func(string) (string, error) { return "/usr/local/bin/whisper", nil },
+	})
+	if err != nil {
+		t.Fatalf("new agent: %v", err)
+	}
+	result, err := agent.Capture(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+
+	cues, err := ReadVTT(result.TranscriptPath)
+	if err != nil {
+		t.Fatalf("read vtt: %v", err)
+	}
+	if len(cues) != result.SegmentCount {
+		t.Fatalf("expected %d cues, got %d", result.SegmentCount, len(cues))
+	}
+}
@@ -0,0 +1,91 @@
+package bundle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+	"github.com/offlinefirst/limitless-context/pkg/tokenizer"
+)
+
+// Item kinds in descending priority; trimming drops ASR first and events last.
+const (
+	kindEvent = "event"
+	kindOCR   = "ocr"
+	kindASR   = "asr"
+)
+
+// assembledContext is the rendered context.md after trimming.
+type assembledContext struct {
+	Doc        string
+	Tokens     int
+	FullTokens int
+	Kept       *task
+	Dropped    []runmanifest.BundleDroppedItem
+}
+
+// assembleContext renders context.md for t and, when it exceeds budget tokens,
+// drops ASR cues, then OCR entries, then events, latest first within each
+// section, until the measured document fits or nothing is left to drop.
+func assembleContext(runID string, base time.Time, t *task, tok *tokenizer.Tokenizer, budget int) assembledContext {
+	doc := renderContext(runID, base, t)
+	full := tok.Count(doc)
+	out := assembledContext{Doc: doc, Tokens: full, FullTokens: full, Kept: t, Dropped: []runmanifest.BundleDroppedItem{}}
+	if full <= budget {
+		return out
+	}
+
+	kept := *t
+	kept.Events = append([]eventRecord(nil), t.Events...)
+	kept.OCR = append([]ocrRecord(nil), t.OCR...)
+	kept.ASR = append([]asrRecord(nil), t.ASR...)
+	reason := fmt.Sprintf("context measured %d tokens against per_task_token_budget %d; items are dropped asr before ocr before events, latest first", full, budget)
+
+	// Subtracting per-line counts is only an estimate because pieces can span
+	// line boundaries, so every pass is confirmed by re-measuring the document.
+	estimate := full
+	for {
+		for estimate > budget {
+			item, ok := dropLowest(&kept, base, tok)
+			if !ok {
+				break
+			}
+			item.Reason = reason
+			out.Dropped = append(out.Dropped, item)
+			estimate -= item.Tokens
+		}
+		kept.Omitted = len(out.Dropped)
+		doc = renderContext(runID, base, &kept)
+		out.Tokens = tok.Count(doc)
+		if out.Tokens <= budget || len(kept.Events)+len(kept.OCR)+len(kept.ASR) == 0 {
+			break
+		}
+		estimate = out.Tokens
+	}
+	out.Doc = doc
+	out.Kept = &kept
+	return out
+}
+
+// dropLowest removes the latest item of the lowest-priority non-empty section.
+func dropLowest(t *task, base time.Time, tok *tokenizer.Tokenizer) (runmanifest.BundleDroppedItem, bool) {
+	switch {
+	case len(t.ASR) > 0:
+		cue := t.ASR[len(t.ASR)-1]
+		t.ASR = t.ASR[:len(t.ASR)-1]
+		return runmanifest.BundleDroppedItem{Kind: kindASR, Ref: fmt.Sprintf("%s#%d", cue.Source, cue.Cue.Index), Tokens: lineTokens(tok, asrLine(cue))}, true
+	case len(t.OCR) > 0:
+		entry := t.OCR[len(t.OCR)-1]
+		t.OCR = t.OCR[:len(t.OCR)-1]
+		return runmanifest.BundleDroppedItem{Kind: kindOCR, Ref: entry.Screenshot, Tokens: lineTokens(tok, ocrLine(base, entry))}, true
+	case len(t.Events) > 0:
+		record := t.Events[len(t.Events)-1]
+		t.Events = t.Events[:len(t.Events)-1]
+		return runmanifest.BundleDroppedItem{Kind: kindEvent, Ref: record.ID, Tokens: lineTokens(tok, eventLine(record))}, true
+	}
+	return runmanifest.BundleDroppedItem{}, false
+}
+
+func lineTokens(tok *tokenizer.Tokenizer, line string) int {
+	return tok.Count("- " + line + "\n")
+}
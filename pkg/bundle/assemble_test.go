@@ -0,0 +1,83 @@
+package bundle
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/asr"
+	"github.com/offlinefirst/limitless-context/pkg/events"
+	"github.com/offlinefirst/limitless-context/pkg/tokenizer"
+)
+
+func trimFixture() *task {
+	t := &task{ID: "task_001", Start: fixtureBase, End: fixtureBase.Add(time.Minute)}
+	for i := 1; i <= 4; i++ {
+		t.Events = append(t.Events, eventRecord{
+			ID:    events.SequenceID(i),
+			Event: events.Event{Timestamp: fixtureBase.Add(time.Duration(i) * time.Second), Category: "keyboard", Action: "type", Target: "editor"},
+		})
+		t.OCR = append(t.OCR, ocrRecord{
+			Screenshot: fmt.Sprintf("screenshot_%03d.png", i),
+			Text:       strings.Repeat("quarterly roadmap review notes ", 6),
+			CapturedAt: fixtureBase.Add(time.Duration(i) * 10 * time.Second),
+		})
+		t.ASR = append(t.ASR, asrRecord{
+			Source: "meeting_0001.vtt",
+			Cue:    asr.Cue{Index: i, Start: time.Duration(i) * time.Second, End: time.Duration(i+1) * time.Second, Text: strings.Repeat("let's walk through the plan ", 6)},
+		})
+	}
+	return t
+}
+
+func TestAssembleContextWithinBudgetKeepsEverything(t *testing.T) {
+	tok := tokenizer.Default()
+	task := trimFixture()
+	out := assembleContext("run", fixtureBase, task, tok, 100000)
+	if len(out.Dropped) != 0 || out.Tokens != out.FullTokens || out.Kept != task {
+		t.Fatalf("expected untouched context, dropped %+v", out.Dropped)
+	}
+	if strings.Contains(out.Doc, "- Omitted:") {
+		t.Fatalf("did not expect omission note:\n%s", out.Doc)
+	}
+}
+
+func TestAssembleContextDropsByPriority(t *testing.T) {
+	tok := tokenizer.Default()
+	task := trimFixture()
+	full := assembleContext("run", fixtureBase, task, tok, 100000)
+
+	// Budget roughly two ASR cues short of the full document.
+	budget := full.FullTokens - 2*lineTokens(tok, asrLine(task.ASR[0]))
+	out := assembleContext("run", fixtureBase, task, tok, budget)
+	if out.Tokens > budget {
+		t.Fatalf("trimmed context is %d tokens, budget %d", out.Tokens, budget)
+	}
+	if len(out.Dropped) < 2 {
+		t.Fatalf("expected at least two dropped items, got %+v", out.Dropped)
+	}
+	if out.Dropped[0].Kind != kindASR || out.Dropped[0].Ref != "meeting_0001.vtt#4" || out.Dropped[1].Ref != "meeting_0001.vtt#3" {
+		t.Fatalf("expected latest ASR cues dropped first, got %+v", out.Dropped)
+	}
+	for _, item := range out.Dropped {
+		if item.Tokens <= 0 || !strings.Contains(item.Reason, fmt.Sprintf("per_task_token_budget %d", budget)) {
+			t.Fatalf("dropped item lacks accounting: %+v", item)
+		}
+	}
+	if len(out.Kept.Events) != 4 || len(out.Kept.OCR) != 4 || len(task.ASR) != 4 {
+		t.Fatalf("trimming must not touch higher-priority items or the input task")
+	}
+	if !strings.Contains(out.Doc, fmt.Sprintf("- Omitted: %d item(s)", len(out.Dropped))) {
+		t.Fatalf("expected omission note:\n%s", out.Doc)
+	}
+
+	// A budget below the header alone drops everything, events last.
+	starved := assembleContext("run", fixtureBase, task, tok, 1)
+	if len(starved.Dropped) != 12 || starved.Dropped[11].Kind != kindEvent || starved.Dropped[11].Ref != "evt_0001" {
+		t.Fatalf("unexpected drop order: %+v", starved.Dropped)
+	}
+	if starved.Dropped[4].Kind != kindOCR || starved.Dropped[4].Ref != "screenshot_004.png" {
+		t.Fatalf("expected OCR to be dropped after ASR: %+v", starved.Dropped[4])
+	}
+}
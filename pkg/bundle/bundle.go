@@ -0,0 +1,461 @@
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/cluster"
+	"github.com/offlinefirst/limitless-context/pkg/prompts"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+	"github.com/offlinefirst/limitless-context/pkg/sessionize"
+	"github.com/offlinefirst/limitless-context/pkg/tokenizer"
+)
+
+// Options configure bundle generation for a single run.
+type Options struct {
+	Layout             runmanifest.Layout
+	Manifest           runmanifest.Manifest
+	Tokenizer          string
+	Templates          *prompts.Set
+	PerTaskTokenBudget int
+	MaxContextTokens   int
+	IdleGap            time.Duration
+}
+
+// Result reports the bundle artifacts written for a run.
+type Result struct {
+	BundlesDir     string
+	ReadmePath     string
+	DaySummaryDir  string
+	InlinedOutputs int
+	Tasks          []TaskSummary
+}
+
+// TaskSummary describes a single task bundle directory.
+type TaskSummary struct {
+	ID              string
+	Dir             string
+	Start           time.Time
+	End             time.Time
+	EventCount      int
+	ScreenshotCount int
+	OCRCount        int
+	ASRCount        int
+	DominantApp     string
+	DominantURL     string
+	PromptTokens    int
+	ContextTokens   int
+	DroppedCount    int
+}
+
+// task accumulates the artifacts that belong to one bundle.
+type task struct {
+	ID          string
+	Start       time.Time
+	End         time.Time
+	DominantApp string
+	DominantURL string
+	Reasons     []string
+	Omitted     int
+	Events      []eventRecord
+	Screenshots []screenshotRecord
+	OCR         []ocrRecord
+	ASR         []asrRecord
+}
+
+func newTemplateRef(r prompts.Rendered) runmanifest.BundleTemplate {
+	return runmanifest.BundleTemplate{Name: r.Name, Source: r.Source, SHA256: r.SHA256}
+}
+
+// Build reads the run artifacts and writes task bundles, the day summary, and
+// README_bundles.md. It refuses to run once a task output has been saved,
+// because re-clustering could renumber tasks under it; RefreshDaySummary
+// picks up saved outputs instead.
+func Build(opts Options) (Result, error) {
+	if opts.Layout.Root == "" {
+		return Result{}, errors.New("run layout must not be empty")
+	}
+	if opts.PerTaskTokenBudget <= 0 {
+		return Result{}, errors.New("per task token budget must be positive")
+	}
+	if opts.MaxContextTokens <= 0 {
+		return Result{}, errors.New("max context tokens must be positive")
+	}
+	tok, err := tokenizer.DefaultRegistry().Lookup(tokenizerName(opts.Tokenizer))
+	if err != nil {
+		return Result{}, err
+	}
+	if opts.Templates == nil {
+		opts.Templates = prompts.NewSet(prompts.Options{})
+	}
+	saved, err := savedOutputs(opts.Layout.BundlesDir)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(saved) > 0 {
+		return Result{}, fmt.Errorf("%s already has a saved output.json; re-bundling can renumber tasks under it, so run tester day-summary to refresh the day summary, or move the outputs aside to re-bundle", strings.Join(saved, ", "))
+	}
+
+	in, err := loadInputs(opts.Layout, opts.Manifest)
+	if err != nil {
+		return Result{}, fmt.Errorf("load run inputs: %w", err)
+	}
+	sessions, err := sessionize.Run(opts.Layout.EventsDir, sessionize.Options{IdleGap: opts.IdleGap})
+	if err != nil {
+		return Result{}, fmt.Errorf("sessionize events: %w", err)
+	}
+
+	if err := os.MkdirAll(opts.Layout.BundlesDir, 0o755); err != nil {
+		return Result{}, fmt.Errorf("ensure bundles directory: %w", err)
+	}
+
+	clusters := cluster.Cluster(sessions.Sessions, clusterRecords(in), clusterScreenshots(in), cluster.Options{})
+	if err := cluster.Write(filepath.Join(opts.Layout.BundlesDir, cluster.FileName), clusters); err != nil {
+		return Result{}, err
+	}
+
+	tasks := partition(in, clusters.Clusters)
+	if err := removeStaleTasks(opts.Layout.BundlesDir, len(tasks)); err != nil {
+		return Result{}, err
+	}
+	result := Result{BundlesDir: opts.Layout.BundlesDir}
+	for _, t := range tasks {
+		summary, err := writeTask(opts, tok, in, t)
+		if err != nil {
+			return Result{}, fmt.Errorf("write %s: %w", t.ID, err)
+		}
+		result.Tasks = append(result.Tasks, summary)
+	}
+
+	day, err := writeDaySummary(opts, tok, result.Tasks)
+	if err != nil {
+		return Result{}, fmt.Errorf("write day summary: %w", err)
+	}
+	result.DaySummaryDir = day.Dir
+	result.InlinedOutputs = day.Inlined
+
+	readmePath, err := writeReadme(opts, result.Tasks)
+	if err != nil {
+		return Result{}, fmt.Errorf("write README_bundles.md: %w", err)
+	}
+	result.ReadmePath = readmePath
+
+	return result, nil
+}
+
+// partition turns each cluster into a task and attaches OCR text and
+// transcript cues by time. Screenshots follow the cluster assignment.
+func partition(in runInputs, clusters []cluster.TaskCluster) []*task {
+	eventsByID := make(map[string]eventRecord, len(in.Events))
+	for _, record := range in.Events {
+		eventsByID[record.ID] = record
+	}
+	shotsByName := make(map[string]screenshotRecord, len(in.Screenshots))
+	for _, shot := range in.Screenshots {
+		shotsByName[shot.Name] = shot
+	}
+
+	var tasks []*task
+	for _, c := range clusters {
+		t := &task{
+			Start:       c.Start.UTC(),
+			End:         c.End.UTC(),
+			DominantApp: c.DominantApp,
+			DominantURL: c.DominantURL,
+			Reasons:     c.Reasons,
+		}
+		for _, id := range c.EventIDs {
+			if record, ok := eventsByID[id]; ok {
+				t.Events = append(t.Events, record)
+			}
+		}
+		for _, name := range c.Screenshots {
+			if shot, ok := shotsByName[name]; ok {
+				t.Screenshots = append(t.Screenshots, shot)
+			}
+		}
+		tasks = append(tasks, t)
+	}
+
+	if len(tasks) == 0 && (len(in.Screenshots) > 0 || len(in.OCR) > 0 || len(in.ASR) > 0) {
+		only := &task{Start: in.Base, End: in.Base}
+		for _, shot := range in.Screenshots {
+			only.widen(shot.Metadata.CapturedAt)
+		}
+		for _, entry := range in.OCR {
+			only.widen(entry.CapturedAt)
+		}
+		for _, cue := range in.ASR {
+			only.widen(cue.Start)
+			only.widen(cue.End)
+		}
+		tasks = append(tasks, only)
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	if len(clusters) == 0 {
+		tasks[0].Screenshots = in.Screenshots
+	}
+	for _, entry := range in.OCR {
+		t := nearest(tasks, entry.CapturedAt)
+		t.OCR = append(t.OCR, entry)
+	}
+	for _, cue := range in.ASR {
+		t := nearest(tasks, cue.Start)
+		t.ASR = append(t.ASR, cue)
+	}
+
+	for i, t := range tasks {
+		t.ID = taskID(i + 1)
+	}
+	return tasks
+}
+
+func (t *task) widen(ts time.Time) {
+	if ts.IsZero() {
+		return
+	}
+	if ts.Before(t.Start) {
+		t.Start = ts
+	}
+	if ts.After(t.End) {
+		t.End = ts
+	}
+}
+
+// nearest returns the task covering ts, or the closest one when ts falls in a gap.
+// Artifacts without a timestamp are attached to the first task.
+func nearest(tasks []*task, ts time.Time) *task {
+	if ts.IsZero() {
+		return tasks[0]
+	}
+	best := tasks[0]
+	bestDistance := time.Duration(-1)
+	for _, t := range tasks {
+		var distance time.Duration
+		switch {
+		case ts.Before(t.Start):
+			distance = t.Start.Sub(ts)
+		case ts.After(t.End):
+			distance = ts.Sub(t.End)
+		default:
+			return t
+		}
+		if bestDistance < 0 || distance < bestDistance {
+			best = t
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+func taskID(n int) string {
+	return fmt.Sprintf("task_%03d", n)
+}
+
+// removeStaleTasks deletes task directories left by an earlier build that
+// produced more than count tasks, so the bundles match the current clusters.
+func removeStaleTasks(bundlesDir string, count int) error {
+	entries, err := os.ReadDir(bundlesDir)
+	if err != nil {
+		return fmt.Errorf("list bundles directory: %w", err)
+	}
+	for _, entry := range entries {
+		var n int
+		if !entry.IsDir() || !isTaskDir(entry.Name(), &n) || n <= count {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(bundlesDir, entry.Name())); err != nil {
+			return fmt.Errorf("remove stale %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// savedOutputs lists the task directories that already hold an output.json.
+func savedOutputs(bundlesDir string) ([]string, error) {
+	entries, err := os.ReadDir(bundlesDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list bundles directory: %w", err)
+	}
+	var saved []string
+	for _, entry := range entries {
+		var n int
+		if !entry.IsDir() || !isTaskDir(entry.Name(), &n) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(bundlesDir, entry.Name(), "output.json")); err == nil {
+			saved = append(saved, entry.Name())
+		}
+	}
+	return saved, nil
+}
+
+// isTaskDir reports whether name is a directory written by taskID and stores
+// its task number in n.
+func isTaskDir(name string, n *int) bool {
+	if _, err := fmt.Sscanf(name, "task_%d", n); err != nil {
+		return false
+	}
+	return name == taskID(*n)
+}
+
+func clusterRecords(in runInputs) []cluster.Record {
+	out := make([]cluster.Record, 0, len(in.Events))
+	for _, record := range in.Events {
+		out = append(out, cluster.Record{ID: record.ID, Event: record.Event})
+	}
+	return out
+}
+
+func clusterScreenshots(in runInputs) []cluster.Screenshot {
+	out := make([]cluster.Screenshot, 0, len(in.Screenshots))
+	for _, shot := range in.Screenshots {
+		out = append(out, cluster.Screenshot{Name: shot.Name, Metadata: shot.Metadata})
+	}
+	return out
+}
+
+func tokenizerName(name string) string {
+	if name == "" {
+		return tokenizer.DefaultName
+	}
+	return name
+}
+
+func writeTask(opts Options, tok *tokenizer.Tokenizer, in runInputs, t *task) (TaskSummary, error) {
+	dir := filepath.Join(opts.Layout.BundlesDir, t.ID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return TaskSummary{}, fmt.Errorf("ensure task directory: %w", err)
+	}
+
+	assembled := assembleContext(opts.Manifest.RunID, in.Base, t, tok, opts.PerTaskTokenBudget)
+	contextDoc := assembled.Doc
+	kept := assembled.Kept
+	rendered, err := opts.Templates.Render(prompts.TaskTemplate, prompts.TaskData{RunID: opts.Manifest.RunID, TaskID: t.ID})
+	if err != nil {
+		return TaskSummary{}, err
+	}
+	prompt := rendered.Prompt
+
+	if err := os.WriteFile(filepath.Join(dir, "prompt.txt"), []byte(prompt), 0o644); err != nil {
+		return TaskSummary{}, fmt.Errorf("write prompt: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schema.json"), rendered.SchemaJSON, 0o644); err != nil {
+		return TaskSummary{}, fmt.Errorf("write schema: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "context.md"), []byte(contextDoc), 0o644); err != nil {
+		return TaskSummary{}, fmt.Errorf("write context: %w", err)
+	}
+
+	byTokenizer, err := countAll(prompt, contextDoc)
+	if err != nil {
+		return TaskSummary{}, err
+	}
+	promptTokens := tok.Count(prompt)
+	contextTokens := assembled.Tokens
+	sections := contextSections(opts.Manifest.RunID, in.Base, kept)
+	metrics := runmanifest.BundleMetrics{
+		TaskID:         t.ID,
+		RunID:          opts.Manifest.RunID,
+		Start:          t.Start,
+		End:            t.End,
+		PromptTemplate: newTemplateRef(rendered),
+		Items: runmanifest.BundleItemCounts{
+			Events:      len(kept.Events),
+			Screenshots: len(kept.Screenshots),
+			OCR:         len(kept.OCR),
+			ASR:         len(kept.ASR),
+		},
+		Characters: runmanifest.BundleSizes{
+			Prompt:  len([]rune(prompt)),
+			Context: len([]rune(contextDoc)),
+			Total:   len([]rune(prompt)) + len([]rune(contextDoc)),
+		},
+		Tokenizer: tok.Name(),
+		Tokens: runmanifest.BundleSizes{
+			Prompt:  promptTokens,
+			Context: contextTokens,
+			Total:   promptTokens + contextTokens,
+		},
+		SectionTokens: runmanifest.BundleSectionTokens{
+			Header: tok.Count(sections.Header),
+			Events: tok.Count(sections.Events),
+			OCR:    tok.Count(sections.OCR),
+			ASR:    tok.Count(sections.ASR),
+		},
+		TokensByTokenizer:      byTokenizer,
+		TokenBudget:            opts.PerTaskTokenBudget,
+		WithinBudget:           contextTokens <= opts.PerTaskTokenBudget,
+		UntrimmedContextTokens: assembled.FullTokens,
+		Dropped:                assembled.Dropped,
+		Checksums: runmanifest.BundleChecksums{
+			PromptSHA256:  sha256Hex(prompt),
+			ContextSHA256: sha256Hex(contextDoc),
+			SchemaSHA256:  sha256Hex(string(rendered.SchemaJSON)),
+		},
+	}
+	if err := runmanifest.SaveBundleMetrics(metrics, filepath.Join(dir, "metrics.json")); err != nil {
+		return TaskSummary{}, err
+	}
+
+	return TaskSummary{
+		ID:              t.ID,
+		Dir:             dir,
+		Start:           t.Start,
+		End:             t.End,
+		EventCount:      len(kept.Events),
+		ScreenshotCount: len(kept.Screenshots),
+		OCRCount:        len(kept.OCR),
+		ASRCount:        len(kept.ASR),
+		DominantApp:     t.DominantApp,
+		DominantURL:     t.DominantURL,
+		PromptTokens:    promptTokens,
+		ContextTokens:   contextTokens,
+		DroppedCount:    len(assembled.Dropped),
+	}, nil
+}
+
+// countAll measures the prompt and context with every registered tokenizer.
+func countAll(prompt, contextDoc string) (map[string]runmanifest.BundleSizes, error) {
+	reg := tokenizer.DefaultRegistry()
+	counts := make(map[string]runmanifest.BundleSizes)
+	for _, name := range reg.Names() {
+		tok, err := reg.Lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		p, c := tok.Count(prompt), tok.Count(contextDoc)
+		counts[name] = runmanifest.BundleSizes{Prompt: p, Context: c, Total: p + c}
+	}
+	return counts, nil
+}
+
+func sha256Hex(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeJSON(path string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", filepath.Base(path), err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package bundle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/cluster"
+	"github.com/offlinefirst/limitless-context/pkg/config"
+	"github.com/offlinefirst/limitless-context/pkg/events"
+	"github.com/offlinefirst/limitless-context/pkg/ocr"
+	"github.com/offlinefirst/limitless-context/pkg/prompts"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+	"github.com/offlinefirst/limitless-context/pkg/screenshots"
+	"github.com/offlinefirst/limitless-context/pkg/sessionize"
+	"github.com/offlinefirst/limitless-context/pkg/video"
+)
+
+var fixtureBase = time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+
+// writeFixtureRun lays out a run with two idle-separated bursts of activity.
+func writeFixtureRun(t *testing.T) (runmanifest.Layout, runmanifest.Manifest) {
+	t.Helper()
+	layout := runmanifest.BuildLayout(t.TempDir(), "20240512_093000")
+	if err := runmanifest.EnsureFilesystem(layout); err != nil {
+		t.Fatalf("ensure filesystem: %v", err)
+	}
+	man := runmanifest.New(runmanifest.Options{
+		RunID:     "20240512_093000",
+		CreatedAt: fixtureBase,
+		Config:    config.Default(),
+		Layout:    layout,
+	})
+	started := fixtureBase
+	man.Status.StartedAt = &started
+	if err := runmanifest.Save(man, layout.ManifestPath); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+
+	fixture := []events.Event{
+		{Timestamp: fixtureBase, Category: "window", Action: "focus", Target: "editor", Metadata: map[string]string{"app": "code", "title": "main.go"}},
+		{Timestamp: fixtureBase.Add(10 * time.Second), Category: "keyboard", Action: "type", Target: "editor", Metadata: map[string]string{"app": "code"}},
+		{Timestamp: fixtureBase.Add(30 * time.Second), Category: "keyboard", Action: "type", Target: "terminal", Metadata: map[string]string{"app": "terminal", "text": "go test ./..."}},
+		{Timestamp: fixtureBase.Add(5 * time.Minute), Category: "window", Action: "focus", Target: "docs-app", Metadata: map[string]string{"app": "docs", "url": "https://docs.example.com/roadmap"}},
+		{Timestamp: fixtureBase.Add(5*time.Minute + 20*time.Second), Category: "clipboard", Action: "copy", Metadata: map[string]string{"app": "docs"}},
+	}
+	file, err := os.Create(filepath.Join(layout.EventsDir, "events_fine.jsonl"))
+	if err != nil {
+		t.Fatalf("create events: %v", err)
+	}
+	encoder := json.NewEncoder(file)
+	for _, event := range fixture {
+		if err := encoder.Encode(event); err != nil {
+			t.Fatalf("encode event: %v", err)
+		}
+	}
+	file.Close()
+
+	for i, offset := range []time.Duration{15 * time.Second, 5*time.Minute + 10*time.Second} {
+		name := filepath.Join(layout.ScreensDir, "screenshot_00"+string(rune('1'+i)))
+		meta := screenshots.Metadata{CapturedAt: fixtureBase.Add(offset), Backend: "synthetic", Width: 2, Height: 2, ImagePath: filepath.Base(name) + ".png"}
+		writeJSONFixture(t, name+".json", meta)
+		if err := os.WriteFile(name+".png", []byte("png"), 0o644); err != nil {
+			t.Fatalf("write png: %v", err)
+		}
+	}
+
+	writeJSONFixture(t, filepath.Join(layout.OCRDir, "index.json"), ocr.Index{
+		GeneratedAt: fixtureBase,
+		Entries: []ocr.IndexEntry{
+			{Screenshot: "screenshot_001.png", Text: "func main() {\n  run()\n}", Language: "eng"},
+			{Screenshot: "screenshot_002.png", Text: "Roadmap Q3", Language: "eng"},
+		},
+	})
+
+	vtt := "WEBVTT\n\n1\n00:05:05.000 --> 00:05:12.000\nLet's review the roadmap.\n"
+	if err := os.WriteFile(filepath.Join(layout.ASRDir, "meeting_0001.vtt"), []byte(vtt), 0o644); err != nil {
+		t.Fatalf("write vtt: %v", err)
+	}
+
+	return layout, man
+}
+
+func writeJSONFixture(t *testing.T, path string, value any) {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("marshal %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestBuildWritesTaskBundles(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+
+	result, err := Build(Options{Layout: layout, Manifest: man, PerTaskTokenBudget: 5000, MaxContextTokens: 8192})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	if len(result.Tasks) != 2 {
+		t.Fatalf("expected two tasks split by idle gap, got %d", len(result.Tasks))
+	}
+	first, second := result.Tasks[0], result.Tasks[1]
+	if first.ID != "task_001" || second.ID != "task_002" {
+		t.Fatalf("unexpected task ids %s, %s", first.ID, second.ID)
+	}
+	if first.EventCount != 3 || second.EventCount != 2 {
+		t.Fatalf("unexpected event split %d/%d", first.EventCount, second.EventCount)
+	}
+	if first.OCRCount != 1 || second.OCRCount != 1 || second.ASRCount != 1 {
+		t.Fatalf("unexpected artifact assignment: %+v %+v", first, second)
+	}
+
+	sessions, err := sessionize.Load(filepath.Join(layout.EventsDir, sessionize.FileName))
+	if err != nil {
+		t.Fatalf("load sessions: %v", err)
+	}
+	if len(sessions.Sessions) != 2 || len(sessions.Sessions[1].EventIDs) != 2 {
+		t.Fatalf("expected bundles to follow events/sessions.json, got %+v", sessions.Sessions)
+	}
+	clusters, err := cluster.Load(filepath.Join(layout.BundlesDir, cluster.FileName))
+	if err != nil {
+		t.Fatalf("load clusters: %v", err)
+	}
+	if len(clusters.Clusters) != 2 || clusters.Clusters[0].DominantApp != "code" || clusters.Clusters[1].DominantApp != "docs" {
+		t.Fatalf("unexpected clusters: %+v", clusters.Clusters)
+	}
+
+	for _, name := range []string{"prompt.txt", "context.md", "metrics.json", "schema.json"} {
+		if _, err := os.Stat(filepath.Join(first.Dir, name)); err != nil {
+			t.Fatalf("expected %s: %v", name, err)
+		}
+	}
+
+	contextDoc, err := os.ReadFile(filepath.Join(second.Dir, "context.md"))
+	if err != nil {
+		t.Fatalf("read context: %v", err)
+	}
+	for _, want := range []string{"- Focus: docs — https://docs.example.com/roadmap", `"id":"evt_0004"`, "[shot:05:10] screenshot_002.png: Roadmap Q3", "[05:05–05:12] meeting_0001.vtt"} {
+		if !strings.Contains(string(contextDoc), want) {
+			t.Fatalf("expected context to contain %q:\n%s", want, contextDoc)
+		}
+	}
+
+	var metrics runmanifest.BundleMetrics
+	data, err := os.ReadFile(filepath.Join(first.Dir, "metrics.json"))
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatalf("decode metrics: %v", err)
+	}
+	if metrics.Items.Events != 3 || metrics.Checksums.ContextSHA256 == "" || metrics.Checksums.SchemaSHA256 == "" || metrics.Tokens.Context == 0 {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+	if metrics.SchemaVersion != runmanifest.BundleMetricsVersion || metrics.SectionTokens.Events == 0 || metrics.SectionTokens.Header == 0 {
+		t.Fatalf("unexpected metrics version or section tokens: %+v", metrics)
+	}
+	if metrics.PromptTemplate.Name != "task" || metrics.PromptTemplate.Source != "embedded" || len(metrics.PromptTemplate.SHA256) != 64 {
+		t.Fatalf("unexpected prompt template reference: %+v", metrics.PromptTemplate)
+	}
+	if metrics.Tokenizer != "cl100k" || len(metrics.TokensByTokenizer) != 3 || metrics.TokensByTokenizer["cl100k"] != metrics.Tokens {
+		t.Fatalf("unexpected per-tokenizer counts: %s %+v", metrics.Tokenizer, metrics.TokensByTokenizer)
+	}
+	if metrics.TokensByTokenizer["llama"].Context == 0 || metrics.TokensByTokenizer["o200k"].Context == 0 {
+		t.Fatalf("expected counts for every family: %+v", metrics.TokensByTokenizer)
+	}
+
+	if _, err := os.Stat(filepath.Join(result.DaySummaryDir, "context_index.json")); err != nil {
+		t.Fatalf("expected day summary index: %v", err)
+	}
+	readme, err := os.ReadFile(result.ReadmePath)
+	if err != nil {
+		t.Fatalf("read README: %v", err)
+	}
+	if !strings.Contains(string(readme), "task_002") {
+		t.Fatalf("expected README to list tasks:\n%s", readme)
+	}
+}
+
+func TestBuildIsDeterministic(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+	opts := Options{Layout: layout, Manifest: man, PerTaskTokenBudget: 5000, MaxContextTokens: 8192}
+
+	if _, err := Build(opts); err != nil {
+		t.Fatalf("first build: %v", err)
+	}
+	first, err := os.ReadFile(filepath.Join(layout.BundlesDir, "task_001", "metrics.json"))
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+	if _, err := Build(opts); err != nil {
+		t.Fatalf("second build: %v", err)
+	}
+	second, err := os.ReadFile(filepath.Join(layout.BundlesDir, "task_001", "metrics.json"))
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+	if string(first) != string(second) {
+		t.Fatalf("expected identical metrics across builds")
+	}
+}
+
+func TestBuildRemovesStaleTaskDirectories(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+	opts := Options{Layout: layout, Manifest: man, PerTaskTokenBudget: 5000, MaxContextTokens: 8192}
+
+	if _, err := Build(opts); err != nil {
+		t.Fatalf("first build: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(layout.BundlesDir, "task_002")); err != nil {
+		t.Fatalf("expected task_002 after first build: %v", err)
+	}
+
+	// A longer idle gap merges both bursts, so the second build finds one cluster.
+	opts.IdleGap = time.Hour
+	result, err := Build(opts)
+	if err != nil {
+		t.Fatalf("second build: %v", err)
+	}
+	if len(result.Tasks) != 1 {
+		t.Fatalf("expected one task after re-clustering, got %d", len(result.Tasks))
+	}
+	if _, err := os.Stat(filepath.Join(layout.BundlesDir, "task_002")); !os.IsNotExist(err) {
+		t.Fatalf("expected stale task_002 to be removed, got %v", err)
+	}
+	for _, keep := range []string{"task_001", "day_summary", cluster.FileName} {
+		if _, err := os.Stat(filepath.Join(layout.BundlesDir, keep)); err != nil {
+			t.Fatalf("expected %s to remain: %v", keep, err)
+		}
+	}
+	readme, err := os.ReadFile(result.ReadmePath)
+	if err != nil {
+		t.Fatalf("read README: %v", err)
+	}
+	if strings.Contains(string(readme), "task_002") {
+		t.Fatalf("expected README to drop task_002:\n%s", readme)
+	}
+}
+
+func TestBuildUsesTemplateOverrides(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+	dir := t.TempDir()
+	override := `{{define "schema"}}{"type":"object","required":["title"],"properties":{"title":{"type":"string"}}}{{end}}Title {{.TaskID}} only.`
+	if err := os.WriteFile(filepath.Join(dir, "task.tmpl"), []byte(override), 0o644); err != nil {
+		t.Fatalf("write override: %v", err)
+	}
+
+	opts := Options{Layout: layout, Manifest: man, Templates: prompts.NewSet(prompts.Options{Dirs: []string{dir}}), PerTaskTokenBudget: 5000, MaxContextTokens: 8192}
+	if _, err := Build(opts); err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	prompt, err := os.ReadFile(filepath.Join(layout.BundlesDir, "task_002", "prompt.txt"))
+	if err != nil || string(prompt) != "Title task_002 only." {
+		t.Fatalf("expected override prompt, got %q (%v)", prompt, err)
+	}
+	var metrics runmanifest.BundleMetrics
+	data, err := os.ReadFile(filepath.Join(layout.BundlesDir, "task_002", "metrics.json"))
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatalf("decode metrics: %v", err)
+	}
+	if metrics.PromptTemplate.Source != filepath.Join(dir, "task.tmpl") {
+		t.Fatalf("expected override source in metrics, got %+v", metrics.PromptTemplate)
+	}
+	if _, err := os.Stat(filepath.Join(layout.BundlesDir, "day_summary", "schema.json")); err != nil {
+		t.Fatalf("expected embedded day summary schema: %v", err)
+	}
+}
+
+func TestBuildPlacesKeyframeOCRByCaptureTime(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+	frames := filepath.Join(layout.VideoDir, video.FramesDirName)
+	if err := os.MkdirAll(frames, 0o755); err != nil {
+		t.Fatalf("create frames dir: %v", err)
+	}
+	writeJSONFixture(t, filepath.Join(frames, "frame_0001.json"), screenshots.Metadata{
+		CapturedAt: fixtureBase.Add(5*time.Minute + 15*time.Second),
+		Backend:    video.KeyframeBackend,
+		ImagePath:  "frame_0001.png",
+	})
+	writeJSONFixture(t, filepath.Join(layout.OCRDir, "index.json"), ocr.Index{
+		GeneratedAt: fixtureBase,
+		Entries: []ocr.IndexEntry{
+			{Screenshot: "screenshot_001.png", Text: "func main() {}", Language: "eng"},
+			{Screenshot: "frame_0001.png", Text: "Roadmap Q3 milestones", Language: "eng"},
+		},
+	})
+
+	result, err := Build(Options{Layout: layout, Manifest: man, PerTaskTokenBudget: 5000, MaxContextTokens: 8192})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if len(result.Tasks) != 2 || result.Tasks[0].OCRCount != 1 || result.Tasks[1].OCRCount != 1 {
+		t.Fatalf("expected the keyframe's OCR text in the second task: %+v", result.Tasks)
+	}
+}
+
+func TestBuildRejectsUnknownTokenizer(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+	_, err := Build(Options{Layout: layout, Manifest: man, Tokenizer: "gpt2", PerTaskTokenBudget: 5000, MaxContextTokens: 8192})
+	if err == nil || !strings.Contains(err.Error(), "unknown tokenizer") {
+		t.Fatalf("expected unknown tokenizer error, got %v", err)
+	}
+}
+
+func TestBuildWithoutEventsUsesSingleTask(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+	if err := os.Remove(filepath.Join(layout.EventsDir, "events_fine.jsonl")); err != nil {
+		t.Fatalf("remove events: %v", err)
+	}
+
+	result, err := Build(Options{Layout: layout, Manifest: man, PerTaskTokenBudget: 5000, MaxContextTokens: 8192})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if len(result.Tasks) != 1 {
+		t.Fatalf("expected single task for video/screenshot-only run, got %d", len(result.Tasks))
+	}
+	if result.Tasks[0].OCRCount != 2 || result.Tasks[0].EventCount != 0 {
+		t.Fatalf("unexpected task contents: %+v", result.Tasks[0])
+	}
+}
+
+func TestFormatOffset(t *testing.T) {
+	cases := map[time.Duration]string{
+		0:                              "00:00",
+		15 * time.Second:               "00:15",
+		75*time.Minute + 3*time.Second: "75:03",
+		-time.Second:                   "00:00",
+		1500 * time.Millisecond:        "00:02",
+	}
+	for input, want := range cases {
+		if got := formatOffset(input); got != want {
+			t.Fatalf("formatOffset(%s) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/prompts"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+	"github.com/offlinefirst/limitless-context/pkg/tokenizer"
+)
+
+// Output states recorded per task in context_index.json.
+const (
+	outputInlined    = "inlined"
+	outputMissing    = "missing"
+	outputInvalid    = "invalid"
+	outputOverBudget = "over_budget"
+)
+
+type daySummaryIndex struct {
+	RunID            string                     `json:"run_id"`
+	Template         runmanifest.BundleTemplate `json:"prompt_template"`
+	MaxContextTokens int                        `json:"max_context_tokens"`
+	ContextTokens    int                        `json:"context_tokens"`
+	Tasks            []daySummaryTask           `json:"tasks"`
+}
+
+type daySummaryTask struct {
+	TaskID       string    `json:"task_id"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	DominantApp  string    `json:"dominant_app,omitempty"`
+	DominantURL  string    `json:"dominant_url,omitempty"`
+	Output       string    `json:"output"`
+	OutputStatus string    `json:"output_status"`
+}
+
+// taskOutput is the subset of a task's output.json inlined into the day summary.
+type taskOutput struct {
+	TaskID     string   `json:"task_id"`
+	Title      string   `json:"title"`
+	Summary    string   `json:"summary"`
+	KeyActions []string `json:"key_actions,omitempty"`
+}
+
+// daySummary reports what writeDaySummary produced.
+type daySummary struct {
+	Dir     string
+	Inlined int
+}
+
+// RefreshDaySummary rewrites day_summary/ from the tasks listed in its
+// context_index.json, inlining any outputs saved since Build. Task folders and
+// README_bundles.md are left untouched.
+func RefreshDaySummary(opts Options) (Result, error) {
+	if opts.Layout.Root == "" {
+		return Result{}, errors.New("run layout must not be empty")
+	}
+	if opts.MaxContextTokens <= 0 {
+		return Result{}, errors.New("max context tokens must be positive")
+	}
+	tok, err := tokenizer.DefaultRegistry().Lookup(tokenizerName(opts.Tokenizer))
+	if err != nil {
+		return Result{}, err
+	}
+	if opts.Templates == nil {
+		opts.Templates = prompts.NewSet(prompts.Options{})
+	}
+
+	indexPath := filepath.Join(opts.Layout.BundlesDir, "day_summary", "context_index.json")
+	data, err := os.ReadFile(indexPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return Result{}, fmt.Errorf("%s is missing; run tester bundle first", indexPath)
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("read day summary index: %w", err)
+	}
+	var index daySummaryIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return Result{}, fmt.Errorf("decode day summary index: %w", err)
+	}
+
+	result := Result{BundlesDir: opts.Layout.BundlesDir}
+	for _, entry := range index.Tasks {
+		result.Tasks = append(result.Tasks, TaskSummary{
+			ID:          entry.TaskID,
+			Dir:         filepath.Join(opts.Layout.BundlesDir, entry.TaskID),
+			Start:       entry.Start,
+			End:         entry.End,
+			DominantApp: entry.DominantApp,
+			DominantURL: entry.DominantURL,
+		})
+	}
+	day, err := writeDaySummary(opts, tok, result.Tasks)
+	if err != nil {
+		return Result{}, fmt.Errorf("write day summary: %w", err)
+	}
+	result.DaySummaryDir = day.Dir
+	result.InlinedOutputs = day.Inlined
+	return result, nil
+}
+
+// writeDaySummary writes day_summary/{prompt.txt,schema.json,context.md,context_index.json}.
+// Existing task outputs are inlined in task order while context.md stays
+// within MaxContextTokens; every other task gets a placeholder.
+func writeDaySummary(opts Options, tok *tokenizer.Tokenizer, tasks []TaskSummary) (daySummary, error) {
+	dir := filepath.Join(opts.Layout.BundlesDir, "day_summary")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return daySummary{}, fmt.Errorf("ensure day summary directory: %w", err)
+	}
+
+	taskIDs := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		taskIDs = append(taskIDs, t.ID)
+	}
+	rendered, err := opts.Templates.Render(prompts.DaySummaryTemplate, prompts.DaySummaryData{RunID: opts.Manifest.RunID, TaskIDs: taskIDs})
+	if err != nil {
+		return daySummary{}, err
+	}
+
+	entries := make([]daySummaryTask, 0, len(tasks))
+	outputs := make([]*taskOutput, len(tasks))
+	for i, t := range tasks {
+		entry := daySummaryTask{
+			TaskID:      t.ID,
+			Start:       t.Start,
+			End:         t.End,
+			DominantApp: t.DominantApp,
+			DominantURL: t.DominantURL,
+			Output:      filepath.ToSlash(filepath.Join("..", t.ID, "output.json")),
+		}
+		out, status, err := readTaskOutput(filepath.Join(t.Dir, "output.json"))
+		if err != nil {
+			return daySummary{}, err
+		}
+		if status == outputInlined {
+			// Pending outputs render with the over-budget placeholder until
+			// accepted, so every measurement below matches the final document.
+			status = outputOverBudget
+		}
+		entry.OutputStatus = status
+		outputs[i] = out
+		entries = append(entries, entry)
+	}
+
+	// Inline greedily in task order so earlier tasks win when the budget is tight.
+	inline := make([]*taskOutput, len(tasks))
+	inlined := 0
+	for i, out := range outputs {
+		if out == nil {
+			continue
+		}
+		inline[i] = out
+		entries[i].OutputStatus = outputInlined
+		if tok.Count(renderDaySummaryContext(opts.Manifest.RunID, entries, inline)) > opts.MaxContextTokens {
+			inline[i] = nil
+			entries[i].OutputStatus = outputOverBudget
+			continue
+		}
+		inlined++
+	}
+	contextDoc := renderDaySummaryContext(opts.Manifest.RunID, entries, inline)
+
+	index := daySummaryIndex{
+		RunID:            opts.Manifest.RunID,
+		Template:         newTemplateRef(rendered),
+		MaxContextTokens: opts.MaxContextTokens,
+		ContextTokens:    tok.Count(contextDoc),
+		Tasks:            entries,
+	}
+	if err := writeJSON(filepath.Join(dir, "context_index.json"), index); err != nil {
+		return daySummary{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "context.md"), []byte(contextDoc), 0o644); err != nil {
+		return daySummary{}, fmt.Errorf("write context: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "prompt.txt"), []byte(rendered.Prompt), 0o644); err != nil {
+		return daySummary{}, fmt.Errorf("write prompt: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schema.json"), rendered.SchemaJSON, 0o644); err != nil {
+		return daySummary{}, fmt.Errorf("write schema: %w", err)
+	}
+	return daySummary{Dir: dir, Inlined: inlined}, nil
+}
+
+// readTaskOutput loads a saved task reply. A missing file or one that is not a
+// JSON object with a summary is reported by status rather than as an error.
+func readTaskOutput(path string) (*taskOutput, string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, outputMissing, nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("read %s: %w", filepath.Base(filepath.Dir(path)), err)
+	}
+	var out taskOutput
+	if err := json.Unmarshal(data, &out); err != nil || strings.TrimSpace(out.Summary) == "" {
+		return nil, outputInvalid, nil
+	}
+	return &out, outputInlined, nil
+}
+
+func renderDaySummaryContext(runID string, entries []daySummaryTask, inline []*taskOutput) string {
+	var b strings.Builder
+	b.WriteString("# Day summary context\n\n")
+	fmt.Fprintf(&b, "- Run: %s\n", runID)
+	fmt.Fprintf(&b, "- Tasks: %d\n", len(entries))
+	if len(entries) == 0 {
+		b.WriteString("\n_No tasks were detected in this run._\n")
+	}
+	for i, entry := range entries {
+		fmt.Fprintf(&b, "\n## %s\n\n", entry.TaskID)
+		fmt.Fprintf(&b, "- Window: %s → %s\n", entry.Start.UTC().Format(time.RFC3339), entry.End.UTC().Format(time.RFC3339))
+		if entry.DominantApp != "" || entry.DominantURL != "" {
+			fmt.Fprintf(&b, "- Focus: %s\n", strings.Join(nonEmpty(entry.DominantApp, entry.DominantURL), " — "))
+		}
+		fmt.Fprintf(&b, "- Output: %s\n\n", entry.Output)
+		if out := inline[i]; out != nil {
+			b.WriteString(compactJSON(out))
+			b.WriteString("\n")
+			continue
+		}
+		switch entry.OutputStatus {
+		case outputOverBudget:
+			fmt.Fprintf(&b, "_Placeholder: output.json was left out to stay within max_context_tokens; paste the contents of %s here._\n", entry.Output)
+		case outputInvalid:
+			fmt.Fprintf(&b, "_Placeholder: output.json is not a JSON object with a summary; fix it or paste the corrected reply from %s here._\n", entry.Output)
+		default:
+			fmt.Fprintf(&b, "_Placeholder: paste the contents of %s here once the task is summarised._\n", entry.Output)
+		}
+	}
+	return b.String()
+}
+
+func compactJSON(value any) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "{}"
+	}
+	return strings.TrimSpace(buf.String())
+}
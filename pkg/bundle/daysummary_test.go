@@ -0,0 +1,152 @@
+package bundle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readDayIndex(t *testing.T, dir string) daySummaryIndex {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "context_index.json"))
+	if err != nil {
+		t.Fatalf("read index: %v", err)
+	}
+	var index daySummaryIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatalf("decode index: %v", err)
+	}
+	return index
+}
+
+func TestDaySummaryPlaceholdersThenInlinesOutputs(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+	opts := Options{Layout: layout, Manifest: man, PerTaskTokenBudget: 5000, MaxContextTokens: 8192}
+
+	result, err := Build(opts)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	index := readDayIndex(t, result.DaySummaryDir)
+	if len(index.Tasks) != 2 || index.Tasks[0].OutputStatus != outputMissing || index.Tasks[1].DominantApp != "docs" {
+		t.Fatalf("unexpected index: %+v", index.Tasks)
+	}
+	if index.Tasks[0].Output != "../task_001/output.json" || index.MaxContextTokens != 8192 || index.ContextTokens == 0 {
+		t.Fatalf("unexpected index header or paths: %+v", index)
+	}
+	contextDoc, err := os.ReadFile(filepath.Join(result.DaySummaryDir, "context.md"))
+	if err != nil {
+		t.Fatalf("read context: %v", err)
+	}
+	if strings.Count(string(contextDoc), "_Placeholder: paste the contents of") != 2 {
+		t.Fatalf("expected two placeholders:\n%s", contextDoc)
+	}
+
+	output := `{"task_id":"task_001","title":"Fix tests","summary":"Edited main.go and ran go test.","key_actions":["ran go test"],"evidence":["event:evt_0003"],"confidence":0.8}`
+	if err := os.WriteFile(filepath.Join(layout.BundlesDir, "task_001", "output.json"), []byte(output), 0o644); err != nil {
+		t.Fatalf("write output: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(layout.BundlesDir, "task_002", "output.json"), []byte("not json"), 0o644); err != nil {
+		t.Fatalf("write output: %v", err)
+	}
+
+	result, err = RefreshDaySummary(opts)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if result.InlinedOutputs != 1 {
+		t.Fatalf("expected one inlined output, got %d", result.InlinedOutputs)
+	}
+	index = readDayIndex(t, result.DaySummaryDir)
+	if index.Tasks[0].OutputStatus != outputInlined || index.Tasks[1].OutputStatus != outputInvalid {
+		t.Fatalf("unexpected statuses: %+v", index.Tasks)
+	}
+	contextDoc, err = os.ReadFile(filepath.Join(result.DaySummaryDir, "context.md"))
+	if err != nil {
+		t.Fatalf("read context: %v", err)
+	}
+	if !strings.Contains(string(contextDoc), `{"task_id":"task_001","title":"Fix tests","summary":"Edited main.go and ran go test.","key_actions":["ran go test"]}`) {
+		t.Fatalf("expected inlined summary without evidence:\n%s", contextDoc)
+	}
+	if !strings.Contains(string(contextDoc), "output.json is not a JSON object with a summary") {
+		t.Fatalf("expected invalid-output placeholder:\n%s", contextDoc)
+	}
+}
+
+func TestDaySummaryRespectsMaxContextTokens(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+	opts := Options{Layout: layout, Manifest: man, PerTaskTokenBudget: 5000, MaxContextTokens: 8192}
+	if _, err := Build(opts); err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	long := `{"task_id":"task_001","title":"Long","summary":"` + strings.Repeat("A very detailed account of the work. ", 40) + `"}`
+	for _, id := range []string{"task_001", "task_002"} {
+		if err := os.WriteFile(filepath.Join(layout.BundlesDir, id, "output.json"), []byte(strings.Replace(long, "task_001", id, 1)), 0o644); err != nil {
+			t.Fatalf("write output: %v", err)
+		}
+	}
+
+	// Enough room for one long summary but not two.
+	baseline := readDayIndex(t, filepath.Join(layout.BundlesDir, "day_summary")).ContextTokens
+	opts.MaxContextTokens = baseline + 400
+	result, err := RefreshDaySummary(opts)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	index := readDayIndex(t, result.DaySummaryDir)
+	if index.Tasks[0].OutputStatus != outputInlined || index.Tasks[1].OutputStatus != outputOverBudget {
+		t.Fatalf("expected earlier task to win the budget: %+v", index.Tasks)
+	}
+	if index.ContextTokens > opts.MaxContextTokens {
+		t.Fatalf("day summary context is %d tokens, limit %d", index.ContextTokens, opts.MaxContextTokens)
+	}
+}
+
+func TestBuildRefusesToRenumberSavedOutputs(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+	opts := Options{Layout: layout, Manifest: man, PerTaskTokenBudget: 5000, MaxContextTokens: 8192}
+	if _, err := Build(opts); err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	metricsPath := filepath.Join(layout.BundlesDir, "task_002", "metrics.json")
+	before, err := os.ReadFile(metricsPath)
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+	output := `{"task_id":"task_002","title":"Roadmap","summary":"Reviewed the roadmap."}`
+	if err := os.WriteFile(filepath.Join(layout.BundlesDir, "task_002", "output.json"), []byte(output), 0o644); err != nil {
+		t.Fatalf("write output: %v", err)
+	}
+
+	// Re-clustering into one task would leave task_002's output orphaned.
+	opts.IdleGap = time.Hour
+	if _, err := Build(opts); err == nil || !strings.Contains(err.Error(), "task_002") || !strings.Contains(err.Error(), "tester day-summary") {
+		t.Fatalf("expected Build to refuse with saved outputs, got %v", err)
+	}
+	after, err := os.ReadFile(metricsPath)
+	if err != nil || string(after) != string(before) {
+		t.Fatalf("expected task_002 to be left alone (%v)", err)
+	}
+
+	result, err := RefreshDaySummary(opts)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if len(result.Tasks) != 2 || result.InlinedOutputs != 1 {
+		t.Fatalf("expected refresh to keep both tasks and inline one output, got %d tasks, %d inlined", len(result.Tasks), result.InlinedOutputs)
+	}
+	if after, _ := os.ReadFile(metricsPath); string(after) != string(before) {
+		t.Fatalf("expected refresh to leave task folders untouched")
+	}
+}
+
+func TestRefreshDaySummaryRequiresBundles(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+	_, err := RefreshDaySummary(Options{Layout: layout, Manifest: man, MaxContextTokens: 8192})
+	if err == nil || !strings.Contains(err.Error(), "tester bundle") {
+		t.Fatalf("expected a missing-bundles error, got %v", err)
+	}
+}
@@ -0,0 +1,3 @@
+// Package bundle turns a completed capture run into per-task and day-summary
+// LLM bundles (prompt, context, metrics) for the manual summarisation workflow.
+package bundle
@@ -0,0 +1,141 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/asr"
+	"github.com/offlinefirst/limitless-context/pkg/events"
+	"github.com/offlinefirst/limitless-context/pkg/ocr"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+	"github.com/offlinefirst/limitless-context/pkg/screenshots"
+	"github.com/offlinefirst/limitless-context/pkg/video"
+)
+
+// eventRecord pairs a captured event with the identifier cited by bundles.
+type eventRecord struct {
+	ID    string
+	Event events.Event
+}
+
+type screenshotRecord struct {
+	Name     string
+	Metadata screenshots.Metadata
+}
+
+type ocrRecord struct {
+	Screenshot string
+	Text       string
+	CapturedAt time.Time
+}
+
+type asrRecord struct {
+	Source string
+	Cue    asr.Cue
+	Start  time.Time
+	End    time.Time
+}
+
+// runInputs holds every artifact the bundler reads from a run directory.
+type runInputs struct {
+	Base        time.Time
+	Events      []eventRecord
+	Screenshots []screenshotRecord
+	OCR         []ocrRecord
+	ASR         []asrRecord
+}
+
+func loadInputs(layout runmanifest.Layout, man runmanifest.Manifest) (runInputs, error) {
+	in := runInputs{Base: man.CaptureStart()}
+
+	finePath := filepath.Join(layout.EventsDir, "events_fine.jsonl")
+	loaded, err := events.ReadFine(finePath)
+	switch {
+	case err == nil:
+		in.Events = make([]eventRecord, 0, len(loaded))
+		for _, event := range loaded {
+			in.Events = append(in.Events, eventRecord{ID: event.ID, Event: event})
+		}
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return runInputs{}, err
+	}
+
+	shots, err := loadScreenshots(layout.ScreensDir)
+	if err != nil {
+		return runInputs{}, err
+	}
+	in.Screenshots = shots
+
+	captured := make(map[string]time.Time, len(shots))
+	for _, shot := range shots {
+		captured[shot.Name] = shot.Metadata.CapturedAt
+	}
+	// OCR also covers keyframes extracted from video.
+	keyframes, err := video.ReadKeyframes(layout.VideoDir)
+	if err != nil {
+		return runInputs{}, err
+	}
+	for _, frame := range keyframes {
+		captured[frame.Name] = frame.Metadata.CapturedAt
+	}
+
+	index, err := ocr.LoadIndex(filepath.Join(layout.OCRDir, "index.json"))
+	switch {
+	case err == nil:
+		for _, entry := range index.Entries {
+			in.OCR = append(in.OCR, ocrRecord{
+				Screenshot: entry.Screenshot,
+				Text:       entry.Text,
+				CapturedAt: captured[stem(entry.Screenshot)],
+			})
+		}
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return runInputs{}, err
+	}
+
+	transcripts, err := filepath.Glob(filepath.Join(layout.ASRDir, "*.vtt"))
+	if err != nil {
+		return runInputs{}, fmt.Errorf("list transcripts: %w", err)
+	}
+	sort.Strings(transcripts)
+	for _, path := range transcripts {
+		cues, err := asr.ReadVTT(path)
+		if err != nil {
+			return runInputs{}, fmt.Errorf("transcript %s: %w", filepath.Base(path), err)
+		}
+		for _, cue := range cues {
+			in.ASR = append(in.ASR, asrRecord{
+				Source: filepath.Base(path),
+				Cue:    cue,
+				Start:  in.Base.Add(cue.Start),
+				End:    in.Base.Add(cue.End),
+			})
+		}
+	}
+
+	return in, nil
+}
+
+func loadScreenshots(dir string) ([]screenshotRecord, error) {
+	records, err := screenshots.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]screenshotRecord, 0, len(records))
+	for _, record := range records {
+		out = append(out, screenshotRecord{Name: record.Name, Metadata: record.Metadata})
+	}
+	return out, nil
+}
+
+func stem(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
@@ -0,0 +1,213 @@
+package bundle
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/prompts"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+//go:embed templates/README_bundles.md.tmpl
+var readmeTemplate string
+
+var readmeTmpl = template.Must(template.New("README_bundles.md").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+	"utc": func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+}).Parse(readmeTemplate))
+
+type readmeData struct {
+	RunID      string
+	CreatedAt  string
+	Warnings   []string
+	Subsystems []readmeSubsystem
+	Tasks      []TaskSummary
+	Contract   []contractField
+}
+
+type readmeSubsystem struct {
+	Name     string
+	Enabled  bool
+	State    string
+	Degraded bool
+	Notes    string
+}
+
+type contractField struct {
+	Name        string
+	Type        string
+	Constraints string
+}
+
+func writeReadme(opts Options, tasks []TaskSummary) (string, error) {
+	contract, err := opts.Templates.Render(prompts.TaskTemplate, prompts.TaskData{RunID: opts.Manifest.RunID, TaskID: "task_NNN"})
+	if err != nil {
+		return "", err
+	}
+	subsystems := readmeSubsystems(opts.Manifest)
+	data := readmeData{
+		RunID:      opts.Manifest.RunID,
+		CreatedAt:  opts.Manifest.CreatedAt.UTC().Format(time.RFC3339),
+		Warnings:   signalWarnings(subsystems),
+		Subsystems: subsystems,
+		Tasks:      tasks,
+		Contract:   contractFields(contract.Schema),
+	}
+
+	var b strings.Builder
+	if err := readmeTmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render README_bundles.md: %w", err)
+	}
+	path := filepath.Join(opts.Layout.BundlesDir, "README_bundles.md")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// readmeSubsystems merges the manifest's capture settings with the recorded
+// subsystem outcomes. A subsystem is degraded when it was enabled but did not
+// complete normally.
+func readmeSubsystems(man runmanifest.Manifest) []readmeSubsystem {
+	enabled := map[string]bool{
+		"video":       man.Capture.VideoEnabled,
+		"screenshots": man.Capture.ScreenshotsEnabled,
+		"events":      man.Capture.EventsEnabled,
+		"asr":         man.Capture.ASREnabled,
+		"ocr":         man.Capture.OCREnabled,
+	}
+	recorded := make(map[string]runmanifest.SubsystemStatus, len(man.Status.Subsystems))
+	for _, status := range man.Status.Subsystems {
+		recorded[status.Name] = status
+	}
+
+	names := make([]string, 0, len(enabled))
+	for name := range enabled {
+		names = append(names, name)
+	}
+	for name := range recorded {
+		if _, ok := enabled[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := make([]readmeSubsystem, 0, len(names))
+	for _, name := range names {
+		entry := readmeSubsystem{Name: name, Enabled: enabled[name], State: "not recorded"}
+		if status, ok := recorded[name]; ok {
+			entry.Enabled = status.Enabled
+			entry.State = status.State
+			entry.Notes = strings.Join(nonEmpty(status.Provider, status.Message), ": ")
+			entry.Degraded = status.Enabled && (!status.Available || degradedState(status.State))
+		}
+		if !entry.Enabled {
+			entry.State = "disabled"
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func degradedState(state string) bool {
+	switch state {
+	case runmanifest.SubsystemStateSkipped, runmanifest.SubsystemStateUnavailable, runmanifest.SubsystemStateErrored:
+		return true
+	}
+	return false
+}
+
+// signalWarnings calls out missing OCR and ASR, whose absence silently thins
+// every task context.
+func signalWarnings(subsystems []readmeSubsystem) []string {
+	var warnings []string
+	for _, s := range subsystems {
+		var missing string
+		switch s.Name {
+		case "asr":
+			missing = "task contexts contain no meeting transcript cues"
+		case "ocr":
+			missing = "task contexts contain no on-screen text"
+		default:
+			continue
+		}
+		label := strings.ToUpper(s.Name)
+		switch {
+		case !s.Enabled:
+			warnings = append(warnings, fmt.Sprintf("%s was disabled for this run, so %s.", label, missing))
+		case s.Degraded:
+			detail := s.State
+			if s.Notes != "" {
+				detail += " — " + s.Notes
+			}
+			warnings = append(warnings, fmt.Sprintf("%s was unavailable (%s), so %s.", label, detail, missing))
+		}
+	}
+	return warnings
+}
+
+// contractFields lists the top-level fields of a reply schema in required order.
+func contractFields(schema *prompts.Schema) []contractField {
+	if schema == nil {
+		return nil
+	}
+	names := append([]string(nil), schema.Required...)
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		seen[name] = true
+	}
+	var optional []string
+	for name := range schema.Properties {
+		if !seen[name] {
+			optional = append(optional, name)
+		}
+	}
+	sort.Strings(optional)
+	names = append(names, optional...)
+
+	fields := make([]contractField, 0, len(names))
+	for _, name := range names {
+		prop := schema.Properties[name]
+		field := contractField{Name: name, Type: prop.Type}
+		if prop.Type == "array" && prop.Items != nil {
+			field.Type = "array of " + prop.Items.Type
+		}
+		notes := describeConstraints(prop)
+		if !seen[name] {
+			notes = append([]string{"optional"}, notes...)
+		}
+		// Patterns may contain alternations, which would split the table cell.
+		field.Constraints = strings.ReplaceAll(strings.Join(notes, "; "), "|", `\|`)
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func describeConstraints(s *prompts.Schema) []string {
+	var notes []string
+	if s.Const != nil {
+		notes = append(notes, fmt.Sprintf("must equal `%v`", s.Const))
+	}
+	if s.MinLength != nil && *s.MinLength > 0 {
+		notes = append(notes, "non-empty")
+	}
+	if s.MaxLength != nil {
+		notes = append(notes, fmt.Sprintf("at most %d characters", *s.MaxLength))
+	}
+	if s.Minimum != nil && s.Maximum != nil {
+		notes = append(notes, fmt.Sprintf("between %v and %v", *s.Minimum, *s.Maximum))
+	}
+	if s.Items != nil && s.Items.Pattern != "" {
+		notes = append(notes, fmt.Sprintf("entries match `%s`", s.Items.Pattern))
+	}
+	if s.Pattern != "" {
+		notes = append(notes, fmt.Sprintf("matches `%s`", s.Pattern))
+	}
+	return notes
+}
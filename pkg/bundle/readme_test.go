@@ -0,0 +1,71 @@
+package bundle
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+func TestReadmeWarnsAboutDegradedSignals(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+	man.Capture.ASREnabled = true
+	man.Capture.OCREnabled = true
+	man.Status.Subsystems = []runmanifest.SubsystemStatus{
+		{Name: "events", Enabled: true, Available: true, State: runmanifest.SubsystemStateCompleted},
+		{Name: "asr", Enabled: true, Available: false, State: runmanifest.SubsystemStateSkipped, Message: "whisper binary not found"},
+		{Name: "ocr", Enabled: true, Available: false, State: runmanifest.SubsystemStateUnavailable, Provider: "tesseract", Message: "not installed"},
+	}
+
+	result, err := Build(Options{Layout: layout, Manifest: man, PerTaskTokenBudget: 5000, MaxContextTokens: 8192})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	data, err := os.ReadFile(result.ReadmePath)
+	if err != nil {
+		t.Fatalf("read README: %v", err)
+	}
+	readme := string(data)
+
+	for _, want := range []string{
+		"# Bundles for run 20240512_093000",
+		"> **Warning:** ASR was unavailable (skipped — whisper binary not found), so task contexts contain no meeting transcript cues.",
+		"> **Warning:** OCR was unavailable (unavailable — tesseract: not installed), so task contexts contain no on-screen text.",
+		"| events | yes | completed |  |",
+		"| ocr | yes | unavailable (degraded) | tesseract: not installed |",
+		"tester process --run 20240512_093000",
+		"| `task_id` | string | must equal `task_NNN` |",
+		"| `confidence` | number | between 0 and 1 |",
+	} {
+		if !strings.Contains(readme, want) {
+			t.Fatalf("README missing %q:\n%s", want, readme)
+		}
+	}
+	first, second := strings.Index(readme, "1. `task_001/`"), strings.Index(readme, "2. `task_002/`")
+	if first < 0 || second < first {
+		t.Fatalf("expected tasks in timeline order:\n%s", readme)
+	}
+}
+
+func TestReadmeWarnsWhenSignalsDisabled(t *testing.T) {
+	layout, man := writeFixtureRun(t)
+	man.Capture.ASREnabled = false
+	man.Capture.OCREnabled = true
+
+	result, err := Build(Options{Layout: layout, Manifest: man, PerTaskTokenBudget: 5000, MaxContextTokens: 8192})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	data, err := os.ReadFile(result.ReadmePath)
+	if err != nil {
+		t.Fatalf("read README: %v", err)
+	}
+	readme := string(data)
+	if !strings.Contains(readme, "ASR was disabled for this run") {
+		t.Fatalf("expected disabled ASR warning:\n%s", readme)
+	}
+	if strings.Contains(readme, "OCR was") {
+		t.Fatalf("unexpected OCR warning:\n%s", readme)
+	}
+}
@@ -0,0 +1,147 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/screenshots"
+)
+
+type contextEvent struct {
+	ID        string            `json:"id"`
+	Timestamp time.Time         `json:"timestamp"`
+	Category  string            `json:"category"`
+	Action    string            `json:"action"`
+	Target    string            `json:"target,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// contextParts holds the separately measured sections of context.md.
+type contextParts struct {
+	Header string
+	Events string
+	OCR    string
+	ASR    string
+}
+
+func renderContext(runID string, base time.Time, t *task) string {
+	parts := contextSections(runID, base, t)
+	return parts.Header + parts.Events + parts.OCR + parts.ASR
+}
+
+func contextSections(runID string, base time.Time, t *task) contextParts {
+	var parts contextParts
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s context\n\n", t.ID)
+	fmt.Fprintf(&b, "- Run: %s\n", runID)
+	fmt.Fprintf(&b, "- Window: %s → %s (%s)\n", t.Start.UTC().Format(time.RFC3339), t.End.UTC().Format(time.RFC3339), t.End.Sub(t.Start).Round(time.Second))
+	fmt.Fprintf(&b, "- Items: %d events, %d screenshots, %d OCR entries, %d ASR cues\n", len(t.Events), len(t.Screenshots), len(t.OCR), len(t.ASR))
+	if t.DominantApp != "" || t.DominantURL != "" {
+		fmt.Fprintf(&b, "- Focus: %s\n", strings.Join(nonEmpty(t.DominantApp, t.DominantURL), " — "))
+	}
+	if len(t.Reasons) > 0 {
+		fmt.Fprintf(&b, "- Promoted: %s\n", strings.Join(t.Reasons, ", "))
+	}
+	if t.Omitted > 0 {
+		fmt.Fprintf(&b, "- Omitted: %d item(s) trimmed to fit the token budget (listed in metrics.json)\n", t.Omitted)
+	}
+	parts.Header = b.String()
+
+	b.Reset()
+	fmt.Fprintf(&b, "\n## Events\n\n")
+	if len(t.Events) == 0 {
+		b.WriteString("_No events captured for this task._\n")
+	}
+	for _, record := range t.Events {
+		b.WriteString("- ")
+		b.WriteString(eventLine(record))
+		b.WriteString("\n")
+	}
+	parts.Events = b.String()
+
+	b.Reset()
+	fmt.Fprintf(&b, "\n## OCR\n\n")
+	if len(t.OCR) == 0 {
+		b.WriteString("_No OCR text captured for this task._\n")
+	}
+	for _, entry := range t.OCR {
+		b.WriteString("- ")
+		b.WriteString(ocrLine(base, entry))
+		b.WriteString("\n")
+	}
+	parts.OCR = b.String()
+
+	b.Reset()
+	fmt.Fprintf(&b, "\n## ASR\n\n")
+	if len(t.ASR) == 0 {
+		b.WriteString("_No meeting transcript for this task._\n")
+	}
+	for _, cue := range t.ASR {
+		b.WriteString("- ")
+		b.WriteString(asrLine(cue))
+		b.WriteString("\n")
+	}
+	parts.ASR = b.String()
+
+	return parts
+}
+
+func eventLine(record eventRecord) string {
+	payload := contextEvent{
+		ID:        record.ID,
+		Timestamp: record.Event.Timestamp.UTC(),
+		Category:  record.Event.Category,
+		Action:    record.Event.Action,
+		Target:    record.Event.Target,
+		Metadata:  record.Event.Metadata,
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(payload); err != nil {
+		return fmt.Sprintf(`{"id":%q}`, record.ID)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+func ocrLine(base time.Time, entry ocrRecord) string {
+	return fmt.Sprintf("[%s] %s: %s", shotRef(base, entry.CapturedAt), entry.Screenshot, singleLine(entry.Text))
+}
+
+func asrLine(cue asrRecord) string {
+	return fmt.Sprintf("[%s–%s] %s: %s", formatOffset(cue.Cue.Start), formatOffset(cue.Cue.End), cue.Source, singleLine(cue.Cue.Text))
+}
+
+// shotRef formats the evidence anchor for a screenshot-derived item.
+func shotRef(base, capturedAt time.Time) string {
+	if capturedAt.IsZero() {
+		return "shot:unknown"
+	}
+	return screenshots.Ref(base, capturedAt)
+}
+
+func nonEmpty(values ...string) []string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+// formatOffset renders a duration as mm:ss, letting minutes exceed 59 for long runs.
+func formatOffset(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	total := int(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%02d:%02d", total/60, total%60)
+}
+
+func singleLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
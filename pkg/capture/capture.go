@@ -29,6 +29,9 @@ type Options struct {
 	Logger  *slog.Logger
 	Clock   func() time.Time
 	Control *Controller
+	// Sleeper waits between frames of video backends paced to Clock. Nil
+	// waits in real time.
+	Sleeper func(context.Context, time.Duration) error
 }
 
 // Summary reports the results of enabled subsystems.
@@ -36,6 +39,7 @@ type Summary struct {
 	Events      *events.Result
 	Screenshots *screenshots.Result
 	Video       *video.Result
+	Keyframes   *video.KeyframeResult
 	ASR         *asr.Result
 	OCR         *ocr.Result
 	Lifecycle   *LifecycleSummary
@@ -255,6 +259,35 @@ func Run(ctx context.Context, opts Options) (summary Summary, err error) {
 		notifyScreenshotsUnavailable()
 	}
 
+	// Keyframes extracted from video feed OCR alongside screenshots.
+	var keyframeFilesMu sync.Mutex
+	var keyframeFiles []string
+	keyframesReady := make(chan struct{})
+	var keyframesReadyOnce sync.Once
+	notifyKeyframesReady := func(files []string) {
+		keyframeFilesMu.Lock()
+		keyframeFiles = append([]string(nil), files...)
+		keyframeFilesMu.Unlock()
+		keyframesReadyOnce.Do(func() { close(keyframesReady) })
+	}
+	notifyKeyframesUnavailable := func() {
+		keyframesReadyOnce.Do(func() { close(keyframesReady) })
+	}
+	if !baseStatuses["video"].Enabled || !baseStatuses["video"].Available || !opts.Config.Capture.OCREnabled {
+		notifyKeyframesUnavailable()
+	}
+
+	// releaseWaiters unblocks OCR when a subsystem it waits on ends without
+	// producing files.
+	releaseWaiters := func(name string) {
+		switch name {
+		case "screenshots":
+			notifyScreenshotsUnavailable()
+		case "video":
+			notifyKeyframesUnavailable()
+		}
+	}
+
 	var errOnce sync.Once
 	var runErr error
 
@@ -313,10 +346,42 @@ func Run(ctx context.Context, opts Options) (summary Summary, err error) {
 			status: baseStatuses["video"],
 			run: func(runCtx context.Context) (string, func(*Summary), error) {
 				opts.Logger.Info("starting video capture", "provider", videoEnv.Provider)
+				// Forward pauses so the recorder closes the current segment
+				// instead of recording through them.
+				changes, unsubscribe := controller.Subscribe()
+				defer unsubscribe()
+				pauses := make(chan struct{}, 1)
+				go func() {
+					for change := range changes {
+						if change.State != "paused" {
+							continue
+						}
+						select {
+						case pauses <- struct{}{}:
+						default:
+						}
+					}
+				}()
+				var deadline time.Time
+				if duration > 0 {
+					deadline = start.Add(duration)
+				}
+				width, height, err := opts.Config.Capture.Video.Dimensions()
+				if err != nil {
+					return "", nil, err
+				}
 				recorder, err := video.NewRecorder(video.Options{
 					ChunkSeconds: opts.Config.Capture.Video.ChunkSeconds,
 					Format:       opts.Config.Capture.Video.Format,
 					Clock:        clock,
+					Deadline:     deadline,
+					Wait:         controller.Wait,
+					Pauses:       pauses,
+					FPS:          opts.Config.Capture.Video.FPS,
+					Width:        width,
+					Height:       height,
+					Backend:      videoEnv.Provider,
+					Sleeper:      opts.Sleeper,
 				})
 				if err != nil {
 					return "", nil, err
@@ -326,9 +391,37 @@ func Run(ctx context.Context, opts Options) (summary Summary, err error) {
 					opts.Logger.Error("video recorder encountered error", "error", err)
 					return "", nil, err
 				}
-				logCapture(clock(), "video", "captured segment %s", res.File)
-				opts.Logger.Info("video capture complete", "file", res.File)
-				return fmt.Sprintf("segment -> %s", res.File), func(s *Summary) { s.Video = &res }, nil
+				logCapture(clock(), "video", "captured %d segments (%s)", len(res.Segments), res.IndexPath)
+				opts.Logger.Info("video capture complete", "segments", len(res.Segments), "index", res.IndexPath)
+				message := fmt.Sprintf("%d segments -> %s", len(res.Segments), res.IndexPath)
+
+				// Extraction is post-capture work, so it runs under ctx
+				// rather than runCtx, which ends when recording stops.
+				var frames *video.KeyframeResult
+				if opts.Config.Capture.OCREnabled && len(res.Segments) > 0 {
+					extracted, err := video.ExtractKeyframes(ctx, opts.Layout.VideoDir, video.KeyframeOptions{
+						Interval:       time.Duration(opts.Config.Capture.Video.KeyframeIntervalSeconds) * time.Second,
+						SceneThreshold: float64(opts.Config.Capture.Video.SceneChangePercent) / 100,
+					})
+					if err != nil {
+						logCapture(clock(), "video", "keyframe extraction failed: %v", err)
+						opts.Logger.Warn("keyframe extraction failed", "error", err)
+					} else {
+						frames = &extracted
+						notifyKeyframesReady(extracted.Files)
+						for _, note := range extracted.Skipped {
+							logCapture(clock(), "video", "keyframes skipped %s", note)
+						}
+						logCapture(clock(), "video", "extracted %d keyframes (%d scene changes)", len(extracted.Files), extracted.SceneChanges)
+						opts.Logger.Info("keyframe extraction complete", "frames", len(extracted.Files), "scene_changes", extracted.SceneChanges, "skipped_segments", len(extracted.Skipped))
+						message = fmt.Sprintf("%s; %d keyframes", message, len(extracted.Files))
+					}
+				}
+				notifyKeyframesUnavailable()
+				return message, func(s *Summary) {
+					s.Video = &res
+					s.Keyframes = frames
+				}, nil
 			},
 		},
 		{
@@ -376,19 +469,23 @@ func Run(ctx context.Context, opts Options) (summary Summary, err error) {
 				if err != nil {
 					return "", nil, err
 				}
+				// OCR processes what capture produced, so like keyframe
+				// extraction it outlives runCtx and only ctx cancels it.
 				inputs := make([]string, 0)
-				if opts.Config.Capture.ScreenshotsEnabled {
+				for _, ready := range []chan struct{}{screenshotReady, keyframesReady} {
 					select {
-					case <-screenshotReady:
-					case <-runCtx.Done():
-						notifyScreenshotsUnavailable()
-						return "", nil, runCtx.Err()
+					case <-ready:
+					case <-ctx.Done():
+						return "", nil, ctx.Err()
 					}
 				}
 				screenshotFilesMu.Lock()
 				inputs = append(inputs, screenshotFiles...)
 				screenshotFilesMu.Unlock()
-				res, err := worker.Process(runCtx, inputs, opts.Layout.OCRDir)
+				keyframeFilesMu.Lock()
+				inputs = append(inputs, keyframeFiles...)
+				keyframeFilesMu.Unlock()
+				res, err := worker.Process(ctx, inputs, opts.Layout.OCRDir)
 				if err != nil {
 					return "", nil, err
 				}
@@ -413,9 +510,7 @@ func Run(ctx context.Context, opts Options) (summary Summary, err error) {
 				status.State = runmanifest.SubsystemStateSkipped
 				logCapture(clock(), runner.name, "skipped (%s)", status.Message)
 				opts.Logger.Info(fmt.Sprintf("%s disabled via config", runner.name))
-				if runner.name == "screenshots" {
-					notifyScreenshotsUnavailable()
-				}
+				releaseWaiters(runner.name)
 				recordStatus(status)
 				return
 			}
@@ -426,9 +521,7 @@ func Run(ctx context.Context, opts Options) (summary Summary, err error) {
 				status.State = runmanifest.SubsystemStateUnavailable
 				logCapture(clock(), runner.name, "unavailable (%s)", status.Message)
 				opts.Logger.Warn(fmt.Sprintf("%s unavailable", runner.name), "message", status.Message)
-				if runner.name == "screenshots" {
-					notifyScreenshotsUnavailable()
-				}
+				releaseWaiters(runner.name)
 				recordStatus(status)
 				return
 			}
@@ -443,6 +536,12 @@ func Run(ctx context.Context, opts Options) (summary Summary, err error) {
 						summary.Lifecycle.TerminationCause = "duration_elapsed"
 					}
 					summaryMu.Unlock()
+				case errors.Is(err, ErrStopRequested):
+					status.State = runmanifest.SubsystemStateSkipped
+					status.Message = "not run (stop requested)"
+				case errors.Is(err, ErrInterrupted):
+					status.State = runmanifest.SubsystemStateSkipped
+					status.Message = "not run (interrupted)"
 				case errors.Is(err, context.Canceled):
 					status.State = runmanifest.SubsystemStateSkipped
 					if status.Message == "" {
@@ -453,23 +552,19 @@ func Run(ctx context.Context, opts Options) (summary Summary, err error) {
 					status.Message = err.Error()
 					errOnce.Do(func() { runErr = err })
 				}
-				if runner.name == "screenshots" {
-					notifyScreenshotsUnavailable()
-				}
+				releaseWaiters(runner.name)
 				recordStatus(status)
 				return
 			}
 
 			message, apply, execErr := runner.run(runCtx)
 			if execErr != nil {
-				if errors.Is(execErr, context.Canceled) {
+				releaseWaiters(runner.name)
+				if errors.Is(execErr, context.Canceled) || stoppedEarly(execErr) {
 					status.State = runmanifest.SubsystemStateSkipped
 					if status.Message == "" {
 						status.Message = "canceled"
 					}
-					if runner.name == "screenshots" {
-						notifyScreenshotsUnavailable()
-					}
 					recordStatus(status)
 					return
 				}
@@ -482,7 +577,6 @@ func Run(ctx context.Context, opts Options) (summary Summary, err error) {
 						return
 					}
 				case "screenshots":
-					notifyScreenshotsUnavailable()
 					if errors.Is(execErr, screenshots.ErrPermissionRequired) {
 						status.State = runmanifest.SubsystemStateUnavailable
 						status.Message = joinMessage(status.Message, []string{execErr.Error()})
@@ -502,9 +596,6 @@ func Run(ctx context.Context, opts Options) (summary Summary, err error) {
 				recordStatus(status)
 				controller.Kill(execErr)
 				errOnce.Do(func() { runErr = execErr })
-				if runner.name == "screenshots" {
-					notifyScreenshotsUnavailable()
-				}
 				return
 			}
 
@@ -545,6 +636,14 @@ func Run(ctx context.Context, opts Options) (summary Summary, err error) {
 		if summary.Lifecycle.FinishedAt.IsZero() {
 			summary.Lifecycle.FinishedAt = clock()
 		}
+		if summary.Lifecycle.TerminationCause == "" {
+			switch stopErr := controller.Err(); {
+			case errors.Is(stopErr, ErrStopRequested):
+				summary.Lifecycle.TerminationCause = "stop_requested"
+			case errors.Is(stopErr, ErrInterrupted):
+				summary.Lifecycle.TerminationCause = "interrupted"
+			}
+		}
 		if summary.Lifecycle.TerminationCause == "" {
 			if runErr != nil {
 				summary.Lifecycle.TerminationCause = "error"
@@ -561,6 +660,12 @@ func Run(ctx context.Context, opts Options) (summary Summary, err error) {
 	return summary, err
 }
 
+// stoppedEarly reports whether err is one of the controller's non-failure stop
+// errors, which a subsystem can see from Wait before runCtx is canceled.
+func stoppedEarly(err error) bool {
+	return errors.Is(err, ErrDurationElapsed) || errors.Is(err, ErrStopRequested) || errors.Is(err, ErrInterrupted)
+}
+
 func messageSlice(value string) []string {
 	if value == "" {
 		return nil
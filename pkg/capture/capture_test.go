@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/offlinefirst/limitless-context/pkg/config"
+	"github.com/offlinefirst/limitless-context/pkg/ocr"
 	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
 	"github.com/offlinefirst/limitless-context/pkg/video"
 )
@@ -106,9 +107,16 @@ func TestRunExecutesEnabledSubsystems(t *testing.T) {
 	if _, err := os.Stat(filepath.Join(layout.ScreensDir, "screenshot_001.json")); err != nil {
 		t.Fatalf("expected screenshot metadata output: %v", err)
 	}
-	if _, err := os.Stat(filepath.Join(layout.VideoDir, "segment_20240501T100000.mp4")); err != nil {
+	if _, err := os.Stat(filepath.Join(layout.VideoDir, "segment_0001_20240501T100000.mp4")); err != nil {
 		t.Fatalf("expected video stub output: %v", err)
 	}
+	// A 60-minute run with 300s chunks records twelve segments.
+	if len(summary.Video.Segments) != 12 || !summary.Video.Ended.Equal(base.Add(time.Hour)) {
+		t.Fatalf("expected twelve segments covering the run, got %d ending %s", len(summary.Video.Segments), summary.Video.Ended)
+	}
+	if _, err := os.Stat(filepath.Join(layout.VideoDir, video.IndexName)); err != nil {
+		t.Fatalf("expected video segment index: %v", err)
+	}
 	if _, err := os.Stat(filepath.Join(layout.ASRDir, "status.json")); err != nil {
 		t.Fatalf("expected ASR status: %v", err)
 	}
@@ -244,6 +252,153 @@ func TestRunStopsWhenDurationElapsed(t *testing.T) {
 	}
 }
 
+func TestRunStopRequestedIsNotAFailure(t *testing.T) {
+	installVideoFake(t)
+
+	cfg := config.Default()
+	layout := runmanifest.BuildLayout(t.TempDir(), "stopped")
+	if err := runmanifest.EnsureFilesystem(layout); err != nil {
+		t.Fatalf("ensure filesystem: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	controller := NewController()
+	controller.Stop("operator via tester stop")
+
+	summary, err := Run(context.Background(), Options{Config: cfg, Layout: layout, Logger: logger, Control: controller})
+	if err != nil {
+		t.Fatalf("expected nil error when stop was requested, got %v", err)
+	}
+	if summary.Lifecycle == nil || summary.Lifecycle.TerminationCause != "stop_requested" {
+		t.Fatalf("expected stop_requested termination, got %#v", summary.Lifecycle)
+	}
+	timeline := summary.Lifecycle.ControllerTimeline
+	if len(timeline) == 0 || timeline[0].State != "stopping" {
+		t.Fatalf("expected stopping in the controller timeline, got %+v", timeline)
+	}
+	for _, status := range summary.Subsystems {
+		if status.State == runmanifest.SubsystemStateErrored {
+			t.Fatalf("%s should not error on a requested stop: %+v", status.Name, status)
+		}
+	}
+}
+
+func TestRunInterruptedRecordsTermination(t *testing.T) {
+	installVideoFake(t)
+
+	cfg := config.Default()
+	layout := runmanifest.BuildLayout(t.TempDir(), "interrupted")
+	if err := runmanifest.EnsureFilesystem(layout); err != nil {
+		t.Fatalf("ensure filesystem: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	controller := NewController()
+	controller.Interrupt("received interrupt")
+
+	summary, err := Run(context.Background(), Options{Config: cfg, Layout: layout, Logger: logger, Control: controller})
+	if err != nil {
+		t.Fatalf("expected nil error when interrupted, got %v", err)
+	}
+	if summary.Lifecycle == nil || summary.Lifecycle.TerminationCause != "interrupted" {
+		t.Fatalf("expected interrupted termination, got %#v", summary.Lifecycle)
+	}
+	for _, status := range summary.Subsystems {
+		if status.Enabled && status.Available && status.Message != "not run (interrupted)" {
+			t.Fatalf("%s should be skipped as interrupted: %+v", status.Name, status)
+		}
+	}
+}
+
+func TestRunWithSyntheticVideoBackend(t *testing.T) {
+	t.Setenv("LIMITLESS_VIDEO_BACKEND", "synthetic")
+
+	cfg := config.Default()
+	cfg.Capture.DurationMinutes = 1
+	cfg.Capture.Video.ChunkSeconds = 30
+	cfg.Capture.Video.FPS = 1
+	cfg.Capture.Video.Resolution = "320x180"
+	cfg.Capture.Screenshots.IntervalSeconds = 1
+	cfg.Capture.Screenshots.MaxPerMinute = 1
+	layout := runmanifest.BuildLayout(t.TempDir(), "synthetic")
+	if err := runmanifest.EnsureFilesystem(layout); err != nil {
+		t.Fatalf("ensure filesystem: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	summary, err := Run(context.Background(), Options{Config: cfg, Layout: layout, Logger: logger, Clock: func() time.Time { return base }, Sleeper: noWait})
+	if err != nil {
+		t.Fatalf("run capture: %v", err)
+	}
+	if summary.Video == nil || len(summary.Video.Segments) != 2 {
+		t.Fatalf("expected two synthetic segments, got %+v", summary.Video)
+	}
+	for _, status := range summary.Subsystems {
+		if status.Name == "video" && (status.State != runmanifest.SubsystemStateCompleted || status.Provider != video.ProviderSynthetic) {
+			t.Fatalf("unexpected video status: %+v", status)
+		}
+	}
+	info, err := os.Stat(filepath.Join(layout.VideoDir, "segment_0001_20240501T100000.avi"))
+	if err != nil || info.Size() == 0 {
+		t.Fatalf("expected a non-empty AVI segment: %v", err)
+	}
+	meta, err := video.LoadSegmentMetadata(summary.Video.Segments[0].Sidecar)
+	if err != nil {
+		t.Fatalf("load segment metadata: %v", err)
+	}
+	if meta.Backend != video.ProviderSynthetic || meta.FPS != 1 || meta.Resolution != "320x180" || meta.Bytes != info.Size() {
+		t.Fatalf("unexpected segment metadata: %+v", meta)
+	}
+}
+
+func TestRunVideoOnlyFeedsKeyframesToOCR(t *testing.T) {
+	t.Setenv("LIMITLESS_VIDEO_BACKEND", "synthetic")
+
+	cfg := config.Default()
+	cfg.Capture.DurationMinutes = 1
+	cfg.Capture.ScreenshotsEnabled = false
+	cfg.Capture.EventsEnabled = false
+	cfg.Capture.ASREnabled = false
+	cfg.Capture.Video.ChunkSeconds = 30
+	cfg.Capture.Video.FPS = 1
+	cfg.Capture.Video.Resolution = "320x180"
+	cfg.Capture.Video.KeyframeIntervalSeconds = 20
+	layout := runmanifest.BuildLayout(t.TempDir(), "video-only")
+	if err := runmanifest.EnsureFilesystem(layout); err != nil {
+		t.Fatalf("ensure filesystem: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	summary, err := Run(context.Background(), Options{Config: cfg, Layout: layout, Logger: logger, Clock: func() time.Time { return base }, Sleeper: noWait})
+	if err != nil {
+		t.Fatalf("run capture: %v", err)
+	}
+	// Two 30s segments sampled every 20s give frames at +0s and +20s each.
+	if summary.Keyframes == nil || len(summary.Keyframes.Files) != 4 {
+		t.Fatalf("expected four keyframes, got %+v", summary.Keyframes)
+	}
+	if summary.OCR == nil || summary.OCR.ProcessedCount != 4 {
+		t.Fatalf("expected OCR over the keyframes, got %+v", summary.OCR)
+	}
+	index, err := ocr.LoadIndex(summary.OCR.IndexPath)
+	if err != nil {
+		t.Fatalf("load ocr index: %v", err)
+	}
+	if index.Entries[0].Screenshot != "frame_0001.png" || !strings.Contains(index.Entries[0].Text, video.KeyframeBackend) {
+		t.Fatalf("unexpected ocr entry: %+v", index.Entries[0])
+	}
+	for _, status := range summary.Subsystems {
+		if status.Name == "video" && !strings.HasSuffix(status.Message, "; 4 keyframes") {
+			t.Fatalf("unexpected video status: %+v", status)
+		}
+	}
+}
+
+// noWait lets synthetic video render its frames without waiting in real time.
+func noWait(context.Context, time.Duration) error { return nil }
+
 func installVideoFake(t *testing.T) {
 	video.SetNativeFactory(func(format string) (video.NativeRecorder, error) {
 		return &captureFakeRecorder{format: format}, nil
@@ -253,6 +408,13 @@ func installVideoFake(t *testing.T) {
 
 type captureFakeRecorder struct {
 	format string
+	last   time.Duration
+}
+
+// LastDuration reports the requested length: the fake writes instantly, so
+// the clock cannot measure it.
+func (f *captureFakeRecorder) LastDuration() time.Duration {
+	return f.last
 }
 
 func (f *captureFakeRecorder) Record(ctx context.Context, dest, filename string, started time.Time, duration time.Duration) (string, error) {
@@ -270,5 +432,6 @@ func (f *captureFakeRecorder) Record(ctx context.Context, dest, filename string,
 	if err := os.WriteFile(path, []byte(payload), 0o644); err != nil {
 		return "", err
 	}
+	f.last = duration
 	return path, nil
 }
@@ -2,9 +2,18 @@ package capture
 
 import (
 	"context"
+	"errors"
 	"sync"
 )
 
+// ErrStopRequested is the stop error recorded when an operator ends capture
+// early. Capture treats it like an elapsed duration rather than a failure.
+var ErrStopRequested = errors.New("stop requested")
+
+// ErrInterrupted is the stop error recorded when the process is asked to
+// exit, for example by SIGINT. Like ErrStopRequested it is not a failure.
+var ErrInterrupted = errors.New("interrupted")
+
 // StateChange represents an observable controller state transition.
 type StateChange struct {
 	State  string
@@ -29,26 +38,64 @@ func NewController() *Controller {
 
 // Pause transitions the controller into a paused state.
 func (c *Controller) Pause() {
+	c.PauseWithReason("pause requested")
+}
+
+// PauseWithReason pauses and records reason on the transition. It reports
+// whether the state changed; pausing while paused or stopping is a no-op.
+func (c *Controller) PauseWithReason(reason string) bool {
 	c.mu.Lock()
-	if c.paused {
-		c.mu.Unlock()
-		return
+	defer c.mu.Unlock()
+	if c.paused || c.stopping {
+		return false
 	}
 	c.paused = true
-	c.broadcastLocked(StateChange{State: "paused", Reason: "pause requested"})
-	c.mu.Unlock()
+	c.broadcastLocked(StateChange{State: "paused", Reason: reason})
+	return true
 }
 
 // Resume clears a paused state and notifies waiters.
 func (c *Controller) Resume() {
+	c.ResumeWithReason("resumed")
+}
+
+// ResumeWithReason resumes and records reason on the transition. It reports
+// whether the state changed.
+func (c *Controller) ResumeWithReason(reason string) bool {
 	c.mu.Lock()
-	alreadyRunning := !c.paused
+	defer c.mu.Unlock()
+	if !c.paused || c.stopping {
+		return false
+	}
 	c.paused = false
-	if !alreadyRunning {
-		c.broadcastLocked(StateChange{State: "running", Reason: "resumed"})
-		c.notifyAllLocked()
+	c.broadcastLocked(StateChange{State: "running", Reason: reason})
+	c.notifyAllLocked()
+	return true
+}
+
+// Stop ends capture early with ErrStopRequested and records reason on the
+// transition. It reports whether the state changed.
+func (c *Controller) Stop(reason string) bool {
+	return c.stopWith(ErrStopRequested, reason)
+}
+
+// Interrupt ends capture early with ErrInterrupted and records reason on the
+// transition. It reports whether the state changed.
+func (c *Controller) Interrupt(reason string) bool {
+	return c.stopWith(ErrInterrupted, reason)
+}
+
+func (c *Controller) stopWith(err error, reason string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopping {
+		return false
 	}
-	c.mu.Unlock()
+	c.stopping = true
+	c.stopErr = err
+	c.broadcastLocked(StateChange{State: "stopping", Reason: reason})
+	c.notifyAllLocked()
+	return true
 }
 
 // Kill requests subsystems to stop and propagates an optional error.
@@ -110,6 +157,13 @@ func (c *Controller) Wait(ctx context.Context) error {
 	}
 }
 
+// Err returns the error capture was stopped with, or nil.
+func (c *Controller) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stopErr
+}
+
 // State reports the textual state for diagnostics.
 func (c *Controller) State() string {
 	c.mu.Lock()
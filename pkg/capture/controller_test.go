@@ -76,3 +76,42 @@ func TestControllerWaitRespectsContextCancellation(t *testing.T) {
 		t.Fatalf("controller wait did not exit on cancellation")
 	}
 }
+
+func TestControllerTransitionsCarryReasons(t *testing.T) {
+	controller := NewController()
+	changes, cancel := controller.Subscribe()
+	defer cancel()
+	<-changes
+
+	if !controller.PauseWithReason("operator lunch") || controller.PauseWithReason("again") {
+		t.Fatalf("expected only the first pause to change state")
+	}
+	if change := <-changes; change.State != "paused" || change.Reason != "operator lunch" {
+		t.Fatalf("unexpected pause transition: %+v", change)
+	}
+	if !controller.ResumeWithReason("back") {
+		t.Fatalf("expected resume to change state")
+	}
+	if change := <-changes; change.State != "running" || change.Reason != "back" {
+		t.Fatalf("unexpected resume transition: %+v", change)
+	}
+	if !controller.Stop("done for the day") || controller.Stop("twice") || controller.PauseWithReason("late") {
+		t.Fatalf("expected a single stop and no transitions after it")
+	}
+	if change := <-changes; change.State != "stopping" || change.Reason != "done for the day" {
+		t.Fatalf("unexpected stop transition: %+v", change)
+	}
+	if err := controller.Wait(context.Background()); !errors.Is(err, ErrStopRequested) || !errors.Is(controller.Err(), ErrStopRequested) {
+		t.Fatalf("expected ErrStopRequested, got %v", err)
+	}
+}
+
+func TestControllerInterrupt(t *testing.T) {
+	controller := NewController()
+	if !controller.Interrupt("received interrupt") || controller.Stop("late") || controller.Interrupt("again") {
+		t.Fatalf("expected only the first stop transition to apply")
+	}
+	if err := controller.Wait(context.Background()); !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("expected ErrInterrupted, got %v", err)
+	}
+}
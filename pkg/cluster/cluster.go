@@ -0,0 +1,328 @@
+// Package cluster groups sessionized events into task clusters by the
+// dominant app/url/file in focus, promoting clusters that contain build,
+// modal, or form-submit activity and folding short fragments into their
+// neighbours.
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/events"
+	"github.com/offlinefirst/limitless-context/pkg/screenshots"
+	"github.com/offlinefirst/limitless-context/pkg/sessionize"
+)
+
+// DefaultMinDuration is the span below which an unpromoted cluster is merged
+// into an adjacent cluster from the same session.
+const DefaultMinDuration = 45 * time.Second
+
+// FileName is the clusters document written under bundles/.
+const FileName = "clusters.json"
+
+// Promotion reasons recorded on clusters.
+const (
+	ReasonBuild      = "build"
+	ReasonModal      = "modal"
+	ReasonFormSubmit = "form_submit"
+)
+
+// Options configure clustering.
+type Options struct {
+	MinDuration time.Duration
+}
+
+// Record pairs an event with the identifier sessions.json refers to.
+type Record struct {
+	ID    string
+	Event events.Event
+}
+
+// Screenshot names a captured frame and its metadata.
+type Screenshot struct {
+	Name     string
+	Metadata screenshots.Metadata
+}
+
+// TaskCluster is one unit of work handed to the bundler.
+type TaskCluster struct {
+	ID           string    `json:"id"`
+	SessionID    string    `json:"session_id"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	DominantApp  string    `json:"dominant_app,omitempty"`
+	DominantURL  string    `json:"dominant_url,omitempty"`
+	DominantFile string    `json:"dominant_file,omitempty"`
+	Promoted     bool      `json:"promoted"`
+	Reasons      []string  `json:"reasons,omitempty"`
+	EventIDs     []string  `json:"event_ids"`
+	Screenshots  []string  `json:"screenshots,omitempty"`
+}
+
+// File is the document persisted to bundles/clusters.json.
+type File struct {
+	MinDurationSeconds float64       `json:"min_duration_seconds"`
+	Clusters           []TaskCluster `json:"clusters"`
+}
+
+// focus identifies what the user was working on.
+type focus struct {
+	App  string
+	URL  string
+	File string
+}
+
+func (f focus) empty() bool { return f == focus{} }
+
+// slice is a working cluster before ids are assigned.
+type slice struct {
+	session string
+	focus   focus
+	records []Record
+}
+
+func (s *slice) start() time.Time { return s.records[0].Event.Timestamp.UTC() }
+func (s *slice) end() time.Time   { return s.records[len(s.records)-1].Event.Timestamp.UTC() }
+
+func (s *slice) promoted() bool { return len(reasons(s.records)) > 0 }
+
+// Cluster splits each session on focus changes, merges short unpromoted
+// fragments, and attaches screenshots to the closest cluster. The output
+// depends only on its inputs, so numbering is stable across runs.
+func Cluster(sessions []sessionize.Session, records []Record, shots []Screenshot, opts Options) File {
+	minDuration := opts.MinDuration
+	if minDuration <= 0 {
+		minDuration = DefaultMinDuration
+	}
+
+	byID := make(map[string]Record, len(records))
+	for _, record := range records {
+		byID[record.ID] = record
+	}
+
+	var slices []*slice
+	for _, session := range sessions {
+		members := make([]Record, 0, len(session.EventIDs))
+		for _, id := range session.EventIDs {
+			if record, ok := byID[id]; ok {
+				members = append(members, record)
+			}
+		}
+		sort.SliceStable(members, func(i, j int) bool { return members[i].Event.Timestamp.Before(members[j].Event.Timestamp) })
+		slices = append(slices, merge(split(session.ID, members), minDuration)...)
+	}
+
+	out := File{MinDurationSeconds: minDuration.Seconds(), Clusters: make([]TaskCluster, 0, len(slices))}
+	for i, s := range slices {
+		dominant := dominantFocus(s.records)
+		ids := make([]string, 0, len(s.records))
+		for _, record := range s.records {
+			ids = append(ids, record.ID)
+		}
+		why := reasons(s.records)
+		out.Clusters = append(out.Clusters, TaskCluster{
+			ID:           clusterID(i + 1),
+			SessionID:    s.session,
+			Start:        s.start(),
+			End:          s.end(),
+			DominantApp:  dominant.App,
+			DominantURL:  dominant.URL,
+			DominantFile: dominant.File,
+			Promoted:     len(why) > 0,
+			Reasons:      why,
+			EventIDs:     ids,
+		})
+	}
+
+	if len(out.Clusters) > 0 {
+		for _, shot := range shots {
+			c := nearest(out.Clusters, shot.Metadata.CapturedAt)
+			c.Screenshots = append(c.Screenshots, shot.Name)
+		}
+	}
+	return out
+}
+
+// split starts a new slice whenever an event names a different focus. Events
+// without app/url/file metadata inherit the current focus.
+func split(session string, members []Record) []*slice {
+	var out []*slice
+	for _, record := range members {
+		f := eventFocus(record.Event)
+		if len(out) == 0 || (!f.empty() && !out[len(out)-1].focus.empty() && f != out[len(out)-1].focus) {
+			out = append(out, &slice{session: session, focus: f})
+		}
+		current := out[len(out)-1]
+		if current.focus.empty() {
+			current.focus = f
+		}
+		current.records = append(current.records, record)
+	}
+	return out
+}
+
+// merge folds the earliest short, unpromoted slice into its predecessor (or
+// successor when it is first) until every remaining slice is long enough,
+// promoted, or alone in its session.
+func merge(slices []*slice, minDuration time.Duration) []*slice {
+	for len(slices) > 1 {
+		target := -1
+		for i, s := range slices {
+			if s.end().Sub(s.start()) < minDuration && !s.promoted() {
+				target = i
+				break
+			}
+		}
+		if target < 0 {
+			break
+		}
+		into := target - 1
+		if into < 0 {
+			into = 1
+		}
+		lo, hi := into, target
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		slices[lo].records = append(slices[lo].records, slices[hi].records...)
+		slices = append(slices[:hi], slices[hi+1:]...)
+	}
+	return slices
+}
+
+func eventFocus(event events.Event) focus {
+	return focus{
+		App:  strings.TrimSpace(event.Metadata["app"]),
+		URL:  strings.TrimSpace(event.Metadata["url"]),
+		File: strings.TrimSpace(event.Metadata["file"]),
+	}
+}
+
+// dominantFocus picks the most frequent app, url, and file independently,
+// breaking ties by the lexically smallest value.
+func dominantFocus(records []Record) focus {
+	apps, urls, files := map[string]int{}, map[string]int{}, map[string]int{}
+	for _, record := range records {
+		f := eventFocus(record.Event)
+		if f.App != "" {
+			apps[f.App]++
+		}
+		if f.URL != "" {
+			urls[f.URL]++
+		}
+		if f.File != "" {
+			files[f.File]++
+		}
+	}
+	return focus{App: mostFrequent(apps), URL: mostFrequent(urls), File: mostFrequent(files)}
+}
+
+func mostFrequent(counts map[string]int) string {
+	best, bestCount := "", 0
+	for value, count := range counts {
+		if count > bestCount || (count == bestCount && value < best) {
+			best, bestCount = value, count
+		}
+	}
+	return best
+}
+
+// reasons lists the sorted promotion reasons found in records.
+func reasons(records []Record) []string {
+	seen := make(map[string]struct{})
+	for _, record := range records {
+		if reason := promotion(record.Event); reason != "" {
+			seen[reason] = struct{}{}
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(seen))
+	for reason := range seen {
+		out = append(out, reason)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func promotion(event events.Event) string {
+	category := strings.ToLower(event.Category)
+	action := strings.ToLower(event.Action)
+	target := strings.ToLower(event.Target)
+	switch {
+	case category == "build" || strings.HasPrefix(action, "build"):
+		return ReasonBuild
+	case category == "modal" || strings.Contains(action, "modal"):
+		return ReasonModal
+	case strings.Contains(action, "submit") || (action == "click" && strings.Contains(target, "submit")):
+		return ReasonFormSubmit
+	}
+	return ""
+}
+
+// nearest returns the cluster covering ts, or the closest one when ts falls
+// in a gap. Screenshots without a timestamp go to the first cluster.
+func nearest(clusters []TaskCluster, ts time.Time) *TaskCluster {
+	if ts.IsZero() {
+		return &clusters[0]
+	}
+	best := 0
+	bestDistance := time.Duration(-1)
+	for i := range clusters {
+		var distance time.Duration
+		switch {
+		case ts.Before(clusters[i].Start):
+			distance = clusters[i].Start.Sub(ts)
+		case ts.After(clusters[i].End):
+			distance = ts.Sub(clusters[i].End)
+		default:
+			return &clusters[i]
+		}
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	return &clusters[best]
+}
+
+// Write persists clusters as indented JSON.
+func Write(path string, doc File) error {
+	if doc.Clusters == nil {
+		doc.Clusters = []TaskCluster{}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("ensure clusters directory: %w", err)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal clusters: %w", err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write clusters: %w", err)
+	}
+	return nil
+}
+
+// Load reads a clusters.json document.
+func Load(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, fmt.Errorf("read clusters: %w", err)
+	}
+	var doc File
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return File{}, fmt.Errorf("decode clusters: %w", err)
+	}
+	return doc, nil
+}
+
+func clusterID(n int) string {
+	return fmt.Sprintf("cluster_%03d", n)
+}
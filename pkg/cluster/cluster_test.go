@@ -0,0 +1,114 @@
+package cluster
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/events"
+	"github.com/offlinefirst/limitless-context/pkg/screenshots"
+	"github.com/offlinefirst/limitless-context/pkg/sessionize"
+)
+
+var base = time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+
+func fixture() ([]sessionize.Session, []Record) {
+	at := func(d time.Duration) time.Time { return base.Add(d) }
+	records := []Record{
+		{ID: "evt_0001", Event: events.Event{Timestamp: at(0), Category: "window", Action: "focus", Metadata: map[string]string{"app": "code", "file": "main.go"}}},
+		{ID: "evt_0002", Event: events.Event{Timestamp: at(20 * time.Second), Category: "keyboard", Action: "type"}},
+		{ID: "evt_0003", Event: events.Event{Timestamp: at(60 * time.Second), Category: "keyboard", Action: "type", Metadata: map[string]string{"app": "code", "file": "main.go"}}},
+		// A 10s detour to chat is folded back into the editor cluster.
+		{ID: "evt_0004", Event: events.Event{Timestamp: at(70 * time.Second), Category: "window", Action: "focus", Metadata: map[string]string{"app": "chat"}}},
+		{ID: "evt_0005", Event: events.Event{Timestamp: at(80 * time.Second), Category: "keyboard", Action: "type", Metadata: map[string]string{"app": "code", "file": "main.go"}}},
+		// A short build run is promoted and kept separate.
+		{ID: "evt_0006", Event: events.Event{Timestamp: at(90 * time.Second), Category: "build", Action: "build_start", Metadata: map[string]string{"app": "terminal"}}},
+		{ID: "evt_0007", Event: events.Event{Timestamp: at(100 * time.Second), Category: "build", Action: "build_end", Metadata: map[string]string{"app": "terminal"}}},
+		{ID: "evt_0008", Event: events.Event{Timestamp: at(10 * time.Minute), Category: "window", Action: "focus", Metadata: map[string]string{"app": "docs", "url": "https://docs.example.com/roadmap"}}},
+		{ID: "evt_0009", Event: events.Event{Timestamp: at(10*time.Minute + 5*time.Second), Category: "mouse", Action: "click", Target: "submit-button", Metadata: map[string]string{"app": "docs", "url": "https://docs.example.com/roadmap"}}},
+	}
+	sessions := []sessionize.Session{
+		{ID: "session_001", Start: at(0), End: at(100 * time.Second), EventIDs: []string{"evt_0001", "evt_0002", "evt_0003", "evt_0004", "evt_0005", "evt_0006", "evt_0007"}},
+		{ID: "session_002", Start: at(10 * time.Minute), End: at(10*time.Minute + 5*time.Second), EventIDs: []string{"evt_0008", "evt_0009"}},
+	}
+	return sessions, records
+}
+
+func TestClusterGroupsPromotesAndMerges(t *testing.T) {
+	sessions, records := fixture()
+	shots := []Screenshot{
+		{Name: "screenshot_001.png", Metadata: screenshots.Metadata{CapturedAt: base.Add(95 * time.Second)}},
+		{Name: "screenshot_002.png", Metadata: screenshots.Metadata{CapturedAt: base.Add(9 * time.Minute)}},
+	}
+
+	doc := Cluster(sessions, records, shots, Options{})
+	if doc.MinDurationSeconds != 45 {
+		t.Fatalf("unexpected min duration %v", doc.MinDurationSeconds)
+	}
+	want := []TaskCluster{
+		{
+			ID: "cluster_001", SessionID: "session_001", Start: base, End: base.Add(80 * time.Second),
+			DominantApp: "code", DominantFile: "main.go",
+			EventIDs: []string{"evt_0001", "evt_0002", "evt_0003", "evt_0004", "evt_0005"},
+		},
+		{
+			ID: "cluster_002", SessionID: "session_001", Start: base.Add(90 * time.Second), End: base.Add(100 * time.Second),
+			DominantApp: "terminal", Promoted: true, Reasons: []string{ReasonBuild},
+			EventIDs: []string{"evt_0006", "evt_0007"}, Screenshots: []string{"screenshot_001.png"},
+		},
+		{
+			ID: "cluster_003", SessionID: "session_002", Start: base.Add(10 * time.Minute), End: base.Add(10*time.Minute + 5*time.Second),
+			DominantApp: "docs", DominantURL: "https://docs.example.com/roadmap", Promoted: true, Reasons: []string{ReasonFormSubmit},
+			EventIDs: []string{"evt_0008", "evt_0009"}, Screenshots: []string{"screenshot_002.png"},
+		},
+	}
+	if !reflect.DeepEqual(doc.Clusters, want) {
+		t.Fatalf("clusters mismatch:\n got %+v\nwant %+v", doc.Clusters, want)
+	}
+
+	if again := Cluster(sessions, records, shots, Options{}); !reflect.DeepEqual(doc, again) {
+		t.Fatalf("clustering is not deterministic")
+	}
+}
+
+func TestClusterMergesLeadingFragmentForward(t *testing.T) {
+	records := []Record{
+		{ID: "evt_0001", Event: events.Event{Timestamp: base, Category: "window", Action: "focus", Metadata: map[string]string{"app": "chat"}}},
+		{ID: "evt_0002", Event: events.Event{Timestamp: base.Add(10 * time.Second), Category: "window", Action: "focus", Metadata: map[string]string{"app": "code"}}},
+		{ID: "evt_0003", Event: events.Event{Timestamp: base.Add(100 * time.Second), Category: "keyboard", Action: "type", Metadata: map[string]string{"app": "code"}}},
+	}
+	sessions := []sessionize.Session{{ID: "session_001", Start: base, End: base.Add(100 * time.Second), EventIDs: []string{"evt_0001", "evt_0002", "evt_0003"}}}
+
+	doc := Cluster(sessions, records, nil, Options{})
+	if len(doc.Clusters) != 1 {
+		t.Fatalf("expected leading fragment to merge forward, got %+v", doc.Clusters)
+	}
+	if got := doc.Clusters[0]; got.DominantApp != "code" || !got.Start.Equal(base) || len(got.EventIDs) != 3 {
+		t.Fatalf("unexpected merged cluster: %+v", got)
+	}
+}
+
+func TestWriteLoadRoundTrip(t *testing.T) {
+	sessions, records := fixture()
+	doc := Cluster(sessions, records, nil, Options{})
+	path := filepath.Join(t.TempDir(), "bundles", FileName)
+	if err := Write(path, doc); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !reflect.DeepEqual(doc, loaded) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", loaded, doc)
+	}
+
+	if err := Write(path, File{}); err != nil {
+		t.Fatalf("write empty: %v", err)
+	}
+	empty, err := Load(path)
+	if err != nil || empty.Clusters == nil || len(empty.Clusters) != 0 {
+		t.Fatalf("expected empty cluster list, got %+v (%v)", empty, err)
+	}
+}
@@ -7,16 +7,21 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"github.com/offlinefirst/limitless-context/pkg/tokenizer"
 )
 
 const DefaultFileName = "config.yaml"
 
 // Config captures the user-adjustable knobs for the capture workflows.
 type Config struct {
-	Paths   PathsConfig
-	Capture CaptureConfig
-	Logging LoggingConfig
+	Paths      PathsConfig
+	Capture    CaptureConfig
+	Summarizer SummarizerConfig
+	Report     ReportConfig
+	Logging    LoggingConfig
 
 	// Source indicates where the configuration originated (defaults or a file path).
 	Source string
@@ -49,6 +54,43 @@ type CaptureConfig struct {
 type VideoConfig struct {
 	ChunkSeconds int
 	Format       string
+	// FPS and Resolution request a frame rate and size from backends that
+	// support them. Zero and empty keep the backend's own defaults.
+	FPS int
+	// Resolution is WIDTHxHEIGHT, for example 1920x1080.
+	Resolution string
+	// KeyframeIntervalSeconds is the longest gap between frames extracted
+	// from recorded segments for OCR.
+	KeyframeIntervalSeconds int
+	// SceneChangePercent also extracts a frame whose brightness differs from
+	// the previous one by at least this percentage. Zero disables it.
+	SceneChangePercent int
+}
+
+// MaxVideoFPS bounds capture.video.fps.
+const MaxVideoFPS = 60
+
+// Dimensions parses Resolution into a width and height. An empty Resolution
+// yields zeros.
+func (v VideoConfig) Dimensions() (int, int, error) {
+	if strings.TrimSpace(v.Resolution) == "" {
+		return 0, 0, nil
+	}
+	return ParseResolution(v.Resolution)
+}
+
+// ParseResolution parses a WIDTHxHEIGHT string such as "1920x1080".
+func ParseResolution(value string) (int, int, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(value)), "x")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid resolution %q (want WIDTHxHEIGHT)", value)
+	}
+	width, errW := strconv.Atoi(strings.TrimSpace(parts[0]))
+	height, errH := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if errW != nil || errH != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid resolution %q (want WIDTHxHEIGHT)", value)
+	}
+	return width, height, nil
 }
 
 // ScreenshotConfig controls screenshot cadence and throttling.
@@ -86,6 +128,24 @@ type PrivacyConfig struct {
 	DropUnknown bool
 }
 
+// SummarizerConfig controls how capture runs are turned into LLM bundles.
+type SummarizerConfig struct {
+	Mode               string
+	Tokenizer          string
+	TemplatesDir       string
+	IdleGapSeconds     int
+	PerTaskTokenBudget int
+	MaxContextTokens   int
+}
+
+// ReportConfig tunes the report's scorecard.
+type ReportConfig struct {
+	// Weights override the default metric weights, keyed by metric
+	// (fidelity, traceability, token_cost, ...). Metric names are checked by
+	// the scoring package when the report is generated.
+	Weights map[string]int
+}
+
 // LoggingConfig defines log verbosity and formatting.
 type LoggingConfig struct {
 	Level  string
@@ -106,10 +166,12 @@ func Default() Config {
 			EventsEnabled:      true,
 			ASREnabled:         true,
 			OCREnabled:         true,
-                        Video: VideoConfig{
-                                ChunkSeconds: 300,
-                                Format:       "mp4",
-                        },
+			Video: VideoConfig{
+				ChunkSeconds:            300,
+				Format:                  "mp4",
+				KeyframeIntervalSeconds: 60,
+				SceneChangePercent:      12,
+			},
 			Screenshots: ScreenshotConfig{
 				IntervalSeconds: 60,
 				MaxPerMinute:    3,
@@ -132,6 +194,13 @@ func Default() Config {
 			},
 			Privacy: PrivacyConfig{},
 		},
+		Summarizer: SummarizerConfig{
+			Mode:               "manual",
+			Tokenizer:          tokenizer.DefaultName,
+			IdleGapSeconds:     120,
+			PerTaskTokenBudget: 5000,
+			MaxContextTokens:   8192,
+		},
 		Logging: LoggingConfig{
 			Level:  "info",
 			Format: "json",
@@ -202,6 +271,18 @@ func (c Config) Validate() error {
 	if strings.TrimSpace(c.Capture.Video.Format) == "" {
 		return errors.New("capture.video.format must not be empty")
 	}
+	if c.Capture.Video.FPS < 0 || c.Capture.Video.FPS > MaxVideoFPS {
+		return fmt.Errorf("capture.video.fps must be between 0 and %d", MaxVideoFPS)
+	}
+	if _, _, err := c.Capture.Video.Dimensions(); err != nil {
+		return fmt.Errorf("capture.video.resolution: %w", err)
+	}
+	if c.Capture.Video.KeyframeIntervalSeconds <= 0 {
+		return errors.New("capture.video.keyframe_interval_seconds must be positive")
+	}
+	if c.Capture.Video.SceneChangePercent < 0 || c.Capture.Video.SceneChangePercent > 100 {
+		return errors.New("capture.video.scene_change_percent must be between 0 and 100")
+	}
 	if c.Capture.Screenshots.IntervalSeconds <= 0 {
 		return errors.New("capture.screenshots.interval_seconds must be positive")
 	}
@@ -215,6 +296,31 @@ func (c Config) Validate() error {
 		return errors.New("capture.events.coarse_interval_seconds must be positive")
 	}
 
+	if c.Summarizer.Mode != "manual" {
+		return fmt.Errorf("summarizer.mode %q is unsupported (only \"manual\")", c.Summarizer.Mode)
+	}
+	if !tokenizer.DefaultRegistry().Has(c.Summarizer.Tokenizer) {
+		return fmt.Errorf("summarizer.tokenizer %q is unsupported (known: %s)", c.Summarizer.Tokenizer, strings.Join(tokenizer.DefaultRegistry().Names(), ", "))
+	}
+	if c.Summarizer.IdleGapSeconds <= 0 {
+		return errors.New("summarizer.idle_gap_seconds must be positive")
+	}
+	if c.Summarizer.PerTaskTokenBudget <= 0 {
+		return errors.New("summarizer.per_task_token_budget must be positive")
+	}
+	if c.Summarizer.MaxContextTokens <= 0 {
+		return errors.New("summarizer.max_context_tokens must be positive")
+	}
+	if c.Summarizer.PerTaskTokenBudget > c.Summarizer.MaxContextTokens {
+		return errors.New("summarizer.per_task_token_budget must not exceed summarizer.max_context_tokens")
+	}
+
+	for metric, weight := range c.Report.Weights {
+		if weight < 0 {
+			return fmt.Errorf("report.weights.%s must not be negative", metric)
+		}
+	}
+
 	if c.Capture.ASREnabled {
 		if strings.TrimSpace(c.Capture.ASR.WhisperBinary) == "" {
 			return errors.New("capture.asr.whisper_binary must not be empty")
@@ -317,7 +423,8 @@ func applyValue(cfg *Config, stack []yamlFrame, key, rawValue string) error {
 	}
 	path = append(path, key)
 
-	switch strings.Join(path, ".") {
+	joined := strings.Join(path, ".")
+	switch joined {
 	case "paths.runs_dir":
 		cfg.Paths.RunsDir = value
 	case "paths.cache_dir":
@@ -370,6 +477,26 @@ func applyValue(cfg *Config, stack []yamlFrame, key, rawValue string) error {
 		cfg.Capture.Video.ChunkSeconds = seconds
 	case "capture.video.format":
 		cfg.Capture.Video.Format = strings.ToLower(value)
+	case "capture.video.fps":
+		fps, err := parseInt(value)
+		if err != nil {
+			return fmt.Errorf("capture.video.fps: %w", err)
+		}
+		cfg.Capture.Video.FPS = fps
+	case "capture.video.resolution":
+		cfg.Capture.Video.Resolution = strings.ToLower(value)
+	case "capture.video.keyframe_interval_seconds":
+		seconds, err := parseInt(value)
+		if err != nil {
+			return fmt.Errorf("capture.video.keyframe_interval_seconds: %w", err)
+		}
+		cfg.Capture.Video.KeyframeIntervalSeconds = seconds
+	case "capture.video.scene_change_percent":
+		percent, err := parseInt(value)
+		if err != nil {
+			return fmt.Errorf("capture.video.scene_change_percent: %w", err)
+		}
+		cfg.Capture.Video.SceneChangePercent = percent
 	case "capture.screenshots.interval_seconds":
 		seconds, err := parseInt(value)
 		if err != nil {
@@ -424,8 +551,43 @@ func applyValue(cfg *Config, stack []yamlFrame, key, rawValue string) error {
 			return fmt.Errorf("capture.privacy.drop_unknown: %w", err)
 		}
 		cfg.Capture.Privacy.DropUnknown = b
+	case "summarizer.mode":
+		cfg.Summarizer.Mode = strings.ToLower(value)
+	case "summarizer.tokenizer":
+		cfg.Summarizer.Tokenizer = strings.ToLower(value)
+	case "summarizer.templates_dir":
+		cfg.Summarizer.TemplatesDir = value
+	case "summarizer.idle_gap_seconds":
+		seconds, err := parseInt(value)
+		if err != nil {
+			return fmt.Errorf("summarizer.idle_gap_seconds: %w", err)
+		}
+		cfg.Summarizer.IdleGapSeconds = seconds
+	case "summarizer.per_task_token_budget":
+		tokens, err := parseInt(value)
+		if err != nil {
+			return fmt.Errorf("summarizer.per_task_token_budget: %w", err)
+		}
+		cfg.Summarizer.PerTaskTokenBudget = tokens
+	case "summarizer.max_context_tokens":
+		tokens, err := parseInt(value)
+		if err != nil {
+			return fmt.Errorf("summarizer.max_context_tokens: %w", err)
+		}
+		cfg.Summarizer.MaxContextTokens = tokens
 	default:
-		return fmt.Errorf("unknown key %q", strings.Join(path, "."))
+		metric, ok := strings.CutPrefix(joined, "report.weights.")
+		if !ok || metric == "" || strings.Contains(metric, ".") {
+			return fmt.Errorf("unknown key %q", joined)
+		}
+		weight, err := parseInt(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", joined, err)
+		}
+		if cfg.Report.Weights == nil {
+			cfg.Report.Weights = make(map[string]int)
+		}
+		cfg.Report.Weights[metric] = weight
 	}
 
 	return nil
@@ -511,6 +673,9 @@ func (c *Config) normalize() {
 	if strings.TrimSpace(c.Capture.Video.Format) == "" {
 		c.Capture.Video.Format = defaults.Capture.Video.Format
 	}
+	if c.Capture.Video.KeyframeIntervalSeconds <= 0 {
+		c.Capture.Video.KeyframeIntervalSeconds = defaults.Capture.Video.KeyframeIntervalSeconds
+	}
 	if c.Capture.Screenshots.IntervalSeconds <= 0 {
 		c.Capture.Screenshots.IntervalSeconds = defaults.Capture.Screenshots.IntervalSeconds
 	}
@@ -541,6 +706,21 @@ func (c *Config) normalize() {
 	if strings.TrimSpace(c.Capture.OCR.TesseractBinary) == "" {
 		c.Capture.OCR.TesseractBinary = defaults.Capture.OCR.TesseractBinary
 	}
+	if strings.TrimSpace(c.Summarizer.Mode) == "" {
+		c.Summarizer.Mode = defaults.Summarizer.Mode
+	}
+	if strings.TrimSpace(c.Summarizer.Tokenizer) == "" {
+		c.Summarizer.Tokenizer = defaults.Summarizer.Tokenizer
+	}
+	if c.Summarizer.IdleGapSeconds <= 0 {
+		c.Summarizer.IdleGapSeconds = defaults.Summarizer.IdleGapSeconds
+	}
+	if c.Summarizer.PerTaskTokenBudget <= 0 {
+		c.Summarizer.PerTaskTokenBudget = defaults.Summarizer.PerTaskTokenBudget
+	}
+	if c.Summarizer.MaxContextTokens <= 0 {
+		c.Summarizer.MaxContextTokens = defaults.Summarizer.MaxContextTokens
+	}
 }
 
 // NormalizeLogLevel validates and lowercases known logging levels.
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -116,3 +117,141 @@ func TestUnknownKeyReturnsError(t *testing.T) {
 		t.Fatalf("expected error for unsupported key")
 	}
 }
+
+func TestLoadVideoFPSAndResolution(t *testing.T) {
+	cfg := Default()
+	// Unset fps and resolution leave backends at their own defaults.
+	if width, height, err := cfg.Capture.Video.Dimensions(); cfg.Capture.Video.FPS != 0 || width != 0 || height != 0 || err != nil {
+		t.Fatalf("unexpected video defaults: %+v", cfg.Capture.Video)
+	}
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	content := "capture:\n  video:\n    fps: 8\n    resolution: \"1280X720\"\n"
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	width, height, err := cfg.Capture.Video.Dimensions()
+	if cfg.Capture.Video.FPS != 8 || err != nil || width != 1280 || height != 720 {
+		t.Fatalf("unexpected video settings: %+v (%dx%d, %v)", cfg.Capture.Video, width, height, err)
+	}
+
+	for _, bad := range []string{"capture:\n  video:\n    fps: 120\n", "capture:\n  video:\n    resolution: 1080p\n"} {
+		if err := os.WriteFile(cfgPath, []byte(bad), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if _, err := Load(cfgPath); err == nil {
+			t.Fatalf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestLoadKeyframeSettings(t *testing.T) {
+	cfg := Default()
+	if cfg.Capture.Video.KeyframeIntervalSeconds != 60 || cfg.Capture.Video.SceneChangePercent != 12 {
+		t.Fatalf("unexpected keyframe defaults: %+v", cfg.Capture.Video)
+	}
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	content := "capture:\n  video:\n    keyframe_interval_seconds: 20\n    scene_change_percent: 0\n"
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Capture.Video.KeyframeIntervalSeconds != 20 || cfg.Capture.Video.SceneChangePercent != 0 {
+		t.Fatalf("unexpected keyframe settings: %+v", cfg.Capture.Video)
+	}
+
+	if err := os.WriteFile(cfgPath, []byte("capture:\n  video:\n    scene_change_percent: 150\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(cfgPath); err == nil {
+		t.Fatalf("expected an error for an out-of-range scene change percent")
+	}
+}
+
+func TestLoadSummarizerSettings(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	content := "summarizer:\n  mode: manual\n  tokenizer: llama\n  templates_dir: prompts\n  idle_gap_seconds: 90\n  per_task_token_budget: 3000\n  max_context_tokens: 6000\n"
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Summarizer.PerTaskTokenBudget != 3000 {
+		t.Fatalf("unexpected per task budget: %d", cfg.Summarizer.PerTaskTokenBudget)
+	}
+	if cfg.Summarizer.MaxContextTokens != 6000 {
+		t.Fatalf("unexpected max context tokens: %d", cfg.Summarizer.MaxContextTokens)
+	}
+	if cfg.Summarizer.TemplatesDir != "prompts" {
+		t.Fatalf("unexpected templates dir: %q", cfg.Summarizer.TemplatesDir)
+	}
+	if cfg.Summarizer.IdleGapSeconds != 90 {
+		t.Fatalf("unexpected idle gap: %d", cfg.Summarizer.IdleGapSeconds)
+	}
+	if cfg.Summarizer.Tokenizer != "llama" {
+		t.Fatalf("unexpected tokenizer: %q", cfg.Summarizer.Tokenizer)
+	}
+
+	content = "summarizer:\n  tokenizer: gpt2\n"
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(cfgPath); err == nil {
+		t.Fatalf("expected error for unknown tokenizer")
+	}
+
+	content = "summarizer:\n  per_task_token_budget: 9000\n  max_context_tokens: 8192\n"
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(cfgPath); err == nil {
+		t.Fatalf("expected error when per task budget exceeds max context tokens")
+	}
+}
+
+func TestLoadReportWeights(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	content := "report:\n  weights:\n    fidelity: 40\n    robustness: 5\n"
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(cfg.Report.Weights) != 2 || cfg.Report.Weights["fidelity"] != 40 || cfg.Report.Weights["robustness"] != 5 {
+		t.Fatalf("unexpected weights: %v", cfg.Report.Weights)
+	}
+
+	content = "report:\n  weights:\n    fidelity: -1\n"
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(cfgPath); err == nil || !strings.Contains(err.Error(), "report.weights.fidelity") {
+		t.Fatalf("expected negative weight error, got %v", err)
+	}
+
+	content = "report:\n  colour: blue\n"
+	if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(cfgPath); err == nil {
+		t.Fatalf("expected error for unknown report key")
+	}
+}
@@ -0,0 +1,177 @@
+// Package control exposes a running capture's controller on a Unix domain
+// socket in the run directory, so other tester processes can pause, resume,
+// or stop it. Each connection carries one JSON request and one JSON response.
+package control
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+// SocketName is the control socket's file name in the run directory.
+const SocketName = "control.sock"
+
+// Actions accepted by the server.
+const (
+	ActionPause  = "pause"
+	ActionResume = "resume"
+	ActionStop   = "stop"
+	ActionState  = "state"
+)
+
+// requestTimeout bounds a client round trip and the server's handling of a
+// single connection.
+const requestTimeout = 5 * time.Second
+
+// ErrNotRunning is returned by Send when no capture is listening on the run.
+var ErrNotRunning = errors.New("no capture is running for this run")
+
+// Controller is the part of capture.Controller the server drives.
+type Controller interface {
+	PauseWithReason(reason string) bool
+	ResumeWithReason(reason string) bool
+	Stop(reason string) bool
+	State() string
+}
+
+// Request asks the server to perform an action.
+type Request struct {
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Response reports the controller state after a request. Changed is false
+// when the action was a no-op, such as pausing a paused capture.
+type Response struct {
+	State   string `json:"state"`
+	Changed bool   `json:"changed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// SocketPath returns the control socket location for a run.
+func SocketPath(layout runmanifest.Layout) string {
+	return filepath.Join(layout.Root, SocketName)
+}
+
+// Server accepts control requests until Close is called.
+type Server struct {
+	listener net.Listener
+	path     string
+	ctrl     Controller
+	wg       sync.WaitGroup
+}
+
+// Listen binds the control socket at path. A socket left behind by a capture
+// that exited without cleaning up is replaced; a live one is an error.
+func Listen(path string, ctrl Controller) (*Server, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("control socket %s is already in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale control socket: %w", err)
+		}
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listen on control socket: %w", err)
+	}
+	s := &Server{listener: listener, path: path, ctrl: ctrl}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Path returns the socket path.
+func (s *Server) Path() string {
+	return s.path
+}
+
+// Close stops accepting requests, waits for in-flight ones, and removes the
+// socket file.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	if rmErr := os.Remove(s.path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) && err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(requestTimeout))
+	var req Request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		_ = json.NewEncoder(conn).Encode(Response{State: s.ctrl.State(), Error: fmt.Sprintf("decode request: %v", err)})
+		return
+	}
+	_ = json.NewEncoder(conn).Encode(s.apply(req))
+}
+
+func (s *Server) apply(req Request) Response {
+	var changed bool
+	switch req.Action {
+	case ActionPause:
+		changed = s.ctrl.PauseWithReason(req.Reason)
+	case ActionResume:
+		changed = s.ctrl.ResumeWithReason(req.Reason)
+	case ActionStop:
+		changed = s.ctrl.Stop(req.Reason)
+	case ActionState:
+	default:
+		return Response{State: s.ctrl.State(), Error: fmt.Sprintf("unknown action %q", req.Action)}
+	}
+	return Response{State: s.ctrl.State(), Changed: changed}
+}
+
+// Send delivers one request to the socket at path. It returns ErrNotRunning
+// when nothing is listening, and the server's error when it rejects the
+// request.
+func Send(path string, req Request) (Response, error) {
+	conn, err := net.DialTimeout("unix", path, requestTimeout)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+			return Response{}, ErrNotRunning
+		}
+		return Response{}, fmt.Errorf("connect to control socket: %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(requestTimeout))
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, fmt.Errorf("send control request: %w", err)
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("read control response: %w", err)
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
@@ -0,0 +1,94 @@
+package control
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/offlinefirst/limitless-context/pkg/capture"
+)
+
+// socketDir keeps socket paths short; Unix socket paths are limited to about
+// 100 bytes and test temp directories can be long.
+func socketDir(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "ctl")
+	if err != nil {
+		t.Fatalf("mkdir temp: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestServerDrivesController(t *testing.T) {
+	path := filepath.Join(socketDir(t), SocketName)
+	ctrl := capture.NewController()
+	changes, cancel := ctrl.Subscribe()
+	defer cancel()
+	<-changes
+
+	server, err := Listen(path, ctrl)
+	if err != nil {
+		t.Fatalf("Listen returned error: %v", err)
+	}
+
+	resp, err := Send(path, Request{Action: ActionPause, Reason: "operator break"})
+	if err != nil || resp.State != "paused" || !resp.Changed {
+		t.Fatalf("unexpected pause response %+v, %v", resp, err)
+	}
+	if change := <-changes; change.State != "paused" || change.Reason != "operator break" {
+		t.Fatalf("unexpected transition: %+v", change)
+	}
+	if resp, err := Send(path, Request{Action: ActionPause}); err != nil || resp.Changed {
+		t.Fatalf("second pause should be a no-op: %+v, %v", resp, err)
+	}
+	if resp, err := Send(path, Request{Action: ActionState}); err != nil || resp.State != "paused" {
+		t.Fatalf("unexpected state response %+v, %v", resp, err)
+	}
+	if _, err := Send(path, Request{Action: "rewind"}); err == nil {
+		t.Fatalf("expected unknown action error")
+	}
+	if resp, err := Send(path, Request{Action: ActionStop, Reason: "done"}); err != nil || resp.State != "stopping" {
+		t.Fatalf("unexpected stop response %+v, %v", resp, err)
+	}
+	if !errors.Is(ctrl.Err(), capture.ErrStopRequested) {
+		t.Fatalf("expected stop to record ErrStopRequested, got %v", ctrl.Err())
+	}
+
+	if err := server.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected socket to be removed, got %v", err)
+	}
+	if _, err := Send(path, Request{Action: ActionState}); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning after close, got %v", err)
+	}
+}
+
+func TestListenReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(socketDir(t), SocketName)
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	if _, err := Listen(path, capture.NewController()); err == nil {
+		t.Fatalf("expected an error while another listener is live")
+	}
+	// Closing a Unix listener removes its file, so recreate the leftover.
+	stale.Close()
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("write stale socket: %v", err)
+	}
+
+	server, err := Listen(path, capture.NewController())
+	if err != nil {
+		t.Fatalf("Listen should replace a stale socket: %v", err)
+	}
+	defer server.Close()
+	if resp, err := Send(path, Request{Action: ActionState}); err != nil || resp.State != "running" {
+		t.Fatalf("unexpected state response %+v, %v", resp, err)
+	}
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// SequenceID returns the identifier cited for the nth (1-based) event of an
+// events_fine.jsonl stream.
+func SequenceID(n int) string {
+	return fmt.Sprintf("evt_%04d", n)
+}
+
+// Scan decodes a JSONL event stream, invoking fn for each event in file order.
+// Events written before IDs were recorded receive SequenceID of their line.
+func Scan(r io.Reader, fn func(Event) error) error {
+	if r == nil {
+		return errors.New("reader must not be nil")
+	}
+	decoder := json.NewDecoder(r)
+	line := 0
+	for {
+		var event Event
+		if err := decoder.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("decode event %d: %w", line+1, err)
+		}
+		line++
+		if event.ID == "" {
+			event.ID = SequenceID(line)
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadFine loads every event from an events_fine.jsonl file.
+func ReadFine(path string) ([]Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open fine events: %w", err)
+	}
+	defer file.Close()
+
+	var out []Event
+	if err := Scan(file, func(event Event) error {
+		out = append(out, event)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,82 @@
+package events
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadFineRoundTripsTapOutput(t *testing.T) {
+	base := time.Date(2024, 3, 14, 9, 26, 0, 0, time.UTC)
+	tap, err := NewTap(Options{
+		FineInterval:   10 * time.Second,
+		CoarseInterval: time.Minute,
+		Clock:          func() time.Time { return base },
+		Source:         stubSource{events: fixtureTimeline(base, 10*time.Second)},
+	})
+	if err != nil {
+		t.Fatalf("new tap: %v", err)
+	}
+	res, err := tap.Capture(nil, t.TempDir())
+	if err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+
+	loaded, err := ReadFine(res.FinePath)
+	if err != nil {
+		t.Fatalf("read fine: %v", err)
+	}
+	if len(loaded) != res.EventCount {
+		t.Fatalf("expected %d events, got %d", res.EventCount, len(loaded))
+	}
+	if !loaded[1].Timestamp.Equal(base.Add(10*time.Second)) || loaded[1].Target != "submit-button" {
+		t.Fatalf("unexpected second event: %+v", loaded[1])
+	}
+	for i, event := range loaded {
+		if event.ID != SequenceID(i+1) {
+			t.Fatalf("event %d has id %q, want %q", i, event.ID, SequenceID(i+1))
+		}
+	}
+}
+
+func TestScanKeepsRecordedIDsAndFillsMissingOnes(t *testing.T) {
+	input := "{\"id\":\"evt_0007\",\"timestamp\":\"2024-03-14T09:26:00Z\",\"category\":\"mouse\",\"action\":\"click\",\"target\":\"a\"}\n" +
+		"{\"timestamp\":\"2024-03-14T09:26:10Z\",\"category\":\"mouse\",\"action\":\"click\",\"target\":\"b\"}\n"
+	var ids []string
+	if err := Scan(strings.NewReader(input), func(event Event) error {
+		ids = append(ids, event.ID)
+		return nil
+	}); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if strings.Join(ids, ",") != "evt_0007,evt_0002" {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+}
+
+func TestScanStopsOnCallbackError(t *testing.T) {
+	input := "{\"timestamp\":\"2024-03-14T09:26:00Z\",\"category\":\"mouse\",\"action\":\"click\",\"target\":\"a\"}\n" +
+		"{\"timestamp\":\"2024-03-14T09:26:10Z\",\"category\":\"mouse\",\"action\":\"click\",\"target\":\"b\"}\n"
+	stop := errors.New("stop")
+	seen := 0
+	err := Scan(strings.NewReader(input), func(Event) error {
+		seen++
+		return stop
+	})
+	if !errors.Is(err, stop) || seen != 1 {
+		t.Fatalf("expected scan to stop after first event, seen=%d err=%v", seen, err)
+	}
+}
+
+func TestReadFineReportsMalformedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events_fine.jsonl")
+	if err := os.WriteFile(path, []byte("{\"category\":\"mouse\"}\nnot-json\n"), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if _, err := ReadFine(path); err == nil || !strings.Contains(err.Error(), "event 2") {
+		t.Fatalf("expected decode error naming event 2, got %v", err)
+	}
+}
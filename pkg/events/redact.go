@@ -5,6 +5,17 @@ import (
 	"strings"
 )
 
+// RedactionMask replaces every redacted match.
+const RedactionMask = "[REDACTED]"
+
+// NamedPatterns are the expressions that redact_patterns entries may refer to
+// by name instead of spelling out a regular expression.
+var NamedPatterns = map[string]string{
+	"email": `(?i)[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`,
+	"cc16":  `\b(?:\d[ -]?){16}\b`,
+	"jwt":   `eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9._-]+\.[A-Za-z0-9._-]+`,
+}
+
 // Redactor applies sensitive-data filters to event metadata.
 //
 // The zero value is a no-op redactor.
@@ -19,19 +30,13 @@ func NewRedactor(redactEmails bool, custom []string) (Redactor, error) {
 	patterns := make([]*regexp.Regexp, 0, len(custom)+1)
 
 	if redactEmails {
-		rx, err := regexp.Compile(`(?i)[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`)
+		rx, err := regexp.Compile(NamedPatterns["email"])
 		if err != nil {
 			return Redactor{}, err
 		}
 		patterns = append(patterns, rx)
 	}
 
-	named := map[string]string{
-		"email": `(?i)[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`,
-		"cc16":  `\b(?:\d[ -]?){16}\b`,
-		"jwt":   `eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9._-]+\.[A-Za-z0-9._-]+`,
-	}
-
 	for _, expr := range custom {
 		trimmed := strings.TrimSpace(expr)
 		if trimmed == "" {
@@ -39,7 +44,7 @@ func NewRedactor(redactEmails bool, custom []string) (Redactor, error) {
 		}
 
 		candidate := trimmed
-		if mapped, ok := named[strings.ToLower(trimmed)]; ok {
+		if mapped, ok := NamedPatterns[strings.ToLower(trimmed)]; ok {
 			candidate = mapped
 		}
 
@@ -61,7 +66,7 @@ func (r Redactor) ApplyString(input string) string {
 
 	redacted := input
 	for _, rx := range r.patterns {
-		redacted = rx.ReplaceAllString(redacted, "[REDACTED]")
+		redacted = rx.ReplaceAllString(redacted, RedactionMask)
 	}
 	return redacted
 }
@@ -57,6 +57,8 @@ type Result struct {
 
 // Event describes a single interaction sample.
 type Event struct {
+	// ID is assigned in write order (see SequenceID) when the event is persisted.
+	ID        string            `json:"id,omitempty"`
 	Timestamp time.Time         `json:"timestamp"`
 	Category  string            `json:"category"`
 	Action    string            `json:"action"`
@@ -149,6 +151,7 @@ func (t *Tap) Capture(ctx context.Context, destDir string) (Result, error) {
 
 		redacted := event
 		redacted.Metadata = t.redactor.ApplyMetadata(event.Metadata)
+		redacted.ID = SequenceID(allowed + 1)
 
 		if err := encoder.Encode(redacted); err != nil {
 			return fmt.Errorf("write fine event: %w", err)
@@ -177,10 +180,14 @@ func (t *Tap) Capture(ctx context.Context, destDir string) (Result, error) {
 	}
 
 	if streamErr != nil {
-		if errors.Is(streamErr, context.Canceled) || errors.Is(streamErr, context.DeadlineExceeded) {
+		if !errors.Is(streamErr, context.Canceled) && !errors.Is(streamErr, context.DeadlineExceeded) {
+			return Result{}, fmt.Errorf("stream events: %w", streamErr)
+		}
+		// A stop mid-stream still flushes the coarse summary for the events
+		// already written; only a capture that saw nothing reports the error.
+		if allowed+filtered == 0 {
 			return Result{}, streamErr
 		}
-		return Result{}, fmt.Errorf("stream events: %w", streamErr)
 	}
 
 	summary := make([]CoarseBucket, 0, len(buckets))
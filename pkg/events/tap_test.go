@@ -204,6 +204,10 @@ func TestTapPrivacyFiltersEvents(t *testing.T) {
 	if strings.Contains(string(data), "mail\n") {
 		t.Fatalf("expected events outside allow-list to be removed")
 	}
+	// IDs are dense over written events so filtered events leave no gaps.
+	if !strings.Contains(string(data), `"id":"evt_0001"`) || strings.Contains(string(data), SequenceID(result.EventCount+1)) {
+		t.Fatalf("expected ids evt_0001..evt_%04d:\n%s", result.EventCount, data)
+	}
 }
 
 func TestRedactorAppliesPatterns(t *testing.T) {
@@ -253,3 +257,41 @@ func TestTapRespectsCancellation(t *testing.T) {
 		t.Fatalf("expected capture to respect cancellation")
 	}
 }
+
+func TestTapFlushesCoarseSummaryWhenStopped(t *testing.T) {
+	redactor, err := NewRedactor(false, nil)
+	if err != nil {
+		t.Fatalf("new redactor: %v", err)
+	}
+
+	base := time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tap, err := NewTap(Options{
+		FineInterval:   time.Second,
+		CoarseInterval: time.Minute,
+		Redactor:       redactor,
+		Clock:          func() time.Time { return base },
+		Source: EventSourceFunc(func(ctx context.Context, emit func(Event) error) error {
+			if err := emit(Event{Timestamp: base, Category: "keyboard", Action: "type", Target: "editor"}); err != nil {
+				return err
+			}
+			cancel()
+			return blockingSource{}.Stream(ctx, emit)
+		}),
+	})
+	if err != nil {
+		t.Fatalf("new tap: %v", err)
+	}
+
+	res, err := tap.Capture(ctx, t.TempDir())
+	if err != nil {
+		t.Fatalf("expected a stopped capture to flush, got %v", err)
+	}
+	if res.EventCount != 1 || res.BucketCount != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if _, err := os.Stat(res.CoarsePath); err != nil {
+		t.Fatalf("expected coarse summary to be written: %v", err)
+	}
+}
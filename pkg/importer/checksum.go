@@ -0,0 +1,93 @@
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+// Integrity states recorded per task in report.json.
+const (
+	IntegrityVerified   = "verified"
+	IntegrityModified   = "modified"
+	IntegrityUnverified = "unverified"
+)
+
+// Integrity compares a task's prompt.txt, context.md, and schema.json with
+// the SHA-256 checksums recorded in its metrics.json at bundle time.
+type Integrity struct {
+	Status     string             `json:"status"`
+	Mismatches []ChecksumMismatch `json:"mismatches,omitempty"`
+	// Reason explains why the checksums could not be verified.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ChecksumMismatch names a bundle input edited after bundling. Actual is
+// empty when the file was deleted.
+type ChecksumMismatch struct {
+	File     string `json:"file"`
+	Recorded string `json:"recorded"`
+	Actual   string `json:"actual"`
+}
+
+// Modified reports whether any bundle input changed after bundling.
+func (i Integrity) Modified() bool {
+	return i.Status == IntegrityModified
+}
+
+// Edited reports whether file differs from its checksum.
+func (i Integrity) Edited(file string) bool {
+	for _, mismatch := range i.Mismatches {
+		if mismatch.File == file {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyChecksums recomputes the digests of dir/prompt.txt, dir/context.md,
+// and dir/schema.json.
+func verifyChecksums(dir string) Integrity {
+	metrics, err := runmanifest.LoadBundleMetrics(filepath.Join(dir, "metrics.json"))
+	if err != nil {
+		return Integrity{Status: IntegrityUnverified, Reason: err.Error()}
+	}
+	recorded := []struct {
+		file string
+		sum  string
+	}{
+		{"prompt.txt", metrics.Checksums.PromptSHA256},
+		{"context.md", metrics.Checksums.ContextSHA256},
+		{"schema.json", metrics.Checksums.SchemaSHA256},
+	}
+
+	out := Integrity{Status: IntegrityVerified}
+	for _, entry := range recorded {
+		if entry.sum == "" {
+			return Integrity{Status: IntegrityUnverified, Reason: fmt.Sprintf("metrics.json records no checksum for %s", entry.file)}
+		}
+		actual, err := fileSHA256(filepath.Join(dir, entry.file))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return Integrity{Status: IntegrityUnverified, Reason: err.Error()}
+		}
+		if actual != entry.sum {
+			out.Status = IntegrityModified
+			out.Mismatches = append(out.Mismatches, ChecksumMismatch{File: entry.file, Recorded: entry.sum, Actual: actual})
+		}
+	}
+	return out
+}
+
+func fileSHA256(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+func digest(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeTaskInputs writes prompt.txt, context.md, and a metrics.json recording
+// their checksums and that of the schema.json already in dir, if any.
+func writeTaskInputs(t *testing.T, dir string) {
+	t.Helper()
+	writeFile(t, filepath.Join(dir, "prompt.txt"), "Reply with JSON.\n")
+	writeFile(t, filepath.Join(dir, "context.md"), "# Context\n- event\n")
+	schema, err := os.ReadFile(filepath.Join(dir, "schema.json"))
+	if os.IsNotExist(err) {
+		writeFile(t, filepath.Join(dir, "schema.json"), "{}\n")
+		schema = []byte("{}\n")
+	} else if err != nil {
+		t.Fatalf("read schema: %v", err)
+	}
+	metrics := runmanifest.BundleMetrics{
+		TaskID:    filepath.Base(dir),
+		Checksums: runmanifest.BundleChecksums{PromptSHA256: digest("Reply with JSON.\n"), ContextSHA256: digest("# Context\n- event\n"), SchemaSHA256: digest(string(schema))},
+	}
+	if err := runmanifest.SaveBundleMetrics(metrics, filepath.Join(dir, "metrics.json")); err != nil {
+		t.Fatalf("save metrics: %v", err)
+	}
+}
+
+func TestVerifyChecksumsDetectsEdits(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "task_001")
+	writeTaskInputs(t, dir)
+	if got := verifyChecksums(dir); got.Status != IntegrityVerified || len(got.Mismatches) != 0 {
+		t.Fatalf("expected untouched bundle to verify: %+v", got)
+	}
+
+	writeFile(t, filepath.Join(dir, "context.md"), "# Context\n- event\n- added by hand\n")
+	got := verifyChecksums(dir)
+	if !got.Modified() || len(got.Mismatches) != 1 || got.Mismatches[0].File != "context.md" || got.Mismatches[0].Actual == "" {
+		t.Fatalf("expected context.md mismatch: %+v", got)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "prompt.txt")); err != nil {
+		t.Fatalf("remove prompt: %v", err)
+	}
+	got = verifyChecksums(dir)
+	if len(got.Mismatches) != 2 || got.Mismatches[0].File != "prompt.txt" || got.Mismatches[0].Actual != "" {
+		t.Fatalf("expected deleted prompt to be reported: %+v", got)
+	}
+}
+
+func TestRunSkipsValidationAgainstEditedSchema(t *testing.T) {
+	layout, man := writeBundles(t, "task_001")
+	dir := filepath.Join(layout.BundlesDir, "task_001")
+	writeTaskInputs(t, dir)
+	writeFile(t, filepath.Join(dir, "output.json"), `{"task_id": "task_001"}`)
+	// Loosening the contract after bundling must not let the output pass.
+	writeFile(t, filepath.Join(dir, "schema.json"), `{"type": "object"}`)
+
+	report, err := Run(Options{Layout: layout, Manifest: man})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	task := report.Tasks[0]
+	if task.Status != StatusInvalid || len(task.Errors) != 1 || !strings.Contains(task.Errors[0], "schema.json was edited after bundling") {
+		t.Fatalf("expected the edited schema to block validation: %+v", task)
+	}
+	if !task.Integrity.Modified() || !task.Integrity.Edited("schema.json") || report.Summary.Modified != 1 {
+		t.Fatalf("expected a schema.json mismatch: %+v %+v", task.Integrity, report.Summary)
+	}
+}
+
+func TestVerifyChecksumsWithoutMetrics(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "task_001")
+	writeFile(t, filepath.Join(dir, "context.md"), "# Context\n")
+	got := verifyChecksums(dir)
+	if got.Status != IntegrityUnverified || !strings.Contains(got.Reason, "metrics") {
+		t.Fatalf("expected unverified status: %+v", got)
+	}
+}
+
+func TestRunFlagsModifiedTasks(t *testing.T) {
+	layout, man := writeBundles(t, "task_001", "task_002")
+	for _, id := range []string{"task_001", "task_002"} {
+		writeTaskInputs(t, filepath.Join(layout.BundlesDir, id))
+	}
+	writeFile(t, filepath.Join(layout.BundlesDir, "task_002", "prompt.txt"), "Reply with prose.\n")
+
+	report, err := Run(Options{Layout: layout, Manifest: man})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if report.Summary.Modified != 1 {
+		t.Fatalf("expected one modified task: %+v", report.Summary)
+	}
+	if report.Tasks[0].Integrity.Status != IntegrityVerified || !report.Tasks[1].Integrity.Modified() {
+		t.Fatalf("unexpected integrity: %+v %+v", report.Tasks[0].Integrity, report.Tasks[1].Integrity)
+	}
+	if report.DaySummary.Integrity != nil {
+		t.Fatalf("day summary has no recorded checksums: %+v", report.DaySummary.Integrity)
+	}
+}
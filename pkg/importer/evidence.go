@@ -0,0 +1,135 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/offlinefirst/limitless-context/pkg/events"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+	"github.com/offlinefirst/limitless-context/pkg/screenshots"
+	"github.com/offlinefirst/limitless-context/pkg/video"
+)
+
+// Evidence summarises the citations in one output's evidence array.
+// ResolvedEvents, ResolvedShots, and ResolvedKeyframes split Resolved by
+// anchor kind. A shot citation can match both a screenshot and a keyframe, so
+// it counts toward both but resolves once.
+type Evidence struct {
+	Cited             int      `json:"cited"`
+	Resolved          int      `json:"resolved"`
+	ResolvedEvents    int      `json:"resolved_events"`
+	ResolvedShots     int      `json:"resolved_shots"`
+	ResolvedKeyframes int      `json:"resolved_keyframes"`
+	Dangling          []string `json:"dangling"`
+}
+
+// Resolver checks event:evt_NNNN and shot:mm:ss citations against the
+// events, screenshots, and video keyframes captured for a run.
+type Resolver struct {
+	events    map[string]struct{}
+	shots     map[string]struct{}
+	keyframes map[string]struct{}
+}
+
+// NewResolver indexes the run's events_fine.jsonl, screenshot metadata, and
+// keyframe metadata, since keyframes are cited as shots too.
+// Missing artifacts leave the corresponding references unresolvable.
+func NewResolver(layout runmanifest.Layout, man runmanifest.Manifest) (*Resolver, error) {
+	r := &Resolver{events: make(map[string]struct{}), shots: make(map[string]struct{}), keyframes: make(map[string]struct{})}
+
+	file, err := os.Open(filepath.Join(layout.EventsDir, "events_fine.jsonl"))
+	switch {
+	case err == nil:
+		defer file.Close()
+		if err := events.Scan(file, func(event events.Event) error {
+			r.events["event:"+event.ID] = struct{}{}
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("index events: %w", err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return nil, fmt.Errorf("open fine events: %w", err)
+	}
+
+	shots, err := screenshots.ReadDir(layout.ScreensDir)
+	if err != nil {
+		return nil, fmt.Errorf("index screenshots: %w", err)
+	}
+	keyframes, err := video.ReadKeyframes(layout.VideoDir)
+	if err != nil {
+		return nil, fmt.Errorf("index keyframes: %w", err)
+	}
+	base := man.CaptureStart()
+	for _, shot := range shots {
+		r.shots[screenshots.Ref(base, shot.Metadata.CapturedAt)] = struct{}{}
+	}
+	for _, frame := range keyframes {
+		r.keyframes[screenshots.Ref(base, frame.Metadata.CapturedAt)] = struct{}{}
+	}
+	return r, nil
+}
+
+// Resolves reports whether ref names a captured event, screenshot, or keyframe.
+func (r *Resolver) Resolves(ref string) bool {
+	if _, ok := r.events[ref]; ok {
+		return true
+	}
+	if _, ok := r.shots[ref]; ok {
+		return true
+	}
+	_, ok := r.keyframes[ref]
+	return ok
+}
+
+// Check resolves every citation, listing each dangling reference once.
+func (r *Resolver) Check(refs []string) Evidence {
+	out := Evidence{Cited: len(refs), Dangling: []string{}}
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		if _, ok := r.events[ref]; ok {
+			out.Resolved++
+			out.ResolvedEvents++
+			continue
+		}
+		_, shot := r.shots[ref]
+		_, frame := r.keyframes[ref]
+		if shot {
+			out.ResolvedShots++
+		}
+		if frame {
+			out.ResolvedKeyframes++
+		}
+		if shot || frame {
+			out.Resolved++
+			continue
+		}
+		if !seen[ref] {
+			seen[ref] = true
+			out.Dangling = append(out.Dangling, ref)
+		}
+	}
+	return out
+}
+
+// citations returns the string entries of a decoded output's evidence array,
+// or false when the output has none.
+func citations(value any) ([]string, bool) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	list, ok := object["evidence"].([]any)
+	if !ok {
+		return nil, false
+	}
+	refs := make([]string, 0, len(list))
+	for _, item := range list {
+		if ref, ok := item.(string); ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, true
+}
@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/events"
+	"github.com/offlinefirst/limitless-context/pkg/screenshots"
+	"github.com/offlinefirst/limitless-context/pkg/video"
+)
+
+// writeCapture records three events and one screenshot taken 65s into the run.
+func writeCapture(t *testing.T, eventsDir, screensDir string, start time.Time) {
+	t.Helper()
+	var lines []string
+	for i := 0; i < 3; i++ {
+		data, err := json.Marshal(events.Event{ID: events.SequenceID(i + 1), Timestamp: start.Add(time.Duration(i) * time.Second), Category: "mouse", Action: "click"})
+		if err != nil {
+			t.Fatalf("marshal event: %v", err)
+		}
+		lines = append(lines, string(data))
+	}
+	writeFile(t, filepath.Join(eventsDir, "events_fine.jsonl"), strings.Join(lines, "\n")+"\n")
+
+	data, err := json.Marshal(screenshots.Metadata{CapturedAt: start.Add(65 * time.Second), ImagePath: "screenshot_001.png"})
+	if err != nil {
+		t.Fatalf("marshal screenshot: %v", err)
+	}
+	writeFile(t, filepath.Join(screensDir, "screenshot_001.json"), string(data))
+}
+
+// writeKeyframe records a video keyframe taken offset into the run.
+func writeKeyframe(t *testing.T, videoDir, name string, capturedAt time.Time) {
+	t.Helper()
+	data, err := json.Marshal(screenshots.Metadata{CapturedAt: capturedAt, ImagePath: name + ".png", Backend: video.KeyframeBackend})
+	if err != nil {
+		t.Fatalf("marshal keyframe: %v", err)
+	}
+	writeFile(t, filepath.Join(videoDir, video.FramesDirName, name+".json"), string(data))
+}
+
+func TestResolverChecksEventsScreenshotsAndKeyframes(t *testing.T) {
+	layout, man := writeBundles(t)
+	start := time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+	man.CreatedAt = start
+	writeCapture(t, layout.EventsDir, layout.ScreensDir, start)
+	writeKeyframe(t, layout.VideoDir, "frame_0001", start.Add(65*time.Second))
+	writeKeyframe(t, layout.VideoDir, "frame_0002", start.Add(127*time.Second))
+
+	resolver, err := NewResolver(layout, man)
+	if err != nil {
+		t.Fatalf("new resolver: %v", err)
+	}
+	got := resolver.Check([]string{"event:evt_0001", "event:evt_0003", "shot:01:05", "event:evt_0009", "shot:01:06", "event:evt_0009", "shot:02:07"})
+	if got.Cited != 7 || got.Resolved != 4 || got.ResolvedEvents != 2 || got.ResolvedShots != 1 || got.ResolvedKeyframes != 2 || strings.Join(got.Dangling, ",") != "event:evt_0009,shot:01:06" {
+		t.Fatalf("unexpected evidence: %+v", got)
+	}
+}
+
+func TestRunReportsDanglingCitationsPerTask(t *testing.T) {
+	layout, man := writeBundles(t, "task_001", "task_002")
+	start := time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+	man.CreatedAt = start
+	writeCapture(t, layout.EventsDir, layout.ScreensDir, start)
+
+	writeFile(t, filepath.Join(layout.BundlesDir, "task_001", "output.json"), strings.Replace(validTask, "%s", "task_001", 1))
+	dangling := strings.Replace(strings.Replace(validTask, "%s", "task_002", 1), `["event:evt_0003"]`, `["event:evt_0002","event:evt_0042","shot:09:59"]`, 1)
+	writeFile(t, filepath.Join(layout.BundlesDir, "task_002", "output.json"), dangling)
+
+	report, err := Run(Options{Layout: layout, Manifest: man})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	first, second := report.Tasks[0], report.Tasks[1]
+	if !first.OK() || first.Evidence == nil || first.Evidence.Resolved != 1 || len(first.Evidence.Dangling) != 0 {
+		t.Fatalf("unexpected task_001 result: %+v", first)
+	}
+	if !second.OK() || second.Evidence == nil || strings.Join(second.Evidence.Dangling, ",") != "event:evt_0042,shot:09:59" {
+		t.Fatalf("dangling citations must be reported without failing validation: %+v %+v", second, second.Evidence)
+	}
+	if report.Summary.Cited != 4 || report.Summary.Resolved != 2 || report.Summary.Dangling != 2 {
+		t.Fatalf("unexpected summary: %+v", report.Summary)
+	}
+	if report.DaySummary.Evidence != nil {
+		t.Fatalf("day summary carries no evidence: %+v", report.DaySummary)
+	}
+
+	data, err := os.ReadFile(filepath.Join(layout.ImportDir, ReportFileName))
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	if !strings.Contains(string(data), `"dangling": [`) {
+		t.Fatalf("expected dangling list in report.json:\n%s", data)
+	}
+}
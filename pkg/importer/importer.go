@@ -0,0 +1,280 @@
+// Package importer validates the output.json replies saved into a run's
+// bundle folders and records the outcome in import/report.json. Every output
+// is checked independently so one corrupt or missing file never stops the rest.
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/prompts"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+// MaxOutputBytes is the largest output.json accepted.
+const MaxOutputBytes = 200 * 1024
+
+// ReportVersion is the import/report.json schema version.
+const ReportVersion = 1
+
+// ReportFileName is written under the run's import directory.
+const ReportFileName = "report.json"
+
+// Output states recorded per file in report.json.
+const (
+	StatusValid   = "valid"
+	StatusInvalid = "invalid"
+	StatusMissing = "missing"
+)
+
+// DaySummaryID identifies the day summary entry in a report.
+const DaySummaryID = "day_summary"
+
+// Options configure an import pass.
+type Options struct {
+	Layout   runmanifest.Layout
+	Manifest runmanifest.Manifest
+	Clock    func() time.Time
+}
+
+// Report is the contents of import/report.json.
+type Report struct {
+	SchemaVersion  int       `json:"schema_version"`
+	RunID          string    `json:"run_id"`
+	GeneratedAt    time.Time `json:"generated_at"`
+	MaxOutputBytes int       `json:"max_output_bytes"`
+	Summary        Summary   `json:"summary"`
+	Tasks          []Result  `json:"tasks"`
+	DaySummary     Result    `json:"day_summary"`
+}
+
+// Summary counts task outputs by status, tasks whose bundle inputs were
+// edited after bundling, and evidence citations.
+type Summary struct {
+	Tasks             int `json:"tasks"`
+	Valid             int `json:"valid"`
+	Invalid           int `json:"invalid"`
+	Missing           int `json:"missing"`
+	Modified          int `json:"modified"`
+	Cited             int `json:"cited"`
+	Resolved          int `json:"resolved"`
+	ResolvedEvents    int `json:"resolved_events"`
+	ResolvedShots     int `json:"resolved_shots"`
+	ResolvedKeyframes int `json:"resolved_keyframes"`
+	Dangling          int `json:"dangling"`
+}
+
+// Result is the validation outcome for one output.json.
+type Result struct {
+	TaskID string `json:"task_id"`
+	// Output is relative to the run root.
+	Output string   `json:"output"`
+	Status string   `json:"status"`
+	Bytes  int64    `json:"bytes,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+	// Evidence is set when the output parsed and carries an evidence array.
+	Evidence *Evidence `json:"evidence,omitempty"`
+	// Integrity is set for task bundles, which record input checksums.
+	Integrity *Integrity `json:"integrity,omitempty"`
+}
+
+// OK reports whether the output passed validation.
+func (r Result) OK() bool {
+	return r.Status == StatusValid
+}
+
+// Run validates bundles/task_*/output.json and bundles/day_summary/output.json
+// against the schema.json written beside each, resolves evidence citations
+// against the run's events and screenshots, verifies each task's prompt.txt
+// and context.md against the checksums in its metrics.json, then writes
+// import/report.json.
+// Only problems that prevent writing the report are returned as errors.
+func Run(opts Options) (Report, error) {
+	clock := opts.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	dayDir := filepath.Join(opts.Layout.BundlesDir, DaySummaryID)
+	if info, err := os.Stat(dayDir); err != nil || !info.IsDir() {
+		return Report{}, fmt.Errorf("no bundles found under %s; run tester bundle first", opts.Layout.BundlesDir)
+	}
+	taskDirs, err := filepath.Glob(filepath.Join(opts.Layout.BundlesDir, "task_*"))
+	if err != nil {
+		return Report{}, fmt.Errorf("list task bundles: %w", err)
+	}
+	sort.Strings(taskDirs)
+	resolver, err := NewResolver(opts.Layout, opts.Manifest)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		SchemaVersion:  ReportVersion,
+		RunID:          opts.Manifest.RunID,
+		GeneratedAt:    clock().UTC(),
+		MaxOutputBytes: MaxOutputBytes,
+		Tasks:          []Result{},
+	}
+	for _, dir := range taskDirs {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		integrity := verifyChecksums(dir)
+		result := checkOutput(opts.Layout.Root, dir, filepath.Base(dir), resolver, integrity.Edited("schema.json"))
+		result.Integrity = &integrity
+		if integrity.Modified() {
+			report.Summary.Modified++
+		}
+		report.Tasks = append(report.Tasks, result)
+		report.Summary.Tasks++
+		switch result.Status {
+		case StatusValid:
+			report.Summary.Valid++
+		case StatusMissing:
+			report.Summary.Missing++
+		default:
+			report.Summary.Invalid++
+		}
+		if result.Evidence != nil {
+			report.Summary.Cited += result.Evidence.Cited
+			report.Summary.Resolved += result.Evidence.Resolved
+			report.Summary.ResolvedEvents += result.Evidence.ResolvedEvents
+			report.Summary.ResolvedShots += result.Evidence.ResolvedShots
+			report.Summary.ResolvedKeyframes += result.Evidence.ResolvedKeyframes
+			report.Summary.Dangling += len(result.Evidence.Dangling)
+		}
+	}
+	report.DaySummary = checkOutput(opts.Layout.Root, dayDir, DaySummaryID, resolver, false)
+
+	if err := Save(report, filepath.Join(opts.Layout.ImportDir, ReportFileName)); err != nil {
+		return Report{}, err
+	}
+	return report, nil
+}
+
+// checkOutput validates dir/output.json against dir/schema.json. Dangling
+// citations are reported in Evidence but do not make the output invalid. An
+// output whose schema was edited after bundling is not validated, since the
+// contract it would be checked against is no longer the bundled one.
+func checkOutput(root, dir, id string, resolver *Resolver, schemaEdited bool) Result {
+	path := filepath.Join(dir, "output.json")
+	result := Result{TaskID: id, Output: relative(root, path), Status: StatusInvalid}
+	fail := func(err error) Result {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		result.Status = StatusMissing
+		result.Errors = []string{"output.json not found"}
+		return result
+	case err != nil:
+		return fail(err)
+	case !info.Mode().IsRegular():
+		return fail(errors.New("output.json is not a regular file"))
+	}
+	result.Bytes = info.Size()
+	if info.Size() > MaxOutputBytes {
+		return fail(fmt.Errorf("output.json is %d bytes; the limit is %d", info.Size(), MaxOutputBytes))
+	}
+	data, err := readLimited(path)
+	if err != nil {
+		return fail(err)
+	}
+	result.Bytes = int64(len(data))
+
+	if schemaEdited {
+		return fail(errors.New("schema.json was edited after bundling; restore the bundled copy, whose SHA-256 is in metrics.json"))
+	}
+	schemaData, err := os.ReadFile(filepath.Join(dir, "schema.json"))
+	if err != nil {
+		return fail(fmt.Errorf("read schema.json: %w; restore the bundled copy", err))
+	}
+	schema, err := prompts.ParseSchema(schemaData)
+	if err != nil {
+		return fail(fmt.Errorf("schema.json: %w", err))
+	}
+
+	value, err := Decode(data)
+	if err != nil {
+		return fail(err)
+	}
+	if refs, ok := citations(value); ok {
+		evidence := resolver.Check(refs)
+		result.Evidence = &evidence
+	}
+	for _, violation := range schema.Validate(value) {
+		result.Errors = append(result.Errors, violation.Error())
+	}
+	if len(result.Errors) == 0 {
+		result.Status = StatusValid
+	}
+	return result
+}
+
+// readLimited reads at most MaxOutputBytes, guarding against files that grow
+// between the size check and the read.
+func readLimited(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, MaxOutputBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxOutputBytes {
+		return nil, fmt.Errorf("output.json exceeds the %d byte limit", MaxOutputBytes)
+	}
+	return data, nil
+}
+
+func relative(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Save writes report JSON with indentation.
+func Save(report Report, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("ensure import directory: %w", err)
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal import report: %w", err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write import report: %w", err)
+	}
+	return nil
+}
+
+// Load reads an import/report.json file, rejecting unknown schema versions.
+func Load(path string) (Report, error) {
+	var report Report
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return report, fmt.Errorf("read import report: %w", err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return report, fmt.Errorf("decode import report: %w", err)
+	}
+	if report.SchemaVersion != ReportVersion {
+		return report, fmt.Errorf("import report schema version %d is unsupported (expected %d)", report.SchemaVersion, ReportVersion)
+	}
+	return report, nil
+}
@@ -0,0 +1,122 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/prompts"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+const validTask = `{"task_id":"%s","title":"Fix tests","summary":"Edited main.go and ran go test.","key_actions":["ran go test"],"evidence":["event:evt_0003"],"confidence":0.8}`
+
+// writeBundles lays out task folders and a day summary folder with the
+// schema.json files the bundler would have written.
+func writeBundles(t *testing.T, taskIDs ...string) (runmanifest.Layout, runmanifest.Manifest) {
+	t.Helper()
+	layout := runmanifest.BuildLayout(t.TempDir(), "20240512_093000")
+	set := prompts.NewSet(prompts.Options{})
+	for _, id := range taskIDs {
+		rendered, err := set.Render(prompts.TaskTemplate, prompts.TaskData{RunID: "20240512_093000", TaskID: id})
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		writeFile(t, filepath.Join(layout.BundlesDir, id, "schema.json"), string(rendered.SchemaJSON))
+	}
+	rendered, err := set.Render(prompts.DaySummaryTemplate, prompts.DaySummaryData{RunID: "20240512_093000", TaskIDs: taskIDs})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	writeFile(t, filepath.Join(layout.BundlesDir, DaySummaryID, "schema.json"), string(rendered.SchemaJSON))
+	return layout, runmanifest.Manifest{RunID: "20240512_093000"}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestRunRecordsFailuresWithoutAborting(t *testing.T) {
+	layout, man := writeBundles(t, "task_001", "task_002", "task_003", "task_004")
+	writeFile(t, filepath.Join(layout.BundlesDir, "task_001", "output.json"), strings.Replace(validTask, "%s", "task_001", 1))
+	writeFile(t, filepath.Join(layout.BundlesDir, "task_002", "output.json"), `{"task_id": "task_002", "title": `)
+	writeFile(t, filepath.Join(layout.BundlesDir, "task_004", "output.json"), strings.Replace(strings.Replace(validTask, "%s", "task_004", 1), `"confidence":0.8}`, `"confidence":0.8,"extra":true}`, 1))
+
+	now := time.Date(2024, 5, 12, 18, 0, 0, 0, time.UTC)
+	report, err := Run(Options{Layout: layout, Manifest: man, Clock: func() time.Time { return now }})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := report.Summary; got.Tasks != 4 || got.Valid != 1 || got.Invalid != 2 || got.Missing != 1 {
+		t.Fatalf("unexpected summary: %+v", report.Summary)
+	}
+	statuses := []string{StatusValid, StatusInvalid, StatusMissing, StatusInvalid}
+	for i, result := range report.Tasks {
+		if result.Status != statuses[i] {
+			t.Fatalf("%s: expected %s, got %+v", result.TaskID, statuses[i], result)
+		}
+	}
+	if report.Tasks[0].Output != "bundles/task_001/output.json" || !report.Tasks[0].OK() {
+		t.Fatalf("unexpected valid entry: %+v", report.Tasks[0])
+	}
+	if !strings.Contains(strings.Join(report.Tasks[1].Errors, "\n"), "invalid JSON") {
+		t.Fatalf("expected syntax error for task_002: %+v", report.Tasks[1])
+	}
+	if !strings.Contains(strings.Join(report.Tasks[3].Errors, "\n"), `unknown field "extra"`) {
+		t.Fatalf("expected unknown-field rejection for task_004: %+v", report.Tasks[3])
+	}
+	if report.DaySummary.Status != StatusMissing {
+		t.Fatalf("expected missing day summary, got %+v", report.DaySummary)
+	}
+
+	loaded, err := Load(filepath.Join(layout.ImportDir, ReportFileName))
+	if err != nil {
+		t.Fatalf("load report: %v", err)
+	}
+	if loaded.RunID != man.RunID || !loaded.GeneratedAt.Equal(now) || len(loaded.Tasks) != 4 || loaded.MaxOutputBytes != MaxOutputBytes {
+		t.Fatalf("unexpected stored report: %+v", loaded)
+	}
+}
+
+func TestRunValidatesDaySummary(t *testing.T) {
+	layout, man := writeBundles(t, "task_001")
+	writeFile(t, filepath.Join(layout.BundlesDir, DaySummaryID, "output.json"), `{"summary":"A day.","highlights":["shipped"],"tasks":[{"task_id":"task_001","summary":"Fixed tests."}]}`)
+
+	report, err := Run(Options{Layout: layout, Manifest: man})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !report.DaySummary.OK() || report.DaySummary.Output != "bundles/day_summary/output.json" {
+		t.Fatalf("expected valid day summary: %+v", report.DaySummary)
+	}
+}
+
+func TestRunEnforcesSizeLimit(t *testing.T) {
+	layout, man := writeBundles(t, "task_001")
+	padded := strings.Replace(validTask, "Edited main.go", strings.Repeat("x", MaxOutputBytes), 1)
+	writeFile(t, filepath.Join(layout.BundlesDir, "task_001", "output.json"), strings.Replace(padded, "%s", "task_001", 1))
+
+	report, err := Run(Options{Layout: layout, Manifest: man})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	result := report.Tasks[0]
+	if result.Status != StatusInvalid || !strings.Contains(result.Errors[0], "the limit is 204800") {
+		t.Fatalf("expected size rejection: %+v", result)
+	}
+}
+
+func TestRunRequiresBundles(t *testing.T) {
+	layout := runmanifest.BuildLayout(t.TempDir(), "run")
+	if _, err := Run(Options{Layout: layout}); err == nil || !strings.Contains(err.Error(), "tester bundle") {
+		t.Fatalf("expected missing bundles error, got %v", err)
+	}
+}
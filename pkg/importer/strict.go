@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Decode parses a single JSON document strictly: the input must be valid
+// UTF-8 without control characters, objects may not repeat a key, and
+// nothing but whitespace may follow the value. Values are returned as
+// encoding/json produces them for an any target.
+func Decode(data []byte) (any, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errors.New("output.json is empty")
+	}
+	if err := checkText(data); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	value, err := decodeValue(dec, "$")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON value at byte %d", dec.InputOffset())
+	}
+	return value, nil
+}
+
+// checkText rejects invalid UTF-8, a byte order mark, and control characters
+// other than the whitespace JSON permits between tokens.
+func checkText(data []byte) error {
+	if bytes.HasPrefix(data, []byte("\xef\xbb\xbf")) {
+		return errors.New("output.json starts with a byte order mark")
+	}
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			return fmt.Errorf("invalid UTF-8 at byte %d", i)
+		}
+		if r != '\t' && r != '\n' && r != '\r' && isControl(r) {
+			return fmt.Errorf("control character %U at byte %d", r, i)
+		}
+		i += size
+	}
+	return nil
+}
+
+func decodeValue(dec *json.Decoder, path string) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, syntaxError(err)
+	}
+	switch tok {
+	case json.Delim('{'):
+		object := make(map[string]any)
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, syntaxError(err)
+			}
+			key := keyTok.(string)
+			if _, dup := object[key]; dup {
+				return nil, fmt.Errorf("%s: duplicate field %q", path, key)
+			}
+			if err := checkString(path+"."+key, key); err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(dec, path+"."+key)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = value
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, syntaxError(err)
+		}
+		return object, nil
+	case json.Delim('['):
+		array := []any{}
+		for dec.More() {
+			value, err := decodeValue(dec, fmt.Sprintf("%s[%d]", path, len(array)))
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, syntaxError(err)
+		}
+		return array, nil
+	}
+	if s, ok := tok.(string); ok {
+		if err := checkString(path, s); err != nil {
+			return nil, err
+		}
+	}
+	return tok, nil
+}
+
+// checkString rejects control characters smuggled in through \u escapes.
+// Escaped tabs and newlines are allowed inside strings.
+func checkString(path, s string) error {
+	for _, r := range s {
+		if r != '\t' && r != '\n' && isControl(r) {
+			return fmt.Errorf("%s: string contains control character %U", path, r)
+		}
+	}
+	return nil
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || (r >= 0x7f && r <= 0x9f)
+}
+
+func syntaxError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return errors.New("invalid JSON: unexpected end of JSON input")
+	}
+	return fmt.Errorf("invalid JSON: %w", err)
+}
@@ -0,0 +1,44 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeAcceptsPlainJSON(t *testing.T) {
+	value, err := Decode([]byte("{\n\t\"summary\": \"line one\\nline two\",\r\n\"n\": [1, 2.5, true, null]\n}\n"))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	object := value.(map[string]any)
+	if object["summary"] != "line one\nline two" || len(object["n"].([]any)) != 4 {
+		t.Fatalf("unexpected value: %#v", value)
+	}
+}
+
+func TestDecodeRejectsMalformedInput(t *testing.T) {
+	cases := map[string]struct {
+		input string
+		want  string
+	}{
+		"empty":           {"  \n", "empty"},
+		"trailing":        {`{"a":1} {"b":2}`, "unexpected data after the JSON value"},
+		"prose":           {"Here you go: {}", "invalid JSON"},
+		"fenced":          {"```json\n{}\n```", "invalid JSON"},
+		"truncated":       {`{"a": [1, 2`, "unexpected end of JSON input"},
+		"duplicate":       {`{"a":1,"a":2}`, `$: duplicate field "a"`},
+		"raw control":     {"{\"a\":\"bell\x07\"}", "control character U+0007 at byte 10"},
+		"escaped control": {`{"a":{"b":"nul\u0000"}}`, "$.a.b: string contains control character U+0000"},
+		"c1 control":      {"{\"a\":\"\u0085\"}", "control character U+0085"},
+		"invalid utf8":    {"{\"a\":\"\xff\"}", "invalid UTF-8 at byte 6"},
+		"bom":             {"\xef\xbb\xbf{}", "byte order mark"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Decode([]byte(tc.input))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
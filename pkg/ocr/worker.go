@@ -52,12 +52,19 @@ type ocrStatus struct {
 	Notes              []string  `json:"notes,omitempty"`
 }
 
-type indexEntry struct {
+// IndexEntry records the recognised text for a single screenshot.
+type IndexEntry struct {
 	Screenshot string `json:"screenshot"`
 	Text       string `json:"text"`
 	Language   string `json:"language"`
 }
 
+// Index is the document persisted to ocr/index.json.
+type Index struct {
+	GeneratedAt time.Time    `json:"generated_at"`
+	Entries     []IndexEntry `json:"entries"`
+}
+
 // NewWorker constructs a worker instance.
 func NewWorker(opts Options) (*Worker, error) {
 	if len(opts.Languages) == 0 {
@@ -109,7 +116,7 @@ func (w *Worker) Process(ctx context.Context, screenshots []string, destDir stri
 	available := w.tesseractAvailable()
 	processed := 0
 	skipped := 0
-	entries := make([]indexEntry, 0, len(screenshots))
+	entries := make([]IndexEntry, 0, len(screenshots))
 
 	for _, shot := range screenshots {
 		if ctx != nil && ctx.Err() != nil {
@@ -121,7 +128,7 @@ func (w *Worker) Process(ctx context.Context, screenshots []string, destDir stri
 			continue
 		}
 		recognised := w.redactor.ApplyString(recognisedText)
-		entries = append(entries, indexEntry{
+		entries = append(entries, IndexEntry{
 			Screenshot: filepath.Base(shot),
 			Text:       recognised,
 			Language:   w.languages[0],
@@ -131,10 +138,7 @@ func (w *Worker) Process(ctx context.Context, screenshots []string, destDir stri
 
 	indexPath := filepath.Join(destDir, "index.json")
 	if len(entries) > 0 {
-		indexDoc := struct {
-			GeneratedAt time.Time    `json:"generated_at"`
-			Entries     []indexEntry `json:"entries"`
-		}{
+		indexDoc := Index{
 			GeneratedAt: w.clock().UTC(),
 			Entries:     entries,
 		}
@@ -179,6 +183,19 @@ func (w *Worker) Process(ctx context.Context, screenshots []string, destDir stri
 	}, nil
 }
 
+// LoadIndex reads an OCR index document from disk.
+func LoadIndex(path string) (Index, error) {
+	var index Index
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return index, fmt.Errorf("read ocr index: %w", err)
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return index, fmt.Errorf("decode ocr index: %w", err)
+	}
+	return index, nil
+}
+
 func (w *Worker) extractText(path string) (string, error) {
 	ext := strings.ToLower(filepath.Ext(path))
 	switch ext {
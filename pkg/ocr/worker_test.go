@@ -134,3 +134,38 @@ func TestWorkerHandlesMissingBinary(t *testing.T) {
 		t.Fatalf("expected status to mention missing binary: %s", string(statusData))
 	}
 }
+
+func TestLoadIndexRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	worker, err := NewWorker(Options{
+		Languages: []string{"eng"},
+		LookPath:  func(string) (string, error) { return "", os.ErrNotExist },
+		Clock:     func() time.Time { return time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC) },
+	})
+	if err != nil {
+		t.Fatalf("new worker: %v", err)
+	}
+
+	shot := filepath.Join(t.TempDir(), "screenshot_001.txt")
+	if err := os.WriteFile(shot, []byte("quarterly plan\n"), 0o644); err != nil {
+		t.Fatalf("write screenshot fixture: %v", err)
+	}
+	result, err := worker.Process(context.Background(), []string{shot}, dir)
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+
+	index, err := LoadIndex(result.IndexPath)
+	if err != nil {
+		t.Fatalf("load index: %v", err)
+	}
+	if len(index.Entries) != 1 {
+		t.Fatalf("expected one entry, got %d", len(index.Entries))
+	}
+	if index.Entries[0].Screenshot != "screenshot_001.txt" || index.Entries[0].Text != "quarterly plan" {
+		t.Fatalf("unexpected entry: %+v", index.Entries[0])
+	}
+	if _, err := LoadIndex(filepath.Join(dir, "missing.json")); err == nil {
+		t.Fatalf("expected error for missing index")
+	}
+}
@@ -0,0 +1,296 @@
+// Package privacyscan searches every text artifact in a run for sensitive
+// data that escaped redaction and records the findings in
+// report/privacy_scan.json. It uses the redactor's named patterns plus a few
+// extra detectors, and counts the [REDACTED] masks that redaction left behind.
+package privacyscan
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/events"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+// ReportVersion is the privacy_scan.json schema version.
+const ReportVersion = 1
+
+// FileName is written under the run's report directory.
+const FileName = "privacy_scan.json"
+
+// MaxViolations caps the violations listed in the report. Counts always
+// cover every match.
+const MaxViolations = 500
+
+// TextExtensions are the artifact types the scanner reads.
+var TextExtensions = []string{".jsonl", ".json", ".vtt", ".md", ".txt", ".log"}
+
+// redactorDetectors are the events.NamedPatterns entries, in report order.
+var redactorDetectors = []string{"email", "cc16", "jwt"}
+
+// extraDetectors catch secrets the redactor has no named pattern for.
+var extraDetectors = []struct{ name, expr string }{
+	{"aws_access_key", `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+	{"private_key", `-----BEGIN [A-Z ]*PRIVATE KEY-----`},
+	{"bearer_token", `(?i)\bbearer\s+[A-Za-z0-9._~+/-]{20,}=*`},
+	{"us_ssn", `\b\d{3}-\d{2}-\d{4}\b`},
+}
+
+// Detector is a named expression the scanner searches for.
+type Detector struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// Detectors returns the redactor's named patterns, the extra detectors, and
+// any custom expressions from capture.events.redact_patterns. Custom entries
+// that name a built-in pattern are already covered and are skipped.
+func Detectors(custom []string) ([]Detector, error) {
+	var detectors []Detector
+	for _, name := range redactorDetectors {
+		detectors = append(detectors, Detector{Name: name, Pattern: regexp.MustCompile(events.NamedPatterns[name])})
+	}
+	for _, extra := range extraDetectors {
+		detectors = append(detectors, Detector{Name: extra.name, Pattern: regexp.MustCompile(extra.expr)})
+	}
+	n := 0
+	for _, expr := range custom {
+		trimmed := strings.TrimSpace(expr)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := events.NamedPatterns[strings.ToLower(trimmed)]; ok {
+			continue
+		}
+		rx, err := regexp.Compile(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("redact pattern %q: %w", trimmed, err)
+		}
+		n++
+		detectors = append(detectors, Detector{Name: fmt.Sprintf("custom_%d", n), Pattern: rx})
+	}
+	return detectors, nil
+}
+
+// Options configure a scan.
+type Options struct {
+	Layout   runmanifest.Layout
+	Manifest runmanifest.Manifest
+	// Patterns are extra expressions, normally capture.events.redact_patterns.
+	Patterns []string
+	Clock    func() time.Time
+}
+
+// Report is the contents of report/privacy_scan.json.
+type Report struct {
+	SchemaVersion int              `json:"schema_version"`
+	RunID         string           `json:"run_id"`
+	GeneratedAt   time.Time        `json:"generated_at"`
+	Summary       Summary          `json:"summary"`
+	Detectors     []DetectorResult `json:"detectors"`
+	// Files lists only files with masks or unmasked matches.
+	Files      []FileResult `json:"files"`
+	Violations []Violation  `json:"violations"`
+	// Truncated is set when more than MaxViolations matches were found.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Summary totals the scan.
+type Summary struct {
+	Files    int   `json:"files"`
+	Bytes    int64 `json:"bytes"`
+	Masked   int   `json:"masked"`
+	Unmasked int   `json:"unmasked"`
+}
+
+// DetectorResult counts unmasked matches for one detector.
+type DetectorResult struct {
+	Name     string `json:"name"`
+	Pattern  string `json:"pattern"`
+	Unmasked int    `json:"unmasked"`
+}
+
+// FileResult counts masks and unmasked matches in one file.
+type FileResult struct {
+	// Path is slash-separated and relative to the run root.
+	Path     string `json:"path"`
+	Masked   int    `json:"masked"`
+	Unmasked int    `json:"unmasked"`
+}
+
+// Violation is one unmasked match. Preview hides most of the matched text so
+// the report does not repeat what it found.
+type Violation struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Detector string `json:"detector"`
+	Preview  string `json:"preview"`
+}
+
+// Clean reports whether the scan found no unmasked matches.
+func (r Report) Clean() bool {
+	return r.Summary.Unmasked == 0
+}
+
+// Scan reads every text artifact under the run root line by line. The
+// report directory is skipped because it only holds derived copies, and a
+// run root that does not exist yet scans as empty.
+func Scan(opts Options) (Report, error) {
+	clock := opts.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	detectors, err := Detectors(opts.Patterns)
+	if err != nil {
+		return Report{}, err
+	}
+	report := Report{
+		SchemaVersion: ReportVersion,
+		RunID:         opts.Manifest.RunID,
+		GeneratedAt:   clock().UTC(),
+		Files:         []FileResult{},
+		Violations:    []Violation{},
+	}
+	counts := make([]int, len(detectors))
+
+	root := opts.Layout.Root
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() {
+			if path == opts.Layout.ReportDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || !isText(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		result := FileResult{Path: filepath.ToSlash(rel)}
+		size, err := scanFile(path, func(line int, text string) {
+			result.Masked += strings.Count(text, events.RedactionMask)
+			for i, detector := range detectors {
+				for _, match := range detector.Pattern.FindAllString(text, -1) {
+					counts[i]++
+					result.Unmasked++
+					if len(report.Violations) == MaxViolations {
+						report.Truncated = true
+						continue
+					}
+					report.Violations = append(report.Violations, Violation{Path: result.Path, Line: line, Detector: detector.Name, Preview: preview(match)})
+				}
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("scan %s: %w", result.Path, err)
+		}
+		report.Summary.Files++
+		report.Summary.Bytes += size
+		report.Summary.Masked += result.Masked
+		report.Summary.Unmasked += result.Unmasked
+		if result.Masked > 0 || result.Unmasked > 0 {
+			report.Files = append(report.Files, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+	for i, detector := range detectors {
+		report.Detectors = append(report.Detectors, DetectorResult{Name: detector.Name, Pattern: detector.Pattern.String(), Unmasked: counts[i]})
+	}
+	return report, nil
+}
+
+func isText(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, candidate := range TextExtensions {
+		if ext == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// scanFile calls visit with each 1-based line number and line, and returns
+// the bytes read. Lines have no length limit.
+func scanFile(path string, visit func(line int, text string)) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	var size int64
+	for line := 1; ; line++ {
+		text, err := reader.ReadString('\n')
+		size += int64(len(text))
+		if text != "" {
+			visit(line, text)
+		}
+		if errors.Is(err, io.EOF) {
+			return size, nil
+		}
+		if err != nil {
+			return size, err
+		}
+	}
+}
+
+// preview keeps the first and last two characters of a match.
+func preview(match string) string {
+	runes := []rune(strings.TrimSpace(match))
+	if len(runes) <= 6 {
+		return strings.Repeat("*", len(runes))
+	}
+	return fmt.Sprintf("%s…%s (%d chars)", string(runes[:2]), string(runes[len(runes)-2:]), len(runes))
+}
+
+// Save writes the report as indented JSON, creating the directory if needed.
+func Save(report Report, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("ensure report directory: %w", err)
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal privacy scan: %w", err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write privacy scan: %w", err)
+	}
+	return nil
+}
+
+// Load reads a privacy_scan.json file, rejecting unknown schema versions.
+func Load(path string) (Report, error) {
+	var report Report
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return report, fmt.Errorf("read privacy scan: %w", err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return report, fmt.Errorf("decode privacy scan: %w", err)
+	}
+	if report.SchemaVersion != ReportVersion {
+		return report, fmt.Errorf("privacy scan schema version %d is unsupported (expected %d)", report.SchemaVersion, ReportVersion)
+	}
+	return report, nil
+}
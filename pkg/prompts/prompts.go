@@ -0,0 +1,147 @@
+// Package prompts renders bundle prompts from text/template files. Each
+// template defines a "schema" block holding the JSON Schema its reply must
+// satisfy, rendered with the same data as the prompt so the process step can
+// validate against the exact contract that was sent.
+package prompts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Built-in template names.
+const (
+	TaskTemplate       = "task"
+	DaySummaryTemplate = "day_summary"
+)
+
+// SourceEmbedded marks templates loaded from the binary's default set.
+const SourceEmbedded = "embedded"
+
+//go:embed templates/*.tmpl
+var embedded embed.FS
+
+// TaskData is passed to the task template.
+type TaskData struct {
+	RunID  string
+	TaskID string
+}
+
+// DaySummaryData is passed to the day summary template.
+type DaySummaryData struct {
+	RunID   string
+	TaskIDs []string
+}
+
+// Options configure where override templates are looked up.
+type Options struct {
+	// Dirs are searched in order for <name>.tmpl before the embedded defaults.
+	Dirs []string
+}
+
+// Set resolves templates by name.
+type Set struct {
+	dirs []string
+}
+
+// Rendered is a prompt plus the contract it declares.
+type Rendered struct {
+	Name   string
+	Source string
+	SHA256 string
+	Prompt string
+	Schema *Schema
+	// SchemaJSON is the rendered schema, indented for writing to disk.
+	SchemaJSON []byte
+}
+
+// NewSet constructs a template set. Empty directories are ignored.
+func NewSet(opts Options) *Set {
+	dirs := make([]string, 0, len(opts.Dirs))
+	for _, dir := range opts.Dirs {
+		if strings.TrimSpace(dir) != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return &Set{dirs: dirs}
+}
+
+// Render executes the named template and its schema block with data.
+func (s *Set) Render(name string, data any) (Rendered, error) {
+	src, source, err := s.load(name)
+	if err != nil {
+		return Rendered{}, err
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{"json": toJSON}).Parse(string(src))
+	if err != nil {
+		return Rendered{}, fmt.Errorf("parse template %s (%s): %w", name, source, err)
+	}
+	if tmpl.Lookup("schema") == nil {
+		return Rendered{}, fmt.Errorf("template %s (%s) must define a \"schema\" block", name, source)
+	}
+
+	var prompt bytes.Buffer
+	if err := tmpl.Execute(&prompt, data); err != nil {
+		return Rendered{}, fmt.Errorf("render template %s: %w", name, err)
+	}
+	var rawSchema bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&rawSchema, "schema", data); err != nil {
+		return Rendered{}, fmt.Errorf("render schema for %s: %w", name, err)
+	}
+	schema, err := ParseSchema(rawSchema.Bytes())
+	if err != nil {
+		return Rendered{}, fmt.Errorf("schema for %s: %w", name, err)
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, rawSchema.Bytes(), "", "  "); err != nil {
+		return Rendered{}, fmt.Errorf("format schema for %s: %w", name, err)
+	}
+	indented.WriteByte('\n')
+
+	sum := sha256.Sum256(src)
+	return Rendered{
+		Name:       name,
+		Source:     source,
+		SHA256:     hex.EncodeToString(sum[:]),
+		Prompt:     prompt.String(),
+		Schema:     schema,
+		SchemaJSON: indented.Bytes(),
+	}, nil
+}
+
+// load returns the first override for name, falling back to the embedded default.
+func (s *Set) load(name string) ([]byte, string, error) {
+	file := name + ".tmpl"
+	for _, dir := range s.dirs {
+		path := filepath.Join(dir, file)
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return data, path, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, "", fmt.Errorf("read template override: %w", err)
+		}
+	}
+	data, err := embedded.ReadFile("templates/" + file)
+	if err != nil {
+		return nil, "", fmt.Errorf("unknown prompt template %q", name)
+	}
+	return data, SourceEmbedded, nil
+}
+
+func toJSON(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,97 @@
+package prompts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderEmbeddedTaskTemplate(t *testing.T) {
+	set := NewSet(Options{Dirs: []string{"", t.TempDir()}})
+	out, err := set.Render(TaskTemplate, TaskData{RunID: "20240512_093000", TaskID: "task_002"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if out.Name != TaskTemplate || out.Source != SourceEmbedded || len(out.SHA256) != 64 {
+		t.Fatalf("unexpected template identity: %+v", out)
+	}
+	if !strings.Contains(out.Prompt, `"task_id": "task_002",`) || strings.Contains(out.Prompt, `"additionalProperties"`) {
+		t.Fatalf("unexpected prompt:\n%s", out.Prompt)
+	}
+	if strings.HasPrefix(out.Prompt, "\n") {
+		t.Fatalf("schema block must not leave leading whitespace in the prompt")
+	}
+	if got := out.Schema.Properties["task_id"].Const; got != "task_002" {
+		t.Fatalf("expected task_id const to be rendered, got %v", got)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(out.SchemaJSON, &decoded); err != nil {
+		t.Fatalf("schema JSON invalid: %v", err)
+	}
+
+	again, err := set.Render(TaskTemplate, TaskData{RunID: "other", TaskID: "task_009"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if again.SHA256 != out.SHA256 {
+		t.Fatalf("hash must identify the template source, not the rendered output")
+	}
+}
+
+func TestRenderDaySummaryTemplate(t *testing.T) {
+	out, err := NewSet(Options{}).Render(DaySummaryTemplate, DaySummaryData{RunID: "run", TaskIDs: []string{"task_001", "task_002"}})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	tasks := out.Schema.Properties["tasks"]
+	if tasks == nil || tasks.MinItems == nil || *tasks.MinItems != 2 || len(tasks.Items.Properties["task_id"].Enum) != 2 {
+		t.Fatalf("unexpected tasks schema: %+v", tasks)
+	}
+}
+
+func TestRenderPrefersOverrideDirectory(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	override := `{{define "schema"}}{"type":"object","required":["summary"],"properties":{"summary":{"type":"string"}}}{{end}}Custom prompt for {{.TaskID}}.`
+	if err := os.WriteFile(filepath.Join(second, "task.tmpl"), []byte(override), 0o644); err != nil {
+		t.Fatalf("write override: %v", err)
+	}
+
+	out, err := NewSet(Options{Dirs: []string{first, second}}).Render(TaskTemplate, TaskData{TaskID: "task_001"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if out.Source != filepath.Join(second, "task.tmpl") || out.Prompt != "Custom prompt for task_001." {
+		t.Fatalf("override not used: %+v", out)
+	}
+	embeddedOut, err := NewSet(Options{}).Render(TaskTemplate, TaskData{TaskID: "task_001"})
+	if err != nil {
+		t.Fatalf("render embedded: %v", err)
+	}
+	if out.SHA256 == embeddedOut.SHA256 {
+		t.Fatalf("override must change the template hash")
+	}
+}
+
+func TestRenderRejectsBrokenTemplates(t *testing.T) {
+	cases := map[string]string{
+		"missing schema": `Prompt without a contract.`,
+		"invalid json":   `{{define "schema"}}{not json{{end}}Prompt`,
+		"undeclared":     `{{define "schema"}}{"type":"object","required":["x"]}{{end}}Prompt`,
+		"bad pattern":    `{{define "schema"}}{"type":"string","pattern":"("}{{end}}Prompt`,
+		"array no items": `{{define "schema"}}{"type":"array"}{{end}}Prompt`,
+	}
+	for name, src := range cases {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "task.tmpl"), []byte(src), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		if _, err := NewSet(Options{Dirs: []string{dir}}).Render(TaskTemplate, TaskData{TaskID: "task_001"}); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+	if _, err := NewSet(Options{}).Render("nope", nil); err == nil {
+		t.Fatalf("expected unknown template error")
+	}
+}
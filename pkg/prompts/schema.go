@@ -0,0 +1,226 @@
+package prompts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// Schema is the JSON Schema subset prompt templates may declare: type,
+// required, properties, additionalProperties, items, const, enum, string
+// length and pattern, numeric range, and array length.
+type Schema struct {
+	Type                 string             `json:"type"`
+	Required             []string           `json:"required,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Const                any                `json:"const,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// ParseSchema decodes and sanity-checks a schema document.
+func ParseSchema(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("decode schema: %w", err)
+	}
+	if err := schema.check("$"); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+func (s *Schema) check(path string) error {
+	switch s.Type {
+	case "object", "array", "string", "number", "integer", "boolean":
+	case "":
+		return fmt.Errorf("%s: type is required", path)
+	default:
+		return fmt.Errorf("%s: unsupported type %q", path, s.Type)
+	}
+	if s.Pattern != "" {
+		if _, err := regexp.Compile(s.Pattern); err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", path, err)
+		}
+	}
+	if s.Type == "array" && s.Items == nil {
+		return fmt.Errorf("%s: array schema must declare items", path)
+	}
+	if s.Items != nil {
+		if err := s.Items.check(path + "[]"); err != nil {
+			return err
+		}
+	}
+	for _, name := range s.Required {
+		if _, ok := s.Properties[name]; !ok {
+			return fmt.Errorf("%s: required property %q is not declared", path, name)
+		}
+	}
+	for name, prop := range s.Properties {
+		if prop == nil {
+			return errors.New(path + "." + name + ": property schema must not be null")
+		}
+		if err := prop.check(path + "." + name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Violation is a value that does not satisfy a schema.
+type Violation struct {
+	Path    string
+	Message string
+}
+
+func (v Violation) Error() string {
+	return v.Path + ": " + v.Message
+}
+
+// Validate checks value, as decoded by encoding/json into any, against the
+// schema and returns every violation found in document order.
+func (s *Schema) Validate(value any) []Violation {
+	var out []Violation
+	s.validate("$", value, &out)
+	return out
+}
+
+func (s *Schema) validate(path string, value any, out *[]Violation) {
+	fail := func(format string, args ...any) {
+		*out = append(*out, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if !matchesType(s.Type, value) {
+		fail("expected %s, got %s", s.Type, jsonType(value))
+		return
+	}
+	if s.Const != nil && !reflect.DeepEqual(s.Const, value) {
+		fail("must equal %s", mustJSON(s.Const))
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, allowed := range s.Enum {
+			if reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %s", mustJSON(s.Enum))
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			fail("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			fail("must be at most %d characters, got %d", *s.MaxLength, length)
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(v) {
+			fail("%q does not match %s", v, s.Pattern)
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			fail("must be >= %v", *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			fail("must be <= %v", *s.Maximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail("must have at least %d items, got %d", *s.MinItems, len(v))
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail("must have at most %d items, got %d", *s.MaxItems, len(v))
+		}
+		for i, item := range v {
+			s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, out)
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				fail("missing required field %q", name)
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					fail("unknown field %q", name)
+				}
+				continue
+			}
+			prop.validate(path+"."+name, v[name], out)
+		}
+	}
+}
+
+func matchesType(want string, value any) bool {
+	switch want {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	}
+	return false
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func mustJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
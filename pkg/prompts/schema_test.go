@@ -0,0 +1,70 @@
+package prompts
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decodeValue(t *testing.T, doc string) any {
+	t.Helper()
+	var value any
+	if err := json.Unmarshal([]byte(doc), &value); err != nil {
+		t.Fatalf("decode %s: %v", doc, err)
+	}
+	return value
+}
+
+func TestValidateAcceptsTaskOutput(t *testing.T) {
+	out, err := NewSet(Options{}).Render(TaskTemplate, TaskData{RunID: "run", TaskID: "task_001"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	value := decodeValue(t, `{"task_id":"task_001","title":"Fix tests","summary":"Ran go test.","key_actions":["ran go test"],"evidence":["event:evt_0003","shot:05:10"],"confidence":0.8}`)
+	if violations := out.Schema.Validate(value); len(violations) != 0 {
+		t.Fatalf("expected valid output, got %v", violations)
+	}
+}
+
+func TestValidateReportsEveryViolation(t *testing.T) {
+	out, err := NewSet(Options{}).Render(TaskTemplate, TaskData{RunID: "run", TaskID: "task_001"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	value := decodeValue(t, `{"task_id":"task_002","title":"`+strings.Repeat("x", 81)+`","key_actions":"none","evidence":["evt_1"],"confidence":1.5,"mood":"happy"}`)
+	violations := out.Schema.Validate(value)
+
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.Error())
+	}
+	joined := strings.Join(got, "\n")
+	for _, want := range []string{
+		`$: missing required field "summary"`,
+		`$: unknown field "mood"`,
+		`$.task_id: must equal "task_001"`,
+		`$.title: must be at most 80 characters, got 81`,
+		`$.key_actions: expected array, got string`,
+		`$.evidence[0]: "evt_1" does not match`,
+		`$.confidence: must be <= 1`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("missing violation %q in:\n%s", want, joined)
+		}
+	}
+}
+
+func TestValidateDaySummaryTaskCount(t *testing.T) {
+	out, err := NewSet(Options{}).Render(DaySummaryTemplate, DaySummaryData{RunID: "run", TaskIDs: []string{"task_001", "task_002"}})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	value := decodeValue(t, `{"summary":"A day.","highlights":[],"tasks":[{"task_id":"task_003","summary":"Other."}]}`)
+	joined := ""
+	for _, v := range out.Schema.Validate(value) {
+		joined += v.Error() + "\n"
+	}
+	if !strings.Contains(joined, "$.tasks: must have at least 2 items, got 1") || !strings.Contains(joined, `$.tasks[0].task_id: must be one of ["task_001","task_002"]`) {
+		t.Fatalf("unexpected violations:\n%s", joined)
+	}
+}
@@ -0,0 +1,29 @@
+// Click a header marked data-sort to sort its table; click again to reverse.
+(function () {
+  function cellValue(row, index, numeric) {
+    var cell = row.cells[index];
+    var raw = cell ? (cell.getAttribute("data-value") || cell.textContent.trim()) : "";
+    if (!numeric) return raw.toLowerCase();
+    var n = parseFloat(raw);
+    return isNaN(n) ? -Infinity : n;
+  }
+  document.querySelectorAll("th[data-sort]").forEach(function (th) {
+    th.addEventListener("click", function () {
+      var table = th.closest("table");
+      var body = table.tBodies[0];
+      var index = Array.prototype.indexOf.call(th.parentNode.children, th);
+      var numeric = th.getAttribute("data-sort") === "num";
+      var ascending = th.getAttribute("data-dir") !== "asc";
+      table.querySelectorAll("th[data-sort]").forEach(function (other) { other.removeAttribute("data-dir"); });
+      th.setAttribute("data-dir", ascending ? "asc" : "desc");
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = cellValue(a, index, numeric), y = cellValue(b, index, numeric);
+        if (x < y) return ascending ? -1 : 1;
+        if (x > y) return ascending ? 1 : -1;
+        return 0;
+      });
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+})();
@@ -0,0 +1,102 @@
+// Plot the embedded timeline data as one row per lane. Items are links to
+// their artifacts; the buttons or Ctrl+scroll change the zoom.
+(function () {
+  var source = document.getElementById("timeline-data");
+  var root = document.getElementById("timeline");
+  if (!source || !root) return;
+  var data = JSON.parse(source.textContent);
+  var items = data.items || [];
+  var start = Date.parse(data.start);
+  var span = Math.max(Date.parse(data.end) - start, 1000);
+  var labels = root.querySelector(".tl-labels");
+  var viewport = root.querySelector(".tl-viewport");
+  var canvas = root.querySelector(".tl-canvas");
+  var zoom = 1;
+  var minZoom = 1, maxZoom = 256;
+
+  function percent(ms) { return (ms - start) / span * 100; }
+
+  function offset(ms) {
+    var total = Math.round(Math.max(ms - start, 0) / 1000);
+    var h = Math.floor(total / 3600), m = Math.floor(total / 60) % 60, s = total % 60;
+    var mmss = (m < 10 ? "0" : "") + m + ":" + (s < 10 ? "0" : "") + s;
+    return h ? h + ":" + mmss : mmss;
+  }
+
+  function element(tag, className, text) {
+    var el = document.createElement(tag);
+    el.className = className;
+    if (text) el.textContent = text;
+    return el;
+  }
+
+  var axis = element("div", "tl-axis");
+  canvas.appendChild(axis);
+  labels.appendChild(element("div", "tl-label tl-axis-label", "Offset"));
+
+  (data.lanes || []).forEach(function (lane) {
+    var laneItems = items.filter(function (item) { return item.lane === lane; });
+    labels.appendChild(element("div", "tl-label", lane + " (" + laneItems.length + ")"));
+    var track = element("div", "tl-track");
+    laneItems.forEach(function (item) {
+      var from = Date.parse(item.start);
+      var link = element("a", "tl-item tl-" + lane + (item.end ? " tl-span" : " tl-point"));
+      link.style.left = percent(from) + "%";
+      var when = offset(from);
+      if (item.end) {
+        var to = Date.parse(item.end);
+        link.style.width = Math.max(percent(to) - percent(from), 0) + "%";
+        when += "–" + offset(to);
+      }
+      link.title = item.label + " (" + when + ")";
+      if (item.href) link.href = item.href;
+      track.appendChild(link);
+    });
+    canvas.appendChild(track);
+  });
+
+  // drawAxis spaces ticks about every 100px at the current zoom.
+  function drawAxis() {
+    axis.textContent = "";
+    var width = canvas.clientWidth || 1;
+    var steps = [1, 5, 15, 30, 60, 300, 600, 900, 1800, 3600, 7200].map(function (s) { return s * 1000; });
+    var step = steps[steps.length - 1];
+    for (var i = 0; i < steps.length; i++) {
+      if (steps[i] / span * width >= 100) { step = steps[i]; break; }
+    }
+    for (var t = 0; t <= span; t += step) {
+      var tick = element("span", "tl-tick", offset(start + t));
+      tick.style.left = percent(start + t) + "%";
+      axis.appendChild(tick);
+    }
+  }
+
+  function setZoom(next, anchor) {
+    next = Math.min(maxZoom, Math.max(minZoom, next));
+    if (next === zoom) return;
+    var x = anchor === undefined ? viewport.clientWidth / 2 : anchor;
+    var ratio = (viewport.scrollLeft + x) / canvas.clientWidth;
+    zoom = next;
+    canvas.style.width = zoom * 100 + "%";
+    viewport.scrollLeft = ratio * canvas.clientWidth - x;
+    drawAxis();
+  }
+
+  root.querySelectorAll("button[data-zoom]").forEach(function (button) {
+    button.addEventListener("click", function () {
+      var action = button.getAttribute("data-zoom");
+      if (action === "in") setZoom(zoom * 2);
+      else if (action === "out") setZoom(zoom / 2);
+      else setZoom(minZoom);
+    });
+  });
+
+  viewport.addEventListener("wheel", function (event) {
+    if (!event.ctrlKey && !event.metaKey) return;
+    event.preventDefault();
+    var rect = viewport.getBoundingClientRect();
+    setZoom(event.deltaY < 0 ? zoom * 1.25 : zoom / 1.25, event.clientX - rect.left);
+  }, { passive: false });
+
+  drawAxis();
+})();
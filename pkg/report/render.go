@@ -0,0 +1,50 @@
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+//go:embed templates/report.html.tmpl
+var pageTemplate string
+
+//go:embed assets/report.css
+var pageCSS string
+
+//go:embed assets/report.js
+var pageJS string
+
+//go:embed assets/timeline.js
+var timelineJS string
+
+var pageTmpl = template.Must(template.New(HTMLFileName).Funcs(template.FuncMap{
+	"utc":      func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+	"clock":    func(t time.Time) string { return t.UTC().Format("15:04:05") },
+	"bytes":    runmanifest.FormatBytes,
+	"duration": func(seconds float64) string { return (time.Duration(seconds) * time.Second).String() },
+	"score":    formatScore,
+}).Parse(pageTemplate))
+
+type pageData struct {
+	Report
+	CSS template.CSS
+	JS  template.JS
+}
+
+func renderHTML(rep Report) ([]byte, error) {
+	var buf bytes.Buffer
+	data := pageData{Report: rep, CSS: template.CSS(pageCSS), JS: template.JS(pageJS + "\n" + timelineJS)}
+	if err := pageTmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("render report html: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func formatScore(v float64) string {
+	return fmt.Sprintf("%.0f", v)
+}
@@ -0,0 +1,383 @@
+// Package report renders the self-contained HTML report for a run and the
+// matching report.json. Both are built from the same Report value so the
+// HTML never shows a figure that machine consumers cannot read.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/importer"
+	"github.com/offlinefirst/limitless-context/pkg/privacyscan"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+	"github.com/offlinefirst/limitless-context/pkg/scoring"
+)
+
+// Version is the report.json schema version.
+const Version = 1
+
+// Output file names under the run's report directory.
+const (
+	HTMLFileName = "report.html"
+	JSONFileName = "report.json"
+)
+
+// Options configure report generation.
+type Options struct {
+	Layout   runmanifest.Layout
+	Manifest runmanifest.Manifest
+	// Weights override the scorecard's default metric weights.
+	Weights map[string]int
+	// RedactPatterns are extra privacy scan detectors, normally
+	// capture.events.redact_patterns.
+	RedactPatterns []string
+	Clock          func() time.Time
+}
+
+// Result reports where the report was written.
+type Result struct {
+	HTMLPath    string
+	JSONPath    string
+	PrivacyPath string
+	Report      Report
+}
+
+// Report is the contents of report/report.json and the data behind report.html.
+type Report struct {
+	SchemaVersion     int                           `json:"schema_version"`
+	RunID             string                        `json:"run_id"`
+	GeneratedAt       time.Time                     `json:"generated_at"`
+	Run               RunInfo                       `json:"run"`
+	Scorecard         scoring.Scorecard             `json:"scorecard"`
+	Tasks             []TaskRow                     `json:"tasks"`
+	TokenTotals       runmanifest.BundleSizes       `json:"token_totals"`
+	Import            *ImportInfo                   `json:"import,omitempty"`
+	Storage           []StorageRow                  `json:"storage"`
+	StorageTotal      StorageRow                    `json:"storage_total"`
+	StorageSubsystems []runmanifest.StorageEntry    `json:"storage_subsystems"`
+	StoragePerHour    int64                         `json:"storage_bytes_per_hour,omitempty"`
+	Privacy           privacyscan.Report            `json:"privacy"`
+	Timeline          Timeline                      `json:"timeline"`
+	Controller        []ControllerRow               `json:"controller_timeline"`
+	Subsystems        []runmanifest.SubsystemStatus `json:"subsystems"`
+	Warnings          []string                      `json:"warnings"`
+}
+
+// RunInfo summarises the capture lifecycle from the manifest.
+type RunInfo struct {
+	CreatedAt       time.Time  `json:"created_at"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationSeconds float64    `json:"duration_seconds"`
+	State           string     `json:"state"`
+	Termination     string     `json:"termination,omitempty"`
+	Summary         string     `json:"summary,omitempty"`
+	Hostname        string     `json:"hostname,omitempty"`
+	AppVersion      string     `json:"app_version,omitempty"`
+}
+
+// TaskRow is the token usage and import outcome for one task bundle.
+type TaskRow struct {
+	TaskID        string    `json:"task_id"`
+	Dir           string    `json:"dir"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	Tokenizer     string    `json:"tokenizer"`
+	PromptTokens  int       `json:"prompt_tokens"`
+	ContextTokens int       `json:"context_tokens"`
+	TotalTokens   int       `json:"total_tokens"`
+	TokenBudget   int       `json:"token_budget"`
+	WithinBudget  bool      `json:"within_budget"`
+	Dropped       int       `json:"dropped"`
+	// Import fields are empty until tester process has run.
+	OutputStatus string   `json:"output_status,omitempty"`
+	OutputErrors []string `json:"output_errors,omitempty"`
+	Dangling     []string `json:"dangling,omitempty"`
+	Integrity    string   `json:"integrity,omitempty"`
+	EditedFiles  []string `json:"edited_files,omitempty"`
+}
+
+// ImportInfo carries the import/report.json summary.
+type ImportInfo struct {
+	GeneratedAt      time.Time        `json:"generated_at"`
+	Summary          importer.Summary `json:"summary"`
+	DaySummaryStatus string           `json:"day_summary_status"`
+}
+
+// StorageRow totals files of one artifact kind.
+type StorageRow struct {
+	Kind  string `json:"kind"`
+	Files int    `json:"files"`
+	Bytes int64  `json:"bytes"`
+}
+
+// ControllerRow is one controller transition with its offset from capture start.
+type ControllerRow struct {
+	State     string    `json:"state"`
+	Reason    string    `json:"reason,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Offset    string    `json:"offset"`
+}
+
+// Generate collects the run's manifest, bundle metrics, import results, and
+// privacy scan and writes report/report.html, report/report.json, and
+// report/privacy_scan.json.
+func Generate(opts Options) (Result, error) {
+	rep, err := Collect(opts)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if err := os.MkdirAll(opts.Layout.ReportDir, 0o755); err != nil {
+		return Result{}, fmt.Errorf("ensure report directory: %w", err)
+	}
+	result := Result{
+		HTMLPath:    filepath.Join(opts.Layout.ReportDir, HTMLFileName),
+		JSONPath:    filepath.Join(opts.Layout.ReportDir, JSONFileName),
+		PrivacyPath: filepath.Join(opts.Layout.ReportDir, privacyscan.FileName),
+		Report:      rep,
+	}
+	if err := privacyscan.Save(rep.Privacy, result.PrivacyPath); err != nil {
+		return Result{}, err
+	}
+	page, err := renderHTML(rep)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := os.WriteFile(result.HTMLPath, page, 0o644); err != nil {
+		return Result{}, fmt.Errorf("write report html: %w", err)
+	}
+	data, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return Result{}, fmt.Errorf("marshal report: %w", err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(result.JSONPath, data, 0o644); err != nil {
+		return Result{}, fmt.Errorf("write report json: %w", err)
+	}
+	return result, nil
+}
+
+// Collect builds the report data without writing anything. Missing bundles or
+// import results become warnings rather than errors.
+func Collect(opts Options) (Report, error) {
+	clock := opts.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	now := clock().UTC()
+	layout, man := opts.Layout, opts.Manifest
+	in, err := scoring.Load(layout, man, now)
+	if err != nil {
+		return Report{}, err
+	}
+	card, err := scoring.Score(in, scoring.Options{Weights: opts.Weights})
+	if err != nil {
+		return Report{}, err
+	}
+	rep := Report{
+		SchemaVersion: Version,
+		RunID:         man.RunID,
+		GeneratedAt:   now,
+		Run:           runInfo(man),
+		Scorecard:     card,
+		Tasks:         taskRows(in.Tasks),
+		Controller:    controllerRows(man),
+		Subsystems:    man.Status.Subsystems,
+		Warnings:      []string{},
+	}
+	if rep.Subsystems == nil {
+		rep.Subsystems = []runmanifest.SubsystemStatus{}
+	}
+
+	if len(rep.Tasks) == 0 {
+		rep.Warnings = append(rep.Warnings, "No task bundles found; run tester bundle to populate token usage.")
+	}
+	for _, t := range rep.Tasks {
+		rep.TokenTotals.Prompt += t.PromptTokens
+		rep.TokenTotals.Context += t.ContextTokens
+		rep.TokenTotals.Total += t.TotalTokens
+	}
+
+	rep.Timeline, err = buildTimeline(layout, man, rep.Tasks)
+	if err != nil {
+		return Report{}, err
+	}
+
+	if imported := in.Import; imported != nil {
+		rep.Import = &ImportInfo{GeneratedAt: imported.GeneratedAt, Summary: imported.Summary, DaySummaryStatus: imported.DaySummary.Status}
+		mergeImport(rep.Tasks, *imported)
+		rep.Warnings = append(rep.Warnings, importWarnings(*imported)...)
+	} else {
+		rep.Warnings = append(rep.Warnings, "No import results found; run tester process to validate task outputs.")
+	}
+
+	rep.Storage, rep.StorageTotal = storageRows(in.Storage)
+	rep.StorageSubsystems = in.Storage.Subsystems
+	rep.StoragePerHour = in.Storage.BytesPerHour
+
+	rep.Privacy, err = privacyscan.Scan(privacyscan.Options{Layout: layout, Manifest: man, Patterns: opts.RedactPatterns, Clock: func() time.Time { return now }})
+	if err != nil {
+		return Report{}, err
+	}
+	if !rep.Privacy.Clean() {
+		rep.Warnings = append(rep.Warnings, fmt.Sprintf("The privacy scan found %d unmasked match(es); see %s.", rep.Privacy.Summary.Unmasked, privacyscan.FileName))
+	}
+
+	for _, status := range man.Status.Subsystems {
+		if status.Enabled && (!status.Available || status.State == runmanifest.SubsystemStateErrored) {
+			rep.Warnings = append(rep.Warnings, fmt.Sprintf("Subsystem %s was degraded (%s).", status.Name, strings.Join(nonEmpty(status.State, status.Message), ": ")))
+		}
+	}
+	return rep, nil
+}
+
+func runInfo(man runmanifest.Manifest) RunInfo {
+	info := RunInfo{
+		CreatedAt:   man.CreatedAt,
+		StartedAt:   man.Status.StartedAt,
+		EndedAt:     man.Status.EndedAt,
+		State:       man.Status.State,
+		Termination: man.Status.Termination,
+		Summary:     man.Status.Summary,
+		Hostname:    man.Hostname,
+		AppVersion:  man.AppVersion,
+	}
+	if info.StartedAt != nil && info.EndedAt != nil {
+		info.DurationSeconds = info.EndedAt.Sub(*info.StartedAt).Seconds()
+	}
+	return info
+}
+
+func taskRows(tasks []runmanifest.BundleMetrics) []TaskRow {
+	rows := make([]TaskRow, 0, len(tasks))
+	for _, metrics := range tasks {
+		rows = append(rows, TaskRow{
+			TaskID:        metrics.TaskID,
+			Dir:           "../bundles/" + metrics.TaskID + "/",
+			Start:         metrics.Start,
+			End:           metrics.End,
+			Tokenizer:     metrics.Tokenizer,
+			PromptTokens:  metrics.Tokens.Prompt,
+			ContextTokens: metrics.Tokens.Context,
+			TotalTokens:   metrics.Tokens.Total,
+			TokenBudget:   metrics.TokenBudget,
+			WithinBudget:  metrics.WithinBudget,
+			Dropped:       len(metrics.Dropped),
+		})
+	}
+	return rows
+}
+
+func mergeImport(rows []TaskRow, imported importer.Report) {
+	byID := make(map[string]importer.Result, len(imported.Tasks))
+	for _, result := range imported.Tasks {
+		byID[result.TaskID] = result
+	}
+	for i := range rows {
+		result, ok := byID[rows[i].TaskID]
+		if !ok {
+			continue
+		}
+		rows[i].OutputStatus = result.Status
+		rows[i].OutputErrors = result.Errors
+		if result.Evidence != nil {
+			rows[i].Dangling = result.Evidence.Dangling
+		}
+		if result.Integrity != nil {
+			rows[i].Integrity = result.Integrity.Status
+			for _, mismatch := range result.Integrity.Mismatches {
+				rows[i].EditedFiles = append(rows[i].EditedFiles, mismatch.File)
+			}
+		}
+	}
+}
+
+func importWarnings(imported importer.Report) []string {
+	var warnings []string
+	for _, result := range imported.Tasks {
+		if result.Integrity != nil && result.Integrity.Modified() {
+			files := make([]string, 0, len(result.Integrity.Mismatches))
+			for _, mismatch := range result.Integrity.Mismatches {
+				files = append(files, mismatch.File)
+			}
+			warnings = append(warnings, fmt.Sprintf("%s was edited after bundling (%s); its output is not comparable.", result.TaskID, strings.Join(files, ", ")))
+		}
+	}
+	if imported.DaySummary.Status == importer.StatusMissing {
+		warnings = append(warnings, "The day summary output is missing; the report covers task outputs only.")
+	}
+	return warnings
+}
+
+func controllerRows(man runmanifest.Manifest) []ControllerRow {
+	base := man.CaptureStart()
+	rows := make([]ControllerRow, 0, len(man.Status.Controller))
+	for _, entry := range man.Status.Controller {
+		rows = append(rows, ControllerRow{
+			State:     entry.State,
+			Reason:    entry.Reason,
+			Timestamp: entry.Timestamp,
+			Offset:    formatOffset(entry.Timestamp.Sub(base)),
+		})
+	}
+	return rows
+}
+
+// storageKinds maps file extensions to the storage table's artifact kinds.
+var storageKinds = map[string]string{
+	".mp4":   "MP4",
+	".avi":   "AVI",
+	".png":   "PNG",
+	".json":  "JSON",
+	".jsonl": "JSON",
+	".vtt":   "VTT",
+}
+
+var storageOrder = []string{"MP4", "AVI", "PNG", "JSON", "VTT", "Other"}
+
+// storageRows groups the measured extensions into the storage table's
+// artifact kinds.
+func storageRows(storage runmanifest.Storage) ([]StorageRow, StorageRow) {
+	totals := make(map[string]*StorageRow, len(storageOrder))
+	for _, kind := range storageOrder {
+		totals[kind] = &StorageRow{Kind: kind}
+	}
+	for _, ext := range storage.Extensions {
+		kind, ok := storageKinds[ext.Name]
+		if !ok {
+			kind = "Other"
+		}
+		totals[kind].Files += ext.Files
+		totals[kind].Bytes += ext.Bytes
+	}
+
+	rows := make([]StorageRow, 0, len(storageOrder))
+	for _, kind := range storageOrder {
+		rows = append(rows, *totals[kind])
+	}
+	return rows, StorageRow{Kind: "Total", Files: storage.Total.Files, Bytes: storage.Total.Bytes}
+}
+
+func formatOffset(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	total := int(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%s%02d:%02d", sign, total/60, total%60)
+}
+
+func nonEmpty(values ...string) []string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
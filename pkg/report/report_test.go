@@ -0,0 +1,187 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/importer"
+	"github.com/offlinefirst/limitless-context/pkg/privacyscan"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+var reportBase = time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+
+func writeReportRun(t *testing.T) (runmanifest.Layout, runmanifest.Manifest) {
+	t.Helper()
+	layout := runmanifest.BuildLayout(t.TempDir(), "20240512_093000")
+	if err := runmanifest.EnsureFilesystem(layout); err != nil {
+		t.Fatalf("ensure filesystem: %v", err)
+	}
+	started, ended := reportBase, reportBase.Add(3*time.Minute)
+	man := runmanifest.Manifest{
+		RunID:     "20240512_093000",
+		CreatedAt: reportBase,
+		Capture:   runmanifest.CaptureSettings{VideoEnabled: true, ScreenshotsEnabled: true, EventsEnabled: true, OCREnabled: true},
+		Status: runmanifest.Status{
+			State:     "completed",
+			StartedAt: &started,
+			EndedAt:   &ended,
+			Controller: []runmanifest.ControllerTimelineEntry{
+				{State: "running", Timestamp: reportBase},
+				{State: "paused", Reason: "operator", Timestamp: reportBase.Add(65 * time.Second)},
+				{State: "stopping", Reason: "duration elapsed", Timestamp: ended},
+			},
+			Subsystems: []runmanifest.SubsystemStatus{
+				{Name: "video", Enabled: true, Available: true, State: runmanifest.SubsystemStateCompleted, Provider: "synthetic"},
+				{Name: "ocr", Enabled: true, Available: false, State: runmanifest.SubsystemStateUnavailable, Message: "tesseract not installed"},
+			},
+		},
+	}
+
+	for i, id := range []string{"task_001", "task_002"} {
+		dir := filepath.Join(layout.BundlesDir, id)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		metrics := runmanifest.BundleMetrics{
+			TaskID:       id,
+			Start:        reportBase.Add(time.Duration(i) * time.Minute),
+			End:          reportBase.Add(time.Duration(i)*time.Minute + 30*time.Second),
+			Tokenizer:    "cl100k",
+			Tokens:       runmanifest.BundleSizes{Prompt: 100, Context: 400 + i, Total: 500 + i},
+			TokenBudget:  5000,
+			WithinBudget: true,
+		}
+		if err := runmanifest.SaveBundleMetrics(metrics, filepath.Join(dir, "metrics.json")); err != nil {
+			t.Fatalf("save metrics: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(layout.VideoDir, "capture.mp4"), make([]byte, 2048), 0o644); err != nil {
+		t.Fatalf("write video: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(layout.VideoDir, "segment_0001.avi"), make([]byte, 1024), 0o644); err != nil {
+		t.Fatalf("write synthetic video: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(layout.EventsDir, "events_fine.jsonl"), []byte(`{"metadata":{"title":"Mail [REDACTED]"}}`+"\n"), 0o644); err != nil {
+		t.Fatalf("write events: %v", err)
+	}
+	return layout, man
+}
+
+func TestGenerateWritesSelfContainedReport(t *testing.T) {
+	layout, man := writeReportRun(t)
+	imported := importer.Report{
+		SchemaVersion: importer.ReportVersion,
+		RunID:         man.RunID,
+		Summary:       importer.Summary{Tasks: 2, Valid: 1, Invalid: 1, Modified: 1},
+		Tasks: []importer.Result{
+			{TaskID: "task_001", Status: importer.StatusValid, Evidence: &importer.Evidence{Cited: 2, Resolved: 1, Dangling: []string{"event:evt_0099"}}, Integrity: &importer.Integrity{Status: importer.IntegrityVerified}},
+			{TaskID: "task_002", Status: importer.StatusInvalid, Errors: []string{"invalid JSON: unexpected end of JSON input"}, Integrity: &importer.Integrity{Status: importer.IntegrityModified, Mismatches: []importer.ChecksumMismatch{{File: "context.md"}}}},
+		},
+		DaySummary: importer.Result{TaskID: importer.DaySummaryID, Status: importer.StatusMissing},
+	}
+	if err := importer.Save(imported, filepath.Join(layout.ImportDir, importer.ReportFileName)); err != nil {
+		t.Fatalf("save import report: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(layout.ASRDir, "meeting_0001.vtt"), []byte("WEBVTT\n\n00:00:01.000 --> 00:00:03.000\nmail dana@corp.org\n"), 0o644); err != nil {
+		t.Fatalf("write transcript: %v", err)
+	}
+
+	now := reportBase.Add(time.Hour)
+	result, err := Generate(Options{Layout: layout, Manifest: man, Clock: func() time.Time { return now }})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	page, err := os.ReadFile(result.HTMLPath)
+	if err != nil {
+		t.Fatalf("read html: %v", err)
+	}
+	html := string(page)
+	for _, want := range []string{
+		"<style>", "<script>",
+		"Fidelity<br><span class=\"muted\">30%</span>",
+		"PrivacyExposure<br><span class=\"muted\">5%</span>",
+		"Hybrid <span class=\"status-unavailable\">(degraded)</span>",
+		`<a href="../bundles/task_001/">task_001</a>`,
+		"dangling evidence: event:evt_0099",
+		"edited: context.md",
+		"task_002 was edited after bundling (context.md)",
+		"<td>01:05</td>", "duration elapsed",
+		"tesseract not installed",
+		"<td>MP4</td><td class=\"num\">1</td><td class=\"num\" data-value=\"2048\">2.0 KiB</td>",
+		"<td>AVI</td><td class=\"num\">1</td><td class=\"num\" data-value=\"1024\">1.0 KiB</td>",
+		"<tr><td>video</td><td class=\"num\">2</td><td class=\"num\" data-value=\"3072\">3.0 KiB</td></tr>",
+		"A one-hour run at this rate would use about",
+		"How the scores were derived",
+		"ceiling 90 x 65% of signal weight captured (ocr unavailable, asr disabled)",
+		`<script type="application/json" id="timeline-data">`, `data-zoom="in"`,
+		`<span class="status-error">1 unmasked match(es)</span>, 1 redaction mask(s).`,
+		`<tr><td><a href="../asr/meeting_0001.vtt">asr/meeting_0001.vtt</a></td><td class="num">4</td><td>email</td><td>da…rg (13 chars)</td></tr>`,
+		"The privacy scan found 1 unmasked match(es); see privacy_scan.json.",
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("report.html missing %q", want)
+		}
+	}
+	for _, external := range []string{"<link", "src=", "http://", "https://"} {
+		if strings.Contains(html, external) {
+			t.Fatalf("report.html must not reference external assets (%q)", external)
+		}
+	}
+
+	data, err := os.ReadFile(result.JSONPath)
+	if err != nil {
+		t.Fatalf("read json: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if decoded.SchemaVersion != Version || !decoded.GeneratedAt.Equal(now) || len(decoded.Tasks) != 2 {
+		t.Fatalf("unexpected report.json header: %+v", decoded)
+	}
+	if decoded.TokenTotals.Total != 1001 || decoded.Tasks[1].Integrity != importer.IntegrityModified || decoded.Import.DaySummaryStatus != importer.StatusMissing {
+		t.Fatalf("unexpected report.json contents: %+v", decoded)
+	}
+	hybrid := decoded.Scorecard.Modes[1]
+	if hybrid.Mode != "Hybrid" || hybrid.Total == nil || len(hybrid.Scores) != len(decoded.Scorecard.Metrics) || hybrid.Scores[0].Explanation == "" {
+		t.Fatalf("unexpected hybrid scorecard row: %+v", hybrid)
+	}
+	scan, err := privacyscan.Load(result.PrivacyPath)
+	if err != nil {
+		t.Fatalf("load privacy scan: %v", err)
+	}
+	if scan.Summary != decoded.Privacy.Summary || len(scan.Violations) != 1 {
+		t.Fatalf("privacy_scan.json disagrees with report.json: %+v", scan)
+	}
+	if decoded.Run.DurationSeconds != 180 || len(decoded.Controller) != 3 || decoded.Controller[2].Offset != "03:00" {
+		t.Fatalf("unexpected run timing: %+v %+v", decoded.Run, decoded.Controller)
+	}
+}
+
+func TestCollectWarnsWithoutBundlesOrImport(t *testing.T) {
+	layout := runmanifest.BuildLayout(t.TempDir(), "run")
+	rep, err := Collect(Options{Layout: layout, Manifest: runmanifest.Manifest{RunID: "run", CreatedAt: reportBase}})
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	joined := strings.Join(rep.Warnings, "\n")
+	if !strings.Contains(joined, "tester bundle") || !strings.Contains(joined, "tester process") {
+		t.Fatalf("expected guidance warnings, got %v", rep.Warnings)
+	}
+	if len(rep.Scorecard.Metrics) != 8 || len(rep.Scorecard.Modes) != 3 || rep.StorageTotal.Files != 0 {
+		t.Fatalf("unexpected empty report: %+v", rep)
+	}
+	weights := 0
+	for _, metric := range rep.Scorecard.Metrics {
+		weights += metric.Weight
+	}
+	if weights != 100 {
+		t.Fatalf("default weights sum to %d", weights)
+	}
+}
@@ -0,0 +1,133 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/asr"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+	"github.com/offlinefirst/limitless-context/pkg/screenshots"
+	"github.com/offlinefirst/limitless-context/pkg/sessionize"
+)
+
+// Timeline lanes, in display order.
+const (
+	LaneSessions    = "sessions"
+	LaneTasks       = "tasks"
+	LaneController  = "controller"
+	LaneScreenshots = "screenshots"
+	LaneASR         = "asr"
+)
+
+// maxCueLabel caps the transcript text shown on an ASR cue.
+const maxCueLabel = 80
+
+// Timeline is every plotted item on one time axis. The HTML report embeds it
+// as JSON for the timeline script.
+type Timeline struct {
+	Start time.Time      `json:"start"`
+	End   time.Time      `json:"end"`
+	Lanes []string       `json:"lanes"`
+	Items []TimelineItem `json:"items"`
+}
+
+// TimelineItem is a span, or an instant when End is nil. Href is relative to
+// the report directory.
+type TimelineItem struct {
+	Lane  string     `json:"lane"`
+	Label string     `json:"label"`
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+	Href  string     `json:"href,omitempty"`
+}
+
+// buildTimeline plots sessions from events/sessions.json, task bundles,
+// controller transitions, screenshot captures, and ASR cues. Artifacts that
+// were never written leave their lane empty.
+func buildTimeline(layout runmanifest.Layout, man runmanifest.Manifest, tasks []TaskRow) (Timeline, error) {
+	tl := Timeline{Lanes: []string{LaneSessions, LaneTasks, LaneController, LaneScreenshots, LaneASR}, Items: []TimelineItem{}}
+	span := func(lane, label string, start, end time.Time, href string) {
+		start, end = start.UTC(), end.UTC()
+		tl.Items = append(tl.Items, TimelineItem{Lane: lane, Label: label, Start: start, End: &end, Href: href})
+	}
+	instant := func(lane, label string, at time.Time, href string) {
+		tl.Items = append(tl.Items, TimelineItem{Lane: lane, Label: label, Start: at.UTC(), Href: href})
+	}
+
+	sessions, err := sessionize.Load(filepath.Join(layout.EventsDir, sessionize.FileName))
+	switch {
+	case err == nil:
+		for _, session := range sessions.Sessions {
+			span(LaneSessions, fmt.Sprintf("%s (%d events)", session.ID, len(session.EventIDs)), session.Start, session.End, "../events/"+sessionize.FileName)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return Timeline{}, err
+	}
+
+	for _, task := range tasks {
+		span(LaneTasks, task.TaskID, task.Start, task.End, task.Dir)
+	}
+
+	for _, entry := range man.Status.Controller {
+		instant(LaneController, strings.Join(nonEmpty(entry.State, entry.Reason), ": "), entry.Timestamp, "../"+filepath.Base(layout.CaptureLogPath))
+	}
+
+	shots, err := screenshots.ReadDir(layout.ScreensDir)
+	if err != nil {
+		return Timeline{}, err
+	}
+	for _, shot := range shots {
+		image := shot.Metadata.ImagePath
+		if image == "" {
+			image = shot.Name + ".png"
+		}
+		instant(LaneScreenshots, shot.Name, shot.Metadata.CapturedAt, "../screenshots/"+filepath.Base(image))
+	}
+
+	transcripts, err := filepath.Glob(filepath.Join(layout.ASRDir, "*.vtt"))
+	if err != nil {
+		return Timeline{}, fmt.Errorf("list transcripts: %w", err)
+	}
+	sort.Strings(transcripts)
+	base := man.CaptureStart()
+	for _, path := range transcripts {
+		cues, err := asr.ReadVTT(path)
+		if err != nil {
+			return Timeline{}, fmt.Errorf("transcript %s: %w", filepath.Base(path), err)
+		}
+		for _, cue := range cues {
+			span(LaneASR, cueLabel(cue.Text), base.Add(cue.Start), base.Add(cue.End), "../asr/"+filepath.Base(path))
+		}
+	}
+
+	tl.Start, tl.End = base, base
+	if man.Status.EndedAt != nil {
+		tl.End = man.Status.EndedAt.UTC()
+	}
+	for _, item := range tl.Items {
+		if item.Start.Before(tl.Start) {
+			tl.Start = item.Start
+		}
+		end := item.Start
+		if item.End != nil {
+			end = *item.End
+		}
+		if end.After(tl.End) {
+			tl.End = end
+		}
+	}
+	return tl, nil
+}
+
+func cueLabel(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > maxCueLabel {
+		return string(runes[:maxCueLabel-1]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/sessionize"
+)
+
+func TestBuildTimelinePlotsEachLane(t *testing.T) {
+	layout, man := writeReportRun(t)
+	sessions := sessionize.File{Sessions: []sessionize.Session{
+		{ID: "session_001", Start: reportBase.Add(5 * time.Second), End: reportBase.Add(50 * time.Second), EventIDs: []string{"evt_0001", "evt_0002"}},
+	}}
+	data, err := json.Marshal(sessions)
+	if err != nil {
+		t.Fatalf("marshal sessions: %v", err)
+	}
+	files := map[string]string{
+		filepath.Join(layout.EventsDir, sessionize.FileName):     string(data),
+		filepath.Join(layout.ScreensDir, "screenshot_0001.png"):  "png",
+		filepath.Join(layout.ScreensDir, "screenshot_0001.json"): `{"captured_at":"2024-05-12T09:30:15Z"}`,
+		filepath.Join(layout.ASRDir, "meeting_0001.vtt"):         "WEBVTT\n\n1\n00:00:10.000 --> 00:00:12.500\nLet's  ship\nit.\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	rep, err := Collect(Options{Layout: layout, Manifest: man})
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	tl := rep.Timeline
+	if !tl.Start.Equal(reportBase) || !tl.End.Equal(reportBase.Add(3*time.Minute)) || len(tl.Lanes) != 5 {
+		t.Fatalf("unexpected timeline bounds: %+v", tl)
+	}
+	byLane := map[string][]TimelineItem{}
+	for _, item := range tl.Items {
+		byLane[item.Lane] = append(byLane[item.Lane], item)
+	}
+	if got := byLane[LaneSessions]; len(got) != 1 || got[0].Label != "session_001 (2 events)" || got[0].Href != "../events/sessions.json" || !got[0].End.Equal(reportBase.Add(50*time.Second)) {
+		t.Fatalf("unexpected sessions lane: %+v", got)
+	}
+	if got := byLane[LaneTasks]; len(got) != 2 || got[1].Label != "task_002" || got[1].Href != "../bundles/task_002/" {
+		t.Fatalf("unexpected tasks lane: %+v", got)
+	}
+	if got := byLane[LaneController]; len(got) != 3 || got[1].Label != "paused: operator" || got[1].End != nil || got[1].Href != "../capture.log" {
+		t.Fatalf("unexpected controller lane: %+v", got)
+	}
+	if got := byLane[LaneScreenshots]; len(got) != 1 || got[0].Href != "../screenshots/screenshot_0001.png" || !got[0].Start.Equal(reportBase.Add(15*time.Second)) {
+		t.Fatalf("unexpected screenshots lane: %+v", got)
+	}
+	cue := byLane[LaneASR]
+	if len(cue) != 1 || cue[0].Label != "Let's ship it." || cue[0].Href != "../asr/meeting_0001.vtt" || !cue[0].Start.Equal(reportBase.Add(10*time.Second)) || !cue[0].End.Equal(reportBase.Add(12500*time.Millisecond)) {
+		t.Fatalf("unexpected asr lane: %+v", cue)
+	}
+}
+
+func TestCueLabelTruncates(t *testing.T) {
+	label := cueLabel(strings.Repeat("a", 200))
+	if runes := []rune(label); len(runes) != maxCueLabel || runes[maxCueLabel-1] != '…' {
+		t.Fatalf("unexpected label %q", label)
+	}
+}
@@ -0,0 +1,119 @@
+package runmanifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// BundleMetricsVersion is the metrics.json schema version written by the bundler.
+const BundleMetricsVersion = 1
+
+// BundleMetrics is the machine-readable summary written to
+// bundles/task_NNN/metrics.json and read back by report and process.
+type BundleMetrics struct {
+	SchemaVersion int       `json:"schema_version"`
+	TaskID        string    `json:"task_id"`
+	RunID         string    `json:"run_id"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+
+	PromptTemplate BundleTemplate   `json:"prompt_template"`
+	Items          BundleItemCounts `json:"items"`
+	Characters     BundleSizes      `json:"characters"`
+
+	// Tokenizer names the encoder used for Tokens, SectionTokens, and the budget check.
+	Tokenizer     string              `json:"tokenizer"`
+	Tokens        BundleSizes         `json:"tokens"`
+	SectionTokens BundleSectionTokens `json:"section_tokens"`
+	// TokensByTokenizer counts the bundle with every built-in tokenizer.
+	TokensByTokenizer map[string]BundleSizes `json:"tokens_by_tokenizer"`
+
+	TokenBudget  int  `json:"token_budget"`
+	WithinBudget bool `json:"within_budget"`
+	// UntrimmedContextTokens is the context size before lower-priority items were dropped.
+	UntrimmedContextTokens int                 `json:"untrimmed_context_tokens"`
+	Dropped                []BundleDroppedItem `json:"dropped"`
+
+	Checksums BundleChecksums `json:"checksums"`
+}
+
+// BundleTemplate identifies the prompt template a bundle was rendered from.
+type BundleTemplate struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	SHA256 string `json:"sha256"`
+}
+
+// BundleItemCounts records how many items of each kind reached context.md.
+type BundleItemCounts struct {
+	Events      int `json:"events"`
+	Screenshots int `json:"screenshots"`
+	OCR         int `json:"ocr"`
+	ASR         int `json:"asr"`
+}
+
+// BundleSizes pairs prompt and context measurements.
+type BundleSizes struct {
+	Prompt  int `json:"prompt"`
+	Context int `json:"context"`
+	Total   int `json:"total"`
+}
+
+// BundleSectionTokens breaks the context token count down by section.
+type BundleSectionTokens struct {
+	Header int `json:"header"`
+	Events int `json:"events"`
+	OCR    int `json:"ocr"`
+	ASR    int `json:"asr"`
+}
+
+// BundleDroppedItem records a context item trimmed to meet the token budget.
+type BundleDroppedItem struct {
+	Kind   string `json:"kind"`
+	Ref    string `json:"ref"`
+	Tokens int    `json:"tokens"`
+	Reason string `json:"reason"`
+}
+
+// BundleChecksums holds SHA-256 digests of the files handed to the model and
+// of the schema its output is validated against.
+type BundleChecksums struct {
+	PromptSHA256  string `json:"prompt_sha256"`
+	ContextSHA256 string `json:"context_sha256"`
+	SchemaSHA256  string `json:"schema_sha256"`
+}
+
+// SaveBundleMetrics writes metrics JSON with indentation, stamping the current version.
+func SaveBundleMetrics(metrics BundleMetrics, path string) error {
+	metrics.SchemaVersion = BundleMetricsVersion
+	if metrics.Dropped == nil {
+		metrics.Dropped = []BundleDroppedItem{}
+	}
+	data, err := json.MarshalIndent(metrics, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal bundle metrics: %w", err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write bundle metrics: %w", err)
+	}
+	return nil
+}
+
+// LoadBundleMetrics reads a metrics.json file, rejecting unknown schema versions.
+func LoadBundleMetrics(path string) (BundleMetrics, error) {
+	var metrics BundleMetrics
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return metrics, fmt.Errorf("read bundle metrics: %w", err)
+	}
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		return metrics, fmt.Errorf("decode bundle metrics: %w", err)
+	}
+	if metrics.SchemaVersion != BundleMetricsVersion {
+		return metrics, fmt.Errorf("bundle metrics schema version %d is unsupported (expected %d)", metrics.SchemaVersion, BundleMetricsVersion)
+	}
+	return metrics, nil
+}
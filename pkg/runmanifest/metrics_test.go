@@ -0,0 +1,60 @@
+package runmanifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBundleMetricsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	start := time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+	metrics := BundleMetrics{
+		TaskID:            "task_001",
+		RunID:             "20240512_093000",
+		Start:             start,
+		End:               start.Add(time.Minute),
+		PromptTemplate:    BundleTemplate{Name: "task", Source: "embedded", SHA256: strings.Repeat("a", 64)},
+		Items:             BundleItemCounts{Events: 3, Screenshots: 1, OCR: 1},
+		Tokenizer:         "cl100k",
+		Tokens:            BundleSizes{Prompt: 120, Context: 300, Total: 420},
+		SectionTokens:     BundleSectionTokens{Header: 40, Events: 200, OCR: 50, ASR: 10},
+		TokensByTokenizer: map[string]BundleSizes{"cl100k": {Prompt: 120, Context: 300, Total: 420}},
+		TokenBudget:       5000,
+		WithinBudget:      true,
+		Checksums:         BundleChecksums{PromptSHA256: "p", ContextSHA256: "c", SchemaSHA256: "s"},
+	}
+	if err := SaveBundleMetrics(metrics, path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(data), `"schema_version": 1`) || !strings.Contains(string(data), `"dropped": []`) {
+		t.Fatalf("expected stamped version and empty dropped list:\n%s", data)
+	}
+
+	loaded, err := LoadBundleMetrics(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	metrics.SchemaVersion = BundleMetricsVersion
+	metrics.Dropped = []BundleDroppedItem{}
+	if !reflect.DeepEqual(loaded, metrics) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", loaded, metrics)
+	}
+}
+
+func TestLoadBundleMetricsRejectsUnknownVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	if err := os.WriteFile(path, []byte(`{"schema_version": 99, "task_id": "task_001"}`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := LoadBundleMetrics(path); err == nil || !strings.Contains(err.Error(), "99") {
+		t.Fatalf("expected version error, got %v", err)
+	}
+}
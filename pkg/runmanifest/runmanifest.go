@@ -26,6 +26,7 @@ type Layout struct {
 	ASRDir         string
 	OCRDir         string
 	BundlesDir     string
+	ImportDir      string
 	ReportDir      string
 }
 
@@ -40,6 +41,7 @@ type Paths struct {
 	ASR         string `json:"asr"`
 	OCR         string `json:"ocr"`
 	Bundles     string `json:"bundles"`
+	Import      string `json:"import"`
 	Report      string `json:"report"`
 }
 
@@ -102,6 +104,8 @@ type Manifest struct {
 	Capture       CaptureSettings `json:"capture"`
 	Paths         Paths           `json:"paths"`
 	Status        Status          `json:"status"`
+	// Storage is the footprint measured when capture finished.
+	Storage *Storage `json:"storage,omitempty"`
 }
 
 // Options captures the knobs for creating a new manifest.
@@ -149,10 +153,32 @@ func BuildLayout(runsDir, runID string) Layout {
 		ASRDir:         filepath.Join(root, "asr"),
 		OCRDir:         filepath.Join(root, "ocr"),
 		BundlesDir:     filepath.Join(root, "bundles"),
+		ImportDir:      filepath.Join(root, "import"),
 		ReportDir:      filepath.Join(root, "report"),
 	}
 }
 
+// CaptureStart is the reference instant for relative offsets such as
+// shot:mm:ss: the recorded start of capture, or the creation time when the
+// run never started.
+func (m Manifest) CaptureStart() time.Time {
+	if m.Status.StartedAt != nil && !m.Status.StartedAt.IsZero() {
+		return m.Status.StartedAt.UTC()
+	}
+	return m.CreatedAt.UTC()
+}
+
+// CaptureDuration is the recorded capture span, falling back to the
+// configured duration when the run lacks start or end timestamps.
+func (m Manifest) CaptureDuration() time.Duration {
+	if m.Status.StartedAt != nil && m.Status.EndedAt != nil {
+		if span := m.Status.EndedAt.Sub(*m.Status.StartedAt); span > 0 {
+			return span
+		}
+	}
+	return time.Duration(m.Capture.DurationMinutes) * time.Minute
+}
+
 // RelativePaths exposes the manifest-friendly relative paths for the layout.
 func (l Layout) RelativePaths() Paths {
 	return Paths{
@@ -165,6 +191,7 @@ func (l Layout) RelativePaths() Paths {
 		ASR:         filepath.Base(l.ASRDir),
 		OCR:         filepath.Base(l.OCRDir),
 		Bundles:     filepath.Base(l.BundlesDir),
+		Import:      filepath.Base(l.ImportDir),
 		Report:      filepath.Base(l.ReportDir),
 	}
 }
@@ -182,6 +209,7 @@ func EnsureFilesystem(layout Layout) error {
 		layout.ASRDir,
 		layout.OCRDir,
 		layout.BundlesDir,
+		layout.ImportDir,
 		layout.ReportDir,
 	}
 
@@ -249,3 +277,22 @@ func ResolveRunID(runsDir string, now time.Time) (string, error) {
 		}
 	}
 }
+
+// LatestRunID returns the newest run under runsDir that has a manifest. Run
+// identifiers are timestamps, so the newest sorts last.
+func LatestRunID(runsDir string) (string, error) {
+	entries, err := os.ReadDir(runsDir)
+	if err != nil {
+		return "", fmt.Errorf("list runs directory: %w", err)
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(BuildLayout(runsDir, entry.Name()).ManifestPath); err == nil {
+			return entry.Name(), nil
+		}
+	}
+	return "", fmt.Errorf("no runs found under %s", runsDir)
+}
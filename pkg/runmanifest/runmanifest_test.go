@@ -45,6 +45,7 @@ func TestEnsureFilesystemCreatesDirectories(t *testing.T) {
 		layout.ASRDir,
 		layout.OCRDir,
 		layout.BundlesDir,
+		layout.ImportDir,
 		layout.ReportDir,
 	}
 
@@ -173,3 +174,28 @@ func TestResolveRunIDEmptyRunsDir(t *testing.T) {
 		t.Fatalf("expected error for empty runs dir")
 	}
 }
+
+func TestLatestRunID(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LatestRunID(dir); err == nil {
+		t.Fatalf("expected error when no runs exist")
+	}
+	for _, id := range []string{"20240512_093000", "20240512_093000_01", "20240513_080000"} {
+		layout := BuildLayout(dir, id)
+		if err := os.MkdirAll(layout.Root, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if id != "20240513_080000" {
+			if err := os.WriteFile(layout.ManifestPath, []byte("{}"), 0o644); err != nil {
+				t.Fatalf("write manifest: %v", err)
+			}
+		}
+	}
+	id, err := LatestRunID(dir)
+	if err != nil {
+		t.Fatalf("LatestRunID failed: %v", err)
+	}
+	if id != "20240512_093000_01" {
+		t.Fatalf("expected the newest run with a manifest, got %s", id)
+	}
+}
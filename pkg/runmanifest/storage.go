@@ -0,0 +1,156 @@
+package runmanifest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RootFilesName groups files stored directly under the run root, such as
+// manifest.json and capture.log.
+const RootFilesName = "run"
+
+// Storage is the disk footprint of a run, recorded in the manifest.
+type Storage struct {
+	MeasuredAt time.Time    `json:"measured_at"`
+	Total      StorageEntry `json:"total"`
+	// CaptureSeconds is the capture span BytesPerHour was projected from.
+	CaptureSeconds float64 `json:"capture_seconds,omitempty"`
+	// BytesPerHour projects Total to a one-hour run; zero when the capture
+	// span is unknown.
+	BytesPerHour int64 `json:"bytes_per_hour,omitempty"`
+	// Subsystems is keyed by top-level directory, in layout order.
+	Subsystems []StorageEntry `json:"subsystems"`
+	// Extensions is keyed by lower-case file extension ("" for none).
+	Extensions []StorageEntry `json:"extensions"`
+}
+
+// StorageEntry totals the files in one group.
+type StorageEntry struct {
+	Name  string `json:"name"`
+	Files int    `json:"files"`
+	Bytes int64  `json:"bytes"`
+}
+
+// Subsystem returns the entry for a top-level directory, or a zero entry.
+func (s Storage) Subsystem(name string) StorageEntry {
+	for _, entry := range s.Subsystems {
+		if entry.Name == name {
+			return entry
+		}
+	}
+	return StorageEntry{Name: name}
+}
+
+// MeasureStorage walks the run root and sums bytes and file counts by
+// subsystem directory and extension. The report directory is skipped since
+// it is regenerated from everything else, and only regular files count, so a
+// live run's control socket is ignored.
+func MeasureStorage(layout Layout, man Manifest, now time.Time) (Storage, error) {
+	subsystems := make(map[string]*StorageEntry)
+	extensions := make(map[string]*StorageEntry)
+	add := func(groups map[string]*StorageEntry, name string, size int64) {
+		entry, ok := groups[name]
+		if !ok {
+			entry = &StorageEntry{Name: name}
+			groups[name] = entry
+		}
+		entry.Files++
+		entry.Bytes += size
+	}
+
+	out := Storage{MeasuredAt: now.UTC(), Total: StorageEntry{Name: "total"}}
+	err := filepath.WalkDir(layout.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == layout.ReportDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(layout.Root, path)
+		if err != nil {
+			return err
+		}
+		group := RootFilesName
+		if parts := strings.SplitN(filepath.ToSlash(rel), "/", 2); len(parts) == 2 {
+			group = parts[0]
+		}
+		add(subsystems, group, info.Size())
+		add(extensions, strings.ToLower(filepath.Ext(path)), info.Size())
+		out.Total.Files++
+		out.Total.Bytes += info.Size()
+		return nil
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Storage{}, fmt.Errorf("measure storage: %w", err)
+	}
+
+	order := make(map[string]int)
+	for i, dir := range []string{layout.VideoDir, layout.ScreensDir, layout.EventsDir, layout.ASRDir, layout.OCRDir, layout.BundlesDir, layout.ImportDir} {
+		order[filepath.Base(dir)] = i + 1
+	}
+	out.Subsystems = sortedEntries(subsystems, func(a, b StorageEntry) bool {
+		ra, rb := rank(order, a.Name), rank(order, b.Name)
+		if ra != rb {
+			return ra < rb
+		}
+		return a.Name < b.Name
+	})
+	out.Extensions = sortedEntries(extensions, func(a, b StorageEntry) bool { return a.Name < b.Name })
+
+	if captured := man.CaptureDuration(); captured > 0 {
+		out.CaptureSeconds = captured.Seconds()
+		out.BytesPerHour = int64(float64(out.Total.Bytes) * float64(time.Hour) / float64(captured))
+	}
+	return out, nil
+}
+
+// rank places known subsystem directories first, then other directories,
+// then files kept at the run root.
+func rank(order map[string]int, name string) int {
+	if i, ok := order[name]; ok {
+		return i
+	}
+	if name == RootFilesName {
+		return len(order) + 2
+	}
+	return len(order) + 1
+}
+
+func sortedEntries(groups map[string]*StorageEntry, less func(a, b StorageEntry) bool) []StorageEntry {
+	out := make([]StorageEntry, 0, len(groups))
+	for _, entry := range groups {
+		out = append(out, *entry)
+	}
+	sort.Slice(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return out
+}
+
+// FormatBytes renders a byte count with binary units, e.g. "1.5 MiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, exp := float64(n)/unit, 0
+	for value >= unit && exp < 3 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGT"[exp])
+}
@@ -0,0 +1,84 @@
+package runmanifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMeasureStorageGroupsBySubsystemAndExtension(t *testing.T) {
+	layout := BuildLayout(t.TempDir(), "run")
+	if err := EnsureFilesystem(layout); err != nil {
+		t.Fatalf("ensure filesystem: %v", err)
+	}
+	files := map[string]int{
+		filepath.Join(layout.VideoDir, "segment_0001.mp4"):       3000,
+		filepath.Join(layout.ScreensDir, "screenshot_0001.PNG"):  200,
+		filepath.Join(layout.ScreensDir, "screenshot_0001.json"): 50,
+		filepath.Join(layout.EventsDir, "events_fine.jsonl"):     70,
+		filepath.Join(layout.Root, "manifest.json"):              40,
+		filepath.Join(layout.ReportDir, "report.html"):           9999,
+	}
+	for path, size := range files {
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	started := time.Date(2024, 5, 12, 9, 0, 0, 0, time.UTC)
+	ended := started.Add(15 * time.Minute)
+	man := Manifest{Status: Status{StartedAt: &started, EndedAt: &ended}}
+	now := ended.Add(time.Minute)
+
+	storage, err := MeasureStorage(layout, man, now)
+	if err != nil {
+		t.Fatalf("MeasureStorage returned error: %v", err)
+	}
+	// capture.log is created empty by EnsureFilesystem; report/ is skipped.
+	if storage.Total.Files != 6 || storage.Total.Bytes != 3360 {
+		t.Fatalf("unexpected total: %+v", storage.Total)
+	}
+	if !storage.MeasuredAt.Equal(now) || storage.CaptureSeconds != 900 || storage.BytesPerHour != 4*3360 {
+		t.Fatalf("unexpected projection: %+v", storage)
+	}
+	var names []string
+	for _, entry := range storage.Subsystems {
+		names = append(names, entry.Name)
+	}
+	if got := filepath.Join(names...); got != filepath.Join("video", "screenshots", "events", RootFilesName) {
+		t.Fatalf("unexpected subsystem order: %v", names)
+	}
+	if shots := storage.Subsystem("screenshots"); shots.Files != 2 || shots.Bytes != 250 {
+		t.Fatalf("unexpected screenshots entry: %+v", shots)
+	}
+	if missing := storage.Subsystem("asr"); missing.Files != 0 || missing.Name != "asr" {
+		t.Fatalf("unexpected asr entry: %+v", missing)
+	}
+	byExt := make(map[string]StorageEntry)
+	for _, entry := range storage.Extensions {
+		byExt[entry.Name] = entry
+	}
+	if byExt[".png"].Bytes != 200 || byExt[".json"].Files != 2 || byExt[".log"].Files != 1 || byExt[".mp4"].Bytes != 3000 {
+		t.Fatalf("unexpected extensions: %+v", storage.Extensions)
+	}
+}
+
+func TestMeasureStorageWithoutDurationSkipsProjection(t *testing.T) {
+	layout := BuildLayout(t.TempDir(), "run")
+	storage, err := MeasureStorage(layout, Manifest{}, time.Now())
+	if err != nil {
+		t.Fatalf("MeasureStorage returned error: %v", err)
+	}
+	if storage.Total.Files != 0 || storage.BytesPerHour != 0 || storage.Subsystems == nil {
+		t.Fatalf("unexpected empty measurement: %+v", storage)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := map[int64]string{0: "0 B", 1023: "1023 B", 2048: "2.0 KiB", 3 << 20: "3.0 MiB", 5 << 30: "5.0 GiB"}
+	for n, want := range cases {
+		if got := FormatBytes(n); got != want {
+			t.Fatalf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
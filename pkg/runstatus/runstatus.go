@@ -0,0 +1,199 @@
+// Package runstatus reports the live state of a run from its manifest and
+// capture.log. The manifest only records subsystem outcomes once capture
+// ends, so while a run is in progress the log is the source of truth.
+package runstatus
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+// Subsystems are the capture subsystems, in the order capture reports them.
+var Subsystems = []string{"events", "screenshots", "video", "asr", "ocr"}
+
+// StateRunning marks a subsystem that has not logged an outcome yet.
+const StateRunning = "running"
+
+// LogEntry is one parsed capture.log line.
+type LogEntry struct {
+	Timestamp time.Time
+	Subsystem string
+	Message   string
+}
+
+// Snapshot is a run's state at one instant.
+type Snapshot struct {
+	RunID string
+	// State is the manifest's run state: pending, running, completed, or failed.
+	State string
+	// Controller is the latest controller state from capture.log.
+	Controller       string
+	ControllerReason string
+	StartedAt        *time.Time
+	// Deadline is unset when the run has no duration limit.
+	Deadline *time.Time
+	Elapsed  time.Duration
+	// Remaining is only set while the run is in progress and has a deadline.
+	Remaining  *time.Duration
+	Subsystems []Subsystem
+	Artifacts  runmanifest.Storage
+	LastLog    *LogEntry
+}
+
+// Subsystem is one subsystem's state and latest log message.
+type Subsystem struct {
+	Name    string
+	State   string
+	Message string
+}
+
+// Active reports whether capture has not finished yet.
+func (s Snapshot) Active() bool {
+	return s.State == "pending" || s.State == "running"
+}
+
+// Read builds a snapshot from the manifest, capture.log, and the files
+// written so far. A missing capture.log is treated as empty.
+func Read(layout runmanifest.Layout, man runmanifest.Manifest, now time.Time) (Snapshot, error) {
+	entries, err := ReadLog(layout.CaptureLogPath)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snap := Snapshot{RunID: man.RunID, State: man.Status.State}
+
+	logged := map[string]Subsystem{}
+	for i, entry := range entries {
+		snap.LastLog = &entries[i]
+		if entry.Subsystem != "controller" {
+			logged[entry.Subsystem] = subsystemFromLog(entry)
+			continue
+		}
+		if rest, ok := strings.CutPrefix(entry.Message, "state="); ok {
+			snap.Controller, snap.ControllerReason, _ = strings.Cut(rest, " reason=")
+		}
+		if strings.HasPrefix(entry.Message, "run_started") {
+			started := entry.Timestamp
+			snap.StartedAt = &started
+			if _, value, ok := strings.Cut(entry.Message, "deadline="); ok {
+				if deadline, err := time.Parse(time.RFC3339, strings.TrimSuffix(value, ")")); err == nil {
+					snap.Deadline = &deadline
+				}
+			}
+		}
+	}
+
+	if man.Status.StartedAt != nil {
+		started := man.Status.StartedAt.UTC()
+		snap.StartedAt = &started
+	}
+	if snap.Deadline == nil && snap.StartedAt != nil && man.Capture.DurationMinutes > 0 {
+		deadline := snap.StartedAt.Add(time.Duration(man.Capture.DurationMinutes) * time.Minute)
+		snap.Deadline = &deadline
+	}
+	if snap.Controller == "" && len(man.Status.Controller) > 0 {
+		last := man.Status.Controller[len(man.Status.Controller)-1]
+		snap.Controller, snap.ControllerReason = last.State, last.Reason
+	}
+
+	if snap.StartedAt != nil {
+		end := now
+		if man.Status.EndedAt != nil {
+			end = *man.Status.EndedAt
+		}
+		if snap.Elapsed = end.Sub(*snap.StartedAt); snap.Elapsed < 0 {
+			snap.Elapsed = 0
+		}
+	}
+	if snap.Active() && snap.Deadline != nil {
+		remaining := snap.Deadline.Sub(now)
+		if remaining < 0 {
+			remaining = 0
+		}
+		snap.Remaining = &remaining
+	}
+
+	snap.Subsystems = subsystems(man, logged)
+	snap.Artifacts, err = runmanifest.MeasureStorage(layout, man, now)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return snap, nil
+}
+
+// subsystems prefers the manifest's final outcomes. Until they are recorded,
+// each subsystem shows its latest log line, or running when it has none.
+func subsystems(man runmanifest.Manifest, logged map[string]Subsystem) []Subsystem {
+	if len(man.Status.Subsystems) > 0 {
+		out := make([]Subsystem, 0, len(man.Status.Subsystems))
+		for _, status := range man.Status.Subsystems {
+			out = append(out, Subsystem{Name: status.Name, State: status.State, Message: status.Message})
+		}
+		return out
+	}
+	out := make([]Subsystem, 0, len(Subsystems))
+	for _, name := range Subsystems {
+		sub, ok := logged[name]
+		if !ok {
+			sub = Subsystem{Name: name, State: StateRunning}
+		}
+		out = append(out, sub)
+	}
+	return out
+}
+
+// subsystemFromLog maps capture's skipped and unavailable lines to their
+// states; any other subsystem line reports a finished capture.
+func subsystemFromLog(entry LogEntry) Subsystem {
+	sub := Subsystem{Name: entry.Subsystem, State: runmanifest.SubsystemStateCompleted, Message: entry.Message}
+	for _, state := range []string{runmanifest.SubsystemStateSkipped, runmanifest.SubsystemStateUnavailable} {
+		if rest, ok := strings.CutPrefix(entry.Message, state+" ("); ok {
+			sub.State, sub.Message = state, strings.TrimSuffix(rest, ")")
+		}
+	}
+	return sub
+}
+
+// ReadLog parses capture.log. Lines that do not match the
+// "[timestamp] subsystem=name message" format are skipped.
+func ReadLog(path string) ([]LogEntry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open capture log: %w", err)
+	}
+	defer file.Close()
+	entries, err := ParseLog(file)
+	if err != nil {
+		return nil, fmt.Errorf("read capture log: %w", err)
+	}
+	return entries, nil
+}
+
+// ParseLog parses capture.log lines from r.
+func ParseLog(r io.Reader) ([]LogEntry, error) {
+	var entries []LogEntry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		stamp, rest, ok := strings.Cut(strings.TrimPrefix(line, "["), "] subsystem=")
+		if !ok || !strings.HasPrefix(line, "[") {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, stamp)
+		if err != nil {
+			continue
+		}
+		name, message, _ := strings.Cut(rest, " ")
+		entries = append(entries, LogEntry{Timestamp: ts, Subsystem: name, Message: message})
+	}
+	return entries, scanner.Err()
+}
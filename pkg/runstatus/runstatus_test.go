@@ -0,0 +1,103 @@
+package runstatus
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+var statusBase = time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+
+const liveLog = `[2024-05-12T09:30:00Z] subsystem=controller state=running reason=initial
+[2024-05-12T09:30:00Z] subsystem=controller run_started duration=1h0m0s (deadline=2024-05-12T10:30:00Z)
+[2024-05-12T09:30:01Z] subsystem=ocr unavailable (tesseract not installed)
+[2024-05-12T09:30:01Z] subsystem=asr skipped (disabled in config)
+not a capture log line
+[2024-05-12T09:40:00Z] subsystem=controller state=paused reason=pause requested
+[2024-05-12T09:41:00Z] subsystem=controller remaining=49m
+`
+
+func writeStatusRun(t *testing.T, log string) runmanifest.Layout {
+	t.Helper()
+	layout := runmanifest.BuildLayout(t.TempDir(), "run")
+	if err := runmanifest.EnsureFilesystem(layout); err != nil {
+		t.Fatalf("ensure filesystem: %v", err)
+	}
+	if err := os.WriteFile(layout.CaptureLogPath, []byte(log), 0o644); err != nil {
+		t.Fatalf("write capture log: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(layout.ScreensDir, "screenshot_0001.png"), make([]byte, 64), 0o644); err != nil {
+		t.Fatalf("write screenshot: %v", err)
+	}
+	return layout
+}
+
+func TestReadLiveRun(t *testing.T) {
+	layout := writeStatusRun(t, liveLog)
+	man := runmanifest.Manifest{RunID: "run", CreatedAt: statusBase, Status: runmanifest.Status{State: "running"}}
+
+	snap, err := Read(layout, man, statusBase.Add(12*time.Minute))
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if !snap.Active() || snap.Controller != "paused" || snap.ControllerReason != "pause requested" {
+		t.Fatalf("unexpected controller state: %+v", snap)
+	}
+	if snap.Elapsed != 12*time.Minute || snap.Remaining == nil || *snap.Remaining != 48*time.Minute {
+		t.Fatalf("unexpected timing: elapsed %s remaining %v", snap.Elapsed, snap.Remaining)
+	}
+	states := map[string]string{}
+	for _, sub := range snap.Subsystems {
+		states[sub.Name] = sub.State + ": " + sub.Message
+	}
+	if len(snap.Subsystems) != len(Subsystems) || states["ocr"] != "unavailable: tesseract not installed" || states["asr"] != "skipped: disabled in config" || states["video"] != "running: " {
+		t.Fatalf("unexpected subsystems: %v", states)
+	}
+	if snap.Artifacts.Subsystem("screenshots").Files != 1 {
+		t.Fatalf("unexpected artifacts: %+v", snap.Artifacts)
+	}
+	if snap.LastLog == nil || snap.LastLog.Message != "remaining=49m" {
+		t.Fatalf("unexpected last log entry: %+v", snap.LastLog)
+	}
+}
+
+func TestReadFinishedRunUsesManifest(t *testing.T) {
+	layout := writeStatusRun(t, liveLog)
+	started, ended := statusBase, statusBase.Add(20*time.Minute)
+	man := runmanifest.Manifest{
+		RunID: "run",
+		Status: runmanifest.Status{
+			State:      "completed",
+			StartedAt:  &started,
+			EndedAt:    &ended,
+			Subsystems: []runmanifest.SubsystemStatus{{Name: "video", State: runmanifest.SubsystemStateCompleted, Message: "segment recorded"}},
+		},
+	}
+	snap, err := Read(layout, man, statusBase.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if snap.Active() || snap.Remaining != nil || snap.Elapsed != 20*time.Minute {
+		t.Fatalf("unexpected finished timing: %+v", snap)
+	}
+	if len(snap.Subsystems) != 1 || snap.Subsystems[0].Message != "segment recorded" {
+		t.Fatalf("expected manifest subsystems, got %+v", snap.Subsystems)
+	}
+}
+
+func TestParseLogSkipsMalformedLines(t *testing.T) {
+	entries, err := ParseLog(strings.NewReader(liveLog))
+	if err != nil {
+		t.Fatalf("ParseLog returned error: %v", err)
+	}
+	if len(entries) != 6 || entries[2].Subsystem != "ocr" || !entries[2].Timestamp.Equal(statusBase.Add(time.Second)) {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if missing, err := ReadLog(filepath.Join(t.TempDir(), "capture.log")); err != nil || missing != nil {
+		t.Fatalf("missing log should read as empty, got %v, %v", missing, err)
+	}
+}
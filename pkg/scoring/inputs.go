@@ -0,0 +1,167 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/asr"
+	"github.com/offlinefirst/limitless-context/pkg/importer"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+	"github.com/offlinefirst/limitless-context/pkg/screenshots"
+	"github.com/offlinefirst/limitless-context/pkg/video"
+)
+
+// Inputs are the run artifacts the scorecard is derived from.
+type Inputs struct {
+	Manifest runmanifest.Manifest
+	// Tasks holds each bundles/task_NNN/metrics.json in task order.
+	Tasks []runmanifest.BundleMetrics
+	// Import is nil until tester process has written import/report.json.
+	Import *importer.Report
+	// Storage is the footprint the manifest recorded when capture finished,
+	// or one measured by Load for manifests written before it was recorded.
+	Storage runmanifest.Storage
+	// Screenshots counts captured frames, which OCR processes one by one.
+	Screenshots int
+	// Keyframes counts frames extracted from video, which OCR also processes.
+	Keyframes int
+	// TranscriptSeconds sums the cue spans of every ASR transcript.
+	TranscriptSeconds float64
+}
+
+// Load gathers a run's bundle metrics, import results, storage footprint,
+// screenshot and keyframe counts, and transcript length. Artifacts that were never
+// produced are left empty rather than reported as errors. now stamps the
+// storage measurement when the manifest has none.
+func Load(layout runmanifest.Layout, man runmanifest.Manifest, now time.Time) (Inputs, error) {
+	in := Inputs{Manifest: man}
+
+	paths, err := filepath.Glob(filepath.Join(layout.BundlesDir, "task_*", "metrics.json"))
+	if err != nil {
+		return Inputs{}, fmt.Errorf("list bundle metrics: %w", err)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		metrics, err := runmanifest.LoadBundleMetrics(path)
+		if err != nil {
+			return Inputs{}, fmt.Errorf("%s: %w", filepath.Base(filepath.Dir(path)), err)
+		}
+		in.Tasks = append(in.Tasks, metrics)
+	}
+
+	imported, err := importer.Load(filepath.Join(layout.ImportDir, importer.ReportFileName))
+	switch {
+	case err == nil:
+		in.Import = &imported
+	case !errors.Is(err, os.ErrNotExist):
+		return Inputs{}, err
+	}
+
+	if man.Storage != nil {
+		in.Storage = *man.Storage
+	} else {
+		in.Storage, err = runmanifest.MeasureStorage(layout, man, now)
+		if err != nil {
+			return Inputs{}, err
+		}
+	}
+
+	shots, err := screenshots.ReadDir(layout.ScreensDir)
+	if err != nil {
+		return Inputs{}, err
+	}
+	in.Screenshots = len(shots)
+
+	keyframes, err := video.ReadKeyframes(layout.VideoDir)
+	if err != nil {
+		return Inputs{}, err
+	}
+	in.Keyframes = len(keyframes)
+
+	in.TranscriptSeconds, err = transcriptSeconds(layout.ASRDir)
+	if err != nil {
+		return Inputs{}, err
+	}
+	return in, nil
+}
+
+func transcriptSeconds(dir string) (float64, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.vtt"))
+	if err != nil {
+		return 0, fmt.Errorf("list transcripts: %w", err)
+	}
+	var total float64
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return 0, fmt.Errorf("open transcript: %w", err)
+		}
+		cues, err := asr.ParseVTT(file)
+		file.Close()
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		for _, cue := range cues {
+			total += (cue.End - cue.Start).Seconds()
+		}
+	}
+	return total, nil
+}
+
+// status returns the manifest's recorded outcome for a subsystem.
+func (in Inputs) status(name string) (runmanifest.SubsystemStatus, bool) {
+	for _, status := range in.Manifest.Status.Subsystems {
+		if status.Name == name {
+			return status, true
+		}
+	}
+	return runmanifest.SubsystemStatus{}, false
+}
+
+// enabled reports whether the subsystem was switched on for the run.
+func (in Inputs) enabled(name string) bool {
+	if status, ok := in.status(name); ok {
+		return status.Enabled
+	}
+	capture := in.Manifest.Capture
+	switch name {
+	case "video":
+		return capture.VideoEnabled
+	case "screenshots":
+		return capture.ScreenshotsEnabled
+	case "events":
+		return capture.EventsEnabled
+	case "asr":
+		return capture.ASREnabled
+	case "ocr":
+		return capture.OCREnabled
+	}
+	return false
+}
+
+// captured reports whether the subsystem produced data, with a short reason
+// when it did not. Without a recorded status the config setting decides.
+func (in Inputs) captured(name string) (bool, string) {
+	status, ok := in.status(name)
+	if !ok {
+		if in.enabled(name) {
+			return true, ""
+		}
+		return false, "disabled"
+	}
+	switch {
+	case !status.Enabled:
+		return false, "disabled"
+	case !status.Available || status.State == runmanifest.SubsystemStateUnavailable:
+		return false, "unavailable"
+	case status.State == runmanifest.SubsystemStateErrored:
+		return false, "failed"
+	case status.State == runmanifest.SubsystemStateSkipped:
+		return false, "skipped"
+	}
+	return true, ""
+}
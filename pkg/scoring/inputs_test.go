@@ -0,0 +1,89 @@
+package scoring
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/importer"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+func TestLoadGathersRunArtifacts(t *testing.T) {
+	layout := runmanifest.BuildLayout(t.TempDir(), "run")
+	if err := runmanifest.EnsureFilesystem(layout); err != nil {
+		t.Fatalf("ensure filesystem: %v", err)
+	}
+	write := func(path string, data []byte) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	write(filepath.Join(layout.VideoDir, "segment_0001.mp4"), make([]byte, 4096))
+	write(filepath.Join(layout.ScreensDir, "screenshot_0001.png"), make([]byte, 100))
+	write(filepath.Join(layout.ScreensDir, "screenshot_0001.json"), []byte(`{"captured_at":"2024-05-12T09:00:15Z"}`))
+	write(filepath.Join(layout.VideoDir, "frames", "frame_0001.json"), []byte(`{"captured_at":"2024-05-12T09:00:00Z"}`))
+	write(filepath.Join(layout.VideoDir, "frames", "frame_0002.json"), []byte(`{"captured_at":"2024-05-12T09:01:00Z"}`))
+	write(filepath.Join(layout.ASRDir, "meeting_0001.vtt"), []byte("WEBVTT\n\n1\n00:00:00.000 --> 00:00:05.000\nHello.\n\n2\n00:00:05.000 --> 00:00:12.500\nBye.\n"))
+	for _, id := range []string{"task_002", "task_001"} {
+		dir := filepath.Join(layout.BundlesDir, id)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := runmanifest.SaveBundleMetrics(runmanifest.BundleMetrics{TaskID: id, TokenBudget: 5000}, filepath.Join(dir, "metrics.json")); err != nil {
+			t.Fatalf("save metrics: %v", err)
+		}
+	}
+
+	in, err := Load(layout, runmanifest.Manifest{RunID: "run"}, time.Now())
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(in.Tasks) != 2 || in.Tasks[0].TaskID != "task_001" {
+		t.Fatalf("unexpected tasks: %+v", in.Tasks)
+	}
+	if in.Import != nil {
+		t.Fatalf("import should be nil before tester process: %+v", in.Import)
+	}
+	if video := in.Storage.Subsystem("video"); video.Files != 3 || video.Bytes < 4096 || in.Storage.Subsystem("screenshots").Files != 2 || in.Storage.Subsystem("ocr").Files != 0 {
+		t.Fatalf("unexpected storage: %+v", in.Storage)
+	}
+	if in.Screenshots != 1 || in.Keyframes != 2 || in.TranscriptSeconds != 12.5 {
+		t.Fatalf("unexpected counts: %d screenshots, %d keyframes, %.1fs transcript", in.Screenshots, in.Keyframes, in.TranscriptSeconds)
+	}
+
+	if err := importer.Save(importer.Report{SchemaVersion: importer.ReportVersion, Summary: importer.Summary{Valid: 2}}, filepath.Join(layout.ImportDir, importer.ReportFileName)); err != nil {
+		t.Fatalf("save import: %v", err)
+	}
+	in, err = Load(layout, runmanifest.Manifest{RunID: "run"}, time.Now())
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if in.Import == nil || in.Import.Summary.Valid != 2 {
+		t.Fatalf("expected import results, got %+v", in.Import)
+	}
+}
+
+func TestLoadPrefersRecordedStorage(t *testing.T) {
+	layout := runmanifest.BuildLayout(t.TempDir(), "run")
+	if err := runmanifest.EnsureFilesystem(layout); err != nil {
+		t.Fatalf("ensure filesystem: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(layout.VideoDir, "segment_0001.mp4"), make([]byte, 4096), 0o644); err != nil {
+		t.Fatalf("write segment: %v", err)
+	}
+	recorded := runmanifest.Storage{Total: runmanifest.StorageEntry{Bytes: 1 << 20, Files: 7}, Subsystems: []runmanifest.StorageEntry{{Name: "video", Bytes: 1 << 20, Files: 7}}}
+
+	in, err := Load(layout, runmanifest.Manifest{RunID: "run", Storage: &recorded}, time.Now())
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if video := in.Storage.Subsystem("video"); in.Storage.Total.Bytes != 1<<20 || video.Files != 7 {
+		t.Fatalf("expected the recorded footprint, got %+v", in.Storage)
+	}
+}
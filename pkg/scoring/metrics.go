@@ -0,0 +1,318 @@
+package scoring
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+// ReferenceBytesPerHour is the storage rate that scores 0 on StorageCost,
+// roughly an hour of 1080p screen recording.
+const ReferenceBytesPerHour = 2 << 30
+
+// Post-capture processing rates used by Runtime.
+const (
+	// OCRSecondsPerFrame is recognition time per screenshot or keyframe.
+	OCRSecondsPerFrame = 2.0
+	// ASRRealtimeFactor is transcription time per second of meeting audio.
+	ASRRealtimeFactor = 0.5
+)
+
+// profile holds the fixed per-subsystem costs behind SetupEffort and
+// PrivacyExposure.
+type profile struct {
+	setup      int
+	setupNeeds string
+	exposure   int
+	exposes    string
+}
+
+var profiles = map[string]profile{
+	"video":       {setup: 35, setupNeeds: "Screen Recording permission and an encoder", exposure: 60, exposes: "every pixel on screen"},
+	"screenshots": {setup: 25, setupNeeds: "Screen Recording permission", exposure: 30, exposes: "periodic full-screen frames"},
+	"events":      {setup: 25, setupNeeds: "Accessibility permission", exposure: 10, exposes: "redacted app and window metadata"},
+	"ocr":         {setup: 15, setupNeeds: "a Tesseract install", exposure: 10, exposes: "on-screen text"},
+	"asr":         {setup: 20, setupNeeds: "a Whisper install and microphone access", exposure: 20, exposes: "meeting speech"},
+}
+
+// unavailablePenalty is deducted from SetupEffort for each enabled signal
+// that could not be set up on the capturing host.
+const unavailablePenalty = 10
+
+// failedRunPenalty is deducted from Robustness when the run ended in error.
+const failedRunPenalty = 25
+
+type assessment struct {
+	score  float64
+	scored bool
+	why    string
+}
+
+func scored(score float64, format string, args ...any) assessment {
+	return assessment{score: score, scored: true, why: fmt.Sprintf(format, args...)}
+}
+
+func unscored(format string, args ...any) assessment {
+	return assessment{why: fmt.Sprintf(format, args...)}
+}
+
+// fidelity scales the mode's ceiling by the share of its signals that were
+// captured and, once outputs are processed, by the share that validated.
+func fidelity(in Inputs, mode Mode) assessment {
+	share := 0
+	var missing []string
+	for _, signal := range mode.Signals {
+		if ok, reason := in.captured(signal.Subsystem); ok {
+			share += signal.Share
+		} else {
+			missing = append(missing, fmt.Sprintf("%s %s", signal.Subsystem, reason))
+		}
+	}
+	score := float64(mode.Ceiling*share) / 100
+	why := fmt.Sprintf("ceiling %d x %d%% of signal weight captured", mode.Ceiling, share)
+	if len(missing) > 0 {
+		why += fmt.Sprintf(" (%s)", strings.Join(missing, ", "))
+	}
+	if in.Import != nil && bundled(in, mode) {
+		summary := in.Import.Summary
+		if processed := summary.Valid + summary.Invalid; processed > 0 {
+			score *= float64(summary.Valid) / float64(processed)
+			why += fmt.Sprintf(" x %d/%d processed outputs valid", summary.Valid, processed)
+		}
+	}
+	return scored(score, "%s = %.0f", why, score)
+}
+
+// bundled reports whether any of the mode's signals reached a task context,
+// making the task outputs evidence of the mode's fidelity.
+func bundled(in Inputs, mode Mode) bool {
+	for _, task := range in.Tasks {
+		for _, signal := range mode.Signals {
+			var items int
+			switch signal.Subsystem {
+			case "events":
+				items = task.Items.Events
+			case "screenshots":
+				items = task.Items.Screenshots
+			case "ocr":
+				items = task.Items.OCR
+			case "asr":
+				items = task.Items.ASR
+			}
+			if items > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// traceability is the share of output citations that resolve to an anchor
+// the mode keeps: event IDs need events, and shot offsets need screenshots or,
+// for video, extracted keyframes.
+func traceability(in Inputs, mode Mode) assessment {
+	if in.Import == nil {
+		return unscored("no import results; run tester process")
+	}
+	summary := in.Import.Summary
+	if summary.Cited == 0 {
+		return unscored("processed outputs cite no evidence")
+	}
+	resolved := 0
+	var anchors []string
+	for _, signal := range mode.Signals {
+		if ok, _ := in.captured(signal.Subsystem); !ok {
+			continue
+		}
+		switch signal.Subsystem {
+		case "events":
+			resolved += summary.ResolvedEvents
+			anchors = append(anchors, "event")
+		case "screenshots":
+			resolved += summary.ResolvedShots
+			anchors = append(anchors, "shot")
+		case "video":
+			resolved += summary.ResolvedKeyframes
+			anchors = append(anchors, "keyframe shot")
+		}
+	}
+	if len(anchors) == 0 {
+		return scored(0, "none of the mode's anchor signals were captured, so none of %d citations can be verified", summary.Cited)
+	}
+	score := 100 * float64(resolved) / float64(summary.Cited)
+	return scored(score, "%d of %d citations resolve to %s anchors = %.0f", resolved, summary.Cited, strings.Join(anchors, " and "), score)
+}
+
+// tokenCost compares the mean per-task tokens the mode would send (prompt,
+// context header, and the mode's context sections) with the token budget.
+func tokenCost(in Inputs, mode Mode) assessment {
+	if len(in.Tasks) == 0 {
+		return unscored("no task bundles; run tester bundle")
+	}
+	var tokens, budget int
+	for _, task := range in.Tasks {
+		tokens += task.Tokens.Prompt + task.SectionTokens.Header
+		budget += task.TokenBudget
+		for _, signal := range mode.Signals {
+			switch signal.Subsystem {
+			case "events":
+				tokens += task.SectionTokens.Events
+			case "ocr":
+				tokens += task.SectionTokens.OCR
+			case "asr":
+				tokens += task.SectionTokens.ASR
+			}
+		}
+	}
+	if budget == 0 {
+		return unscored("bundle metrics record no token budget")
+	}
+	mean := float64(tokens) / float64(len(in.Tasks))
+	meanBudget := float64(budget) / float64(len(in.Tasks))
+	score := 100 * (1 - mean/meanBudget)
+	return scored(score, "mean %.0f tokens per task (prompt, header, and %s sections) against a %.0f-token budget = %.0f",
+		mean, strings.Join(mode.Subsystems(), ", "), meanBudget, clamp(score))
+}
+
+// storageCost projects the bytes written by the mode's subsystems to an
+// hourly rate and compares it with ReferenceBytesPerHour.
+func storageCost(in Inputs, mode Mode) assessment {
+	seconds := in.Manifest.CaptureDuration().Seconds()
+	if seconds <= 0 {
+		return unscored("capture duration unknown")
+	}
+	var bytes int64
+	for _, signal := range mode.Signals {
+		bytes += in.Storage.Subsystem(signal.Subsystem).Bytes
+	}
+	perHour := float64(bytes) * 3600 / seconds
+	score := 100 * (1 - perHour/ReferenceBytesPerHour)
+	return scored(score, "%s over %s projects to %s per hour against %s = %.0f",
+		runmanifest.FormatBytes(bytes), formatSeconds(seconds), runmanifest.FormatBytes(int64(perHour)), runmanifest.FormatBytes(ReferenceBytesPerHour), clamp(score))
+}
+
+// setupEffort deducts each signal's permission and install cost, plus a
+// penalty for every enabled signal that could not be set up on this host.
+func setupEffort(in Inputs, mode Mode) assessment {
+	score := 100
+	var parts []string
+	for _, signal := range mode.Signals {
+		p := profiles[signal.Subsystem]
+		score -= p.setup
+		parts = append(parts, fmt.Sprintf("%s needs %s (-%d)", signal.Subsystem, p.setupNeeds, p.setup))
+		if ok, reason := in.captured(signal.Subsystem); !ok && reason == "unavailable" && in.enabled(signal.Subsystem) {
+			score -= unavailablePenalty
+			parts = append(parts, fmt.Sprintf("%s was unavailable on this host (-%d)", signal.Subsystem, unavailablePenalty))
+		}
+	}
+	return scored(float64(score), "%s = %d", strings.Join(parts, "; "), score)
+}
+
+// runtimeCost estimates post-capture processing time (OCR per frame the mode
+// keeps, ASR per second of audio) as a share of the captured time.
+func runtimeCost(in Inputs, mode Mode) assessment {
+	seconds := in.Manifest.CaptureDuration().Seconds()
+	if seconds <= 0 {
+		return unscored("capture duration unknown")
+	}
+	var processing float64
+	var parts []string
+	for _, signal := range mode.Signals {
+		if ok, _ := in.captured(signal.Subsystem); !ok {
+			continue
+		}
+		switch signal.Subsystem {
+		case "ocr":
+			frames, kinds := ocrFrames(in, mode)
+			processing += float64(frames) * OCRSecondsPerFrame
+			parts = append(parts, fmt.Sprintf("OCR %d %s x %.1fs", frames, kinds, OCRSecondsPerFrame))
+		case "asr":
+			cost := in.TranscriptSeconds * ASRRealtimeFactor
+			processing += cost
+			parts = append(parts, fmt.Sprintf("ASR %s of audio x %.1f", formatSeconds(in.TranscriptSeconds), ASRRealtimeFactor))
+		}
+	}
+	if len(parts) == 0 {
+		return scored(100, "no post-capture processing")
+	}
+	score := 100 * (1 - processing/seconds)
+	return scored(score, "%s = %s of processing for %s captured = %.0f",
+		strings.Join(parts, " + "), formatSeconds(processing), formatSeconds(seconds), clamp(score))
+}
+
+// ocrFrames counts the frames OCR reads for the mode: screenshots when it
+// takes them and keyframes when it records video.
+func ocrFrames(in Inputs, mode Mode) (int, string) {
+	frames := 0
+	var kinds []string
+	for _, signal := range mode.Signals {
+		switch signal.Subsystem {
+		case "screenshots":
+			frames += in.Screenshots
+			kinds = append(kinds, "screenshots")
+		case "video":
+			frames += in.Keyframes
+			kinds = append(kinds, "keyframes")
+		}
+	}
+	if len(kinds) == 0 {
+		return 0, "frames"
+	}
+	return frames, strings.Join(kinds, " and ")
+}
+
+// privacyExposure deducts a fixed exposure for every signal that captured
+// data; higher scores mean less was exposed.
+func privacyExposure(in Inputs, mode Mode) assessment {
+	score := 100
+	var parts []string
+	for _, signal := range mode.Signals {
+		if ok, _ := in.captured(signal.Subsystem); !ok {
+			continue
+		}
+		p := profiles[signal.Subsystem]
+		score -= p.exposure
+		parts = append(parts, fmt.Sprintf("%s records %s (-%d)", signal.Subsystem, p.exposes, p.exposure))
+	}
+	if len(parts) == 0 {
+		return scored(100, "no signal captured data")
+	}
+	return scored(float64(score), "%s = %d", strings.Join(parts, "; "), score)
+}
+
+// robustness is the share of the mode's enabled signals that completed,
+// less a penalty when the run itself ended in error.
+func robustness(in Inputs, mode Mode) assessment {
+	enabled, completed := 0, 0
+	var failed []string
+	for _, signal := range mode.Signals {
+		if !in.enabled(signal.Subsystem) {
+			continue
+		}
+		enabled++
+		if ok, reason := in.captured(signal.Subsystem); ok {
+			completed++
+		} else {
+			failed = append(failed, fmt.Sprintf("%s %s", signal.Subsystem, reason))
+		}
+	}
+	if enabled == 0 {
+		return scored(0, "no signal of this mode was enabled")
+	}
+	score := 100 * float64(completed) / float64(enabled)
+	why := fmt.Sprintf("%d of %d enabled signals completed", completed, enabled)
+	if len(failed) > 0 {
+		why += fmt.Sprintf(" (%s)", strings.Join(failed, ", "))
+	}
+	if status := in.Manifest.Status; status.State == "failed" || status.Termination == "error" {
+		score -= failedRunPenalty
+		why += fmt.Sprintf("; the run ended in error (-%d)", failedRunPenalty)
+	}
+	return scored(score, "%s = %.0f", why, clamp(score))
+}
+
+func formatSeconds(seconds float64) string {
+	return (time.Duration(seconds) * time.Second).String()
+}
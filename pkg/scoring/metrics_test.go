@@ -0,0 +1,103 @@
+package scoring
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+func modeNamed(t *testing.T, name string) Mode {
+	t.Helper()
+	for _, mode := range Modes {
+		if mode.Name == name {
+			return mode
+		}
+	}
+	t.Fatalf("no mode %q", name)
+	return Mode{}
+}
+
+func TestMetricDerivations(t *testing.T) {
+	in := hourRun()
+	cases := []struct {
+		metric func(Inputs, Mode) assessment
+		name   string
+		mode   string
+		score  float64
+		why    string
+	}{
+		{fidelity, "fidelity", "Hybrid", 54, "ceiling 90 x 80% of signal weight captured (ocr unavailable) x 3/4 processed outputs valid = 54"},
+		{fidelity, "fidelity", "Video-only", 60.56, "ceiling 95 x 85% of signal weight captured (ocr unavailable) x 3/4 processed outputs valid"},
+		{fidelity, "fidelity", "Events-only", 45, "ceiling 60 x 100% of signal weight captured x 3/4 processed outputs valid = 45"},
+		{traceability, "traceability", "Hybrid", 80, "8 of 10 citations resolve to event and shot anchors = 80"},
+		{traceability, "traceability", "Events-only", 60, "6 of 10 citations resolve to event anchors = 60"},
+		{traceability, "traceability", "Video-only", 30, "3 of 10 citations resolve to keyframe shot anchors = 30"},
+		{tokenCost, "token cost", "Hybrid", 60, "mean 2000 tokens per task"},
+		{tokenCost, "token cost", "Video-only", 80, "mean 1000 tokens per task (prompt, header, and video, ocr, asr sections)"},
+		{storageCost, "storage cost", "Video-only", 49.95, "1.0 GiB over 1h0m0s projects to 1.0 GiB per hour against 2.0 GiB = 50"},
+		{setupEffort, "setup effort", "Hybrid", 5, "ocr was unavailable on this host (-10)"},
+		{setupEffort, "setup effort", "Events-only", 75, "events needs Accessibility permission (-25) = 75"},
+		{runtimeCost, "runtime", "Hybrid", 100 * (1 - 300.0/3600), "ASR 10m0s of audio x 0.5 = 5m0s of processing for 1h0m0s captured"},
+		{runtimeCost, "runtime", "Events-only", 100, "no post-capture processing"},
+		{privacyExposure, "privacy", "Video-only", 20, "video records every pixel on screen (-60)"},
+		{privacyExposure, "privacy", "Hybrid", 40, "= 40"},
+		{robustness, "robustness", "Hybrid", 75, "3 of 4 enabled signals completed (ocr unavailable) = 75"},
+	}
+	for _, tc := range cases {
+		got := tc.metric(in, modeNamed(t, tc.mode))
+		if !got.scored || math.Abs(got.score-tc.score) > 0.01 {
+			t.Fatalf("%s %s = %.2f (scored %v), want %.2f", tc.mode, tc.name, got.score, got.scored, tc.score)
+		}
+		if !strings.Contains(got.why, tc.why) {
+			t.Fatalf("%s %s explanation %q does not contain %q", tc.mode, tc.name, got.why, tc.why)
+		}
+	}
+}
+
+func TestRuntimeCountsFramesTheModeKeeps(t *testing.T) {
+	in := hourRun()
+	for i, status := range in.Manifest.Status.Subsystems {
+		if status.Name == "ocr" {
+			in.Manifest.Status.Subsystems[i] = runmanifest.SubsystemStatus{Name: "ocr", Enabled: true, Available: true, State: runmanifest.SubsystemStateCompleted}
+		}
+	}
+	video := runtimeCost(in, modeNamed(t, "Video-only"))
+	if math.Abs(video.score-100*(1-420.0/3600)) > 0.01 || !strings.Contains(video.why, "OCR 60 keyframes x 2.0s + ASR 10m0s of audio x 0.5") {
+		t.Fatalf("unexpected Video-only runtime: %+v", video)
+	}
+	hybrid := runtimeCost(in, modeNamed(t, "Hybrid"))
+	if math.Abs(hybrid.score-100*(1-780.0/3600)) > 0.01 || !strings.Contains(hybrid.why, "OCR 240 screenshots x 2.0s") {
+		t.Fatalf("unexpected Hybrid runtime: %+v", hybrid)
+	}
+}
+
+func TestTraceabilityWithoutCapturedAnchors(t *testing.T) {
+	in := hourRun()
+	in.Manifest.Status.Subsystems[2].State = runmanifest.SubsystemStateErrored
+	got := traceability(in, modeNamed(t, "Video-only"))
+	if !got.scored || got.score != 0 || !strings.Contains(got.why, "none of the mode's anchor signals were captured") {
+		t.Fatalf("unexpected traceability: %+v", got)
+	}
+}
+
+func TestRobustnessPenalisesFailedRuns(t *testing.T) {
+	in := hourRun()
+	in.Manifest.Status.State = "failed"
+	in.Manifest.Status.Termination = "error"
+	got := robustness(in, modeNamed(t, "Events-only"))
+	if got.score != 75 || !strings.Contains(got.why, "the run ended in error (-25)") {
+		t.Fatalf("unexpected robustness: %+v", got)
+	}
+}
+
+func TestCapturedFallsBackToConfig(t *testing.T) {
+	in := Inputs{Manifest: runmanifest.Manifest{Capture: runmanifest.CaptureSettings{EventsEnabled: true}}}
+	if ok, _ := in.captured("events"); !ok {
+		t.Fatalf("events should count as captured when enabled without a status")
+	}
+	if ok, reason := in.captured("video"); ok || reason != "disabled" {
+		t.Fatalf("video should be disabled, got %v %q", ok, reason)
+	}
+}
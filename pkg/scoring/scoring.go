@@ -0,0 +1,214 @@
+// Package scoring derives the report's Mode x Metrics scorecard from a run's
+// artifacts. Every score ranges 0-100 where higher is better, so
+// PrivacyExposure 90 means little was exposed, and each one carries an
+// explanation of the figures it was derived from.
+package scoring
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Metric is one column of the scorecard. Key names the metric in config
+// (report.weights.<key>).
+type Metric struct {
+	Name   string `json:"name"`
+	Key    string `json:"key"`
+	Weight int    `json:"weight"`
+}
+
+// DefaultMetrics lists the scorecard columns with their default weights.
+// docs/SPEC.md lists eight metrics but only seven weights (30/20/15/10/10/10/5).
+// Those are applied in column order, so Robustness defaults to 0: it is
+// reported but does not count toward the total until report.weights.robustness
+// gives it a weight.
+var DefaultMetrics = []Metric{
+	{Name: "Fidelity", Key: "fidelity", Weight: 30},
+	{Name: "Traceability", Key: "traceability", Weight: 20},
+	{Name: "TokenCost", Key: "token_cost", Weight: 15},
+	{Name: "StorageCost", Key: "storage_cost", Weight: 10},
+	{Name: "SetupEffort", Key: "setup_effort", Weight: 10},
+	{Name: "Runtime", Key: "runtime", Weight: 10},
+	{Name: "PrivacyExposure", Key: "privacy_exposure", Weight: 5},
+	{Name: "Robustness", Key: "robustness", Weight: 0},
+}
+
+// Signal is a subsystem a mode relies on and its share of the mode's fidelity.
+type Signal struct {
+	Subsystem string `json:"subsystem"`
+	Share     int    `json:"share"`
+	// Optional signals do not make the mode unavailable when missing.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// Mode is a capture mode compared in the scorecard. Ceiling is the fidelity
+// the mode reaches when every signal was captured.
+type Mode struct {
+	Name    string   `json:"name"`
+	Ceiling int      `json:"ceiling"`
+	Signals []Signal `json:"signals"`
+}
+
+// Subsystems lists the mode's signals by name.
+func (m Mode) Subsystems() []string {
+	out := make([]string, 0, len(m.Signals))
+	for _, signal := range m.Signals {
+		out = append(out, signal.Subsystem)
+	}
+	return out
+}
+
+// Modes are the concurrent capture modes a run is evaluated as. Video-only's
+// ocr signal is OCR of the keyframes extracted from its recording.
+var Modes = []Mode{
+	{Name: "Video-only", Ceiling: 95, Signals: []Signal{
+		{Subsystem: "video", Share: 70},
+		{Subsystem: "ocr", Share: 15, Optional: true},
+		{Subsystem: "asr", Share: 15, Optional: true},
+	}},
+	{Name: "Hybrid", Ceiling: 90, Signals: []Signal{
+		{Subsystem: "events", Share: 40},
+		{Subsystem: "screenshots", Share: 25},
+		{Subsystem: "ocr", Share: 20},
+		{Subsystem: "asr", Share: 15, Optional: true},
+	}},
+	{Name: "Events-only", Ceiling: 60, Signals: []Signal{
+		{Subsystem: "events", Share: 100},
+	}},
+}
+
+// Options configure a scoring pass.
+type Options struct {
+	// Weights override DefaultMetrics weights by metric key.
+	Weights map[string]int
+}
+
+// Scorecard is the Mode x Metrics comparison table.
+type Scorecard struct {
+	Metrics []Metric    `json:"metrics"`
+	Modes   []ModeScore `json:"modes"`
+}
+
+// ModeScore is one row of the scorecard. Scores follow the Metrics order.
+// Total is the weighted mean of the scored metrics, or nil when none were.
+type ModeScore struct {
+	Mode       string        `json:"mode"`
+	Subsystems []string      `json:"subsystems"`
+	Available  bool          `json:"available"`
+	Scores     []MetricScore `json:"scores"`
+	Total      *float64      `json:"total,omitempty"`
+}
+
+// MetricScore is one cell of the scorecard. Unscored cells lack the artifacts
+// they are derived from; Explanation then says which step produces them.
+type MetricScore struct {
+	Metric      string  `json:"metric"`
+	Weight      int     `json:"weight"`
+	Score       float64 `json:"score"`
+	Scored      bool    `json:"scored"`
+	Explanation string  `json:"explanation"`
+}
+
+// Metrics applies weight overrides to DefaultMetrics. Unknown keys, negative
+// weights, and weights that sum to zero are rejected.
+func Metrics(overrides map[string]int) ([]Metric, error) {
+	metrics := append([]Metric(nil), DefaultMetrics...)
+	index := make(map[string]int, len(metrics))
+	for i, metric := range metrics {
+		index[metric.Key] = i
+	}
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		i, ok := index[key]
+		if !ok {
+			known := make([]string, 0, len(metrics))
+			for _, metric := range metrics {
+				known = append(known, metric.Key)
+			}
+			return nil, fmt.Errorf("report.weights.%s: unknown metric (known: %s)", key, strings.Join(known, ", "))
+		}
+		if overrides[key] < 0 {
+			return nil, fmt.Errorf("report.weights.%s: weight must not be negative", key)
+		}
+		metrics[i].Weight = overrides[key]
+	}
+	sum := 0
+	for _, metric := range metrics {
+		sum += metric.Weight
+	}
+	if sum == 0 {
+		return nil, fmt.Errorf("report.weights: at least one metric needs a positive weight")
+	}
+	return metrics, nil
+}
+
+// scorers derive each metric for a mode, keyed by metric key.
+var scorers = map[string]func(Inputs, Mode) assessment{
+	"fidelity":         fidelity,
+	"traceability":     traceability,
+	"token_cost":       tokenCost,
+	"storage_cost":     storageCost,
+	"setup_effort":     setupEffort,
+	"runtime":          runtimeCost,
+	"privacy_exposure": privacyExposure,
+	"robustness":       robustness,
+}
+
+// Score evaluates every mode against the run's inputs.
+func Score(in Inputs, opts Options) (Scorecard, error) {
+	metrics, err := Metrics(opts.Weights)
+	if err != nil {
+		return Scorecard{}, err
+	}
+	card := Scorecard{Metrics: metrics, Modes: make([]ModeScore, 0, len(Modes))}
+	for _, mode := range Modes {
+		row := ModeScore{Mode: mode.Name, Subsystems: mode.Subsystems(), Available: available(in, mode)}
+		var weighted, weights float64
+		for _, metric := range metrics {
+			result := scorers[metric.Key](in, mode)
+			cell := MetricScore{Metric: metric.Name, Weight: metric.Weight, Scored: result.scored, Explanation: result.why}
+			if result.scored {
+				cell.Score = clamp(result.score)
+				weighted += cell.Score * float64(metric.Weight)
+				weights += float64(metric.Weight)
+			}
+			row.Scores = append(row.Scores, cell)
+		}
+		if weights > 0 {
+			total := weighted / weights
+			row.Total = &total
+		}
+		card.Modes = append(card.Modes, row)
+	}
+	return card, nil
+}
+
+// available reports whether every required signal of the mode was enabled
+// and available. Subsystems without a recorded status are not held against it.
+func available(in Inputs, mode Mode) bool {
+	for _, signal := range mode.Signals {
+		if signal.Optional {
+			continue
+		}
+		status, ok := in.status(signal.Subsystem)
+		if ok && (!status.Enabled || !status.Available) {
+			return false
+		}
+	}
+	return true
+}
+
+func clamp(v float64) float64 {
+	switch {
+	case v < 0:
+		return 0
+	case v > 100:
+		return 100
+	}
+	return v
+}
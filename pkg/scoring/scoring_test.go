@@ -0,0 +1,152 @@
+package scoring
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/importer"
+	"github.com/offlinefirst/limitless-context/pkg/runmanifest"
+)
+
+var scoringBase = time.Date(2024, 5, 12, 9, 0, 0, 0, time.UTC)
+
+// hourRun is a one-hour capture where OCR was unavailable and everything
+// else completed.
+func hourRun() Inputs {
+	started, ended := scoringBase, scoringBase.Add(time.Hour)
+	completed := func(name string) runmanifest.SubsystemStatus {
+		return runmanifest.SubsystemStatus{Name: name, Enabled: true, Available: true, State: runmanifest.SubsystemStateCompleted}
+	}
+	man := runmanifest.Manifest{
+		RunID:  "run",
+		Status: runmanifest.Status{State: "completed", StartedAt: &started, EndedAt: &ended},
+	}
+	man.Status.Subsystems = []runmanifest.SubsystemStatus{
+		completed("events"), completed("screenshots"), completed("video"), completed("asr"),
+		{Name: "ocr", Enabled: true, Available: false, State: runmanifest.SubsystemStateUnavailable, Message: "tesseract not installed"},
+	}
+	task := runmanifest.BundleMetrics{
+		Items:         runmanifest.BundleItemCounts{Events: 10, Screenshots: 4, ASR: 2},
+		Tokens:        runmanifest.BundleSizes{Prompt: 200},
+		SectionTokens: runmanifest.BundleSectionTokens{Header: 50, Events: 1000, OCR: 500, ASR: 250},
+		TokenBudget:   5000,
+	}
+	return Inputs{
+		Manifest: man,
+		Tasks:    []runmanifest.BundleMetrics{task, task},
+		Import: &importer.Report{Summary: importer.Summary{
+			Tasks: 4, Valid: 3, Invalid: 1, Cited: 10, Resolved: 8, ResolvedEvents: 6, ResolvedShots: 2, ResolvedKeyframes: 3, Dangling: 2,
+		}},
+		Storage: runmanifest.Storage{Subsystems: []runmanifest.StorageEntry{
+			{Name: "video", Files: 1, Bytes: 1 << 30},
+			{Name: "screenshots", Files: 480, Bytes: 100 << 20},
+			{Name: "events", Files: 2, Bytes: 1 << 20},
+			{Name: "asr", Files: 2, Bytes: 1 << 20},
+		}},
+		Screenshots:       240,
+		Keyframes:         60,
+		TranscriptSeconds: 600,
+	}
+}
+
+func cell(t *testing.T, card Scorecard, mode, metric string) MetricScore {
+	t.Helper()
+	for _, row := range card.Modes {
+		if row.Mode != mode {
+			continue
+		}
+		for _, score := range row.Scores {
+			if score.Metric == metric {
+				return score
+			}
+		}
+	}
+	t.Fatalf("no %s score for %s", metric, mode)
+	return MetricScore{}
+}
+
+func TestMetricsAppliesOverrides(t *testing.T) {
+	metrics, err := Metrics(map[string]int{"fidelity": 50, "robustness": 5})
+	if err != nil {
+		t.Fatalf("Metrics returned error: %v", err)
+	}
+	if len(metrics) != len(DefaultMetrics) || metrics[0].Weight != 50 || metrics[7].Weight != 5 || metrics[1].Weight != 20 {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+	if DefaultMetrics[0].Weight != 30 {
+		t.Fatalf("overrides must not modify DefaultMetrics")
+	}
+}
+
+func TestMetricsRejectsBadWeights(t *testing.T) {
+	cases := map[string]struct {
+		weights map[string]int
+		want    string
+	}{
+		"unknown":  {map[string]int{"speed": 1}, "report.weights.speed: unknown metric"},
+		"negative": {map[string]int{"runtime": -1}, "must not be negative"},
+		"zero sum": {map[string]int{"fidelity": 0, "traceability": 0, "token_cost": 0, "storage_cost": 0, "setup_effort": 0, "runtime": 0, "privacy_exposure": 0}, "positive weight"},
+	}
+	for name, tc := range cases {
+		if _, err := Metrics(tc.weights); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected %q, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestScoreComparesModes(t *testing.T) {
+	card, err := Score(hourRun(), Options{})
+	if err != nil {
+		t.Fatalf("Score returned error: %v", err)
+	}
+	if len(card.Modes) != len(Modes) || len(card.Metrics) != len(DefaultMetrics) {
+		t.Fatalf("unexpected scorecard shape: %+v", card)
+	}
+	availability := map[string]bool{}
+	for _, row := range card.Modes {
+		availability[row.Mode] = row.Available
+		if row.Total == nil || len(row.Scores) != len(card.Metrics) {
+			t.Fatalf("incomplete row: %+v", row)
+		}
+		for _, score := range row.Scores {
+			if !score.Scored || score.Explanation == "" {
+				t.Fatalf("%s %s should be scored with an explanation: %+v", row.Mode, score.Metric, score)
+			}
+		}
+	}
+	if !availability["Video-only"] || availability["Hybrid"] || !availability["Events-only"] {
+		t.Fatalf("unexpected availability: %v", availability)
+	}
+
+	// Events-only: fidelity 45, traceability 60, token cost 75, storage ~100,
+	// setup 75, runtime 100, privacy 90, robustness unweighted.
+	events := card.Modes[2]
+	want := (45*30 + 60*20 + 75*15 + cell(t, card, "Events-only", "StorageCost").Score*10 + 75*10 + 100*10 + 90*5) / 100
+	if math.Abs(*events.Total-want) > 0.01 {
+		t.Fatalf("Events-only total = %.2f, want %.2f", *events.Total, want)
+	}
+}
+
+func TestScoreHonoursWeightsAndSkipsUnscored(t *testing.T) {
+	in := hourRun()
+	in.Tasks, in.Import = nil, nil
+	card, err := Score(in, Options{Weights: map[string]int{"fidelity": 0, "traceability": 50, "token_cost": 50, "storage_cost": 0, "setup_effort": 0, "runtime": 0, "privacy_exposure": 0}})
+	if err != nil {
+		t.Fatalf("Score returned error: %v", err)
+	}
+	for _, row := range card.Modes {
+		if row.Total != nil {
+			t.Fatalf("%s total should be nil when every weighted metric is unscored: %v", row.Mode, *row.Total)
+		}
+	}
+	trace := cell(t, card, "Hybrid", "Traceability")
+	if trace.Scored || !strings.Contains(trace.Explanation, "tester process") {
+		t.Fatalf("unexpected traceability cell: %+v", trace)
+	}
+	tokens := cell(t, card, "Hybrid", "TokenCost")
+	if tokens.Scored || !strings.Contains(tokens.Explanation, "tester bundle") {
+		t.Fatalf("unexpected token cost cell: %+v", tokens)
+	}
+}
@@ -0,0 +1,58 @@
+package screenshots
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Record pairs a screenshot's metadata with its file stem, e.g. screenshot_001.
+type Record struct {
+	Name     string
+	Metadata Metadata
+}
+
+// ReadDir loads every screenshot_*.json metadata file in dir, ordered by name.
+// A missing directory yields no records.
+func ReadDir(dir string) ([]Record, error) {
+	return ReadMatching(dir, "screenshot_*.json")
+}
+
+// ReadMatching loads the Metadata files in dir whose names match pattern,
+// ordered by name. A missing directory yields no records.
+func ReadMatching(dir, pattern string) ([]Record, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("list screenshot metadata: %w", err)
+	}
+	sort.Strings(paths)
+	out := make([]Record, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read screenshot metadata: %w", err)
+		}
+		var meta Metadata
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", filepath.Base(path), err)
+		}
+		meta.CapturedAt = meta.CapturedAt.UTC()
+		out = append(out, Record{Name: strings.TrimSuffix(filepath.Base(path), ".json"), Metadata: meta})
+	}
+	return out, nil
+}
+
+// Ref returns the shot:mm:ss anchor bundles and model outputs use to cite a
+// frame captured at capturedAt, measured from the run start base.
+func Ref(base, capturedAt time.Time) string {
+	d := capturedAt.Sub(base)
+	if d < 0 {
+		d = 0
+	}
+	total := int(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("shot:%02d:%02d", total/60, total%60)
+}
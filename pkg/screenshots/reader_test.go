@@ -0,0 +1,51 @@
+package screenshots
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadDirLoadsMetadataInNameOrder(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+	for _, name := range []string{"screenshot_002", "screenshot_001"} {
+		data, err := json.Marshal(Metadata{CapturedAt: base, Backend: "synthetic", ImagePath: name + ".png"})
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), data, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	records, err := ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(records) != 2 || records[0].Name != "screenshot_001" || records[1].Metadata.ImagePath != "screenshot_002.png" {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+
+	empty, err := ReadDir(filepath.Join(dir, "missing"))
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("expected no records for a missing directory, got %v %v", empty, err)
+	}
+}
+
+func TestRefFormatsOffsetFromBase(t *testing.T) {
+	base := time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+	cases := map[time.Duration]string{
+		0:                                     "shot:00:00",
+		5*time.Minute + 9600*time.Millisecond: "shot:05:10",
+		75*time.Minute + 3*time.Second:        "shot:75:03",
+		-time.Second:                          "shot:00:00",
+	}
+	for offset, want := range cases {
+		if got := Ref(base, base.Add(offset)); got != want {
+			t.Fatalf("Ref(+%s) = %q, want %q", offset, got, want)
+		}
+	}
+}
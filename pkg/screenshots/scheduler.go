@@ -96,16 +96,29 @@ func (s *Scheduler) Capture(ctx context.Context, destDir string) (Result, error)
 	var firstCapture time.Time
 	var lastCapture time.Time
 
+	// A stop after the first capture keeps the screenshots taken so far.
+	stopped := func() bool {
+		return ctx != nil && ctx.Err() != nil && len(pngFiles) > 0
+	}
 	for i := 0; i < limit; i++ {
 		if ctx != nil && ctx.Err() != nil {
+			if stopped() {
+				break
+			}
 			return Result{}, ctx.Err()
 		}
 		if err := s.waitForNext(ctx, nextCapture); err != nil {
+			if stopped() {
+				break
+			}
 			return Result{}, err
 		}
 
 		capture, err := s.provider.Grab(ctx)
 		if err != nil {
+			if stopped() {
+				break
+			}
 			return Result{}, fmt.Errorf("capture frame: %w", err)
 		}
 		if len(capture.PNG) == 0 {
@@ -143,3 +143,34 @@ func TestSchedulerCaptureCancellation(t *testing.T) {
 		t.Fatalf("expected cancellation error")
 	}
 }
+
+func TestSchedulerKeepsCapturesWhenStopped(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	provider := &fakeProvider{frames: []FrameCapture{{
+		PNG:      []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A},
+		Metadata: Metadata{CapturedAt: base, Backend: "fake"},
+	}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler, err := NewScheduler(Options{
+		Interval:     5 * time.Second,
+		MaxPerMinute: 3,
+		Clock:        func() time.Time { return base },
+		Provider:     provider,
+		Sleeper: func(ctx context.Context, _ time.Duration) error {
+			cancel()
+			return ctx.Err()
+		},
+	})
+	if err != nil {
+		t.Fatalf("new scheduler: %v", err)
+	}
+
+	result, err := scheduler.Capture(ctx, t.TempDir())
+	if err != nil {
+		t.Fatalf("expected a stopped capture to keep its screenshots, got %v", err)
+	}
+	if result.Count != 1 || len(result.MetadataFiles) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
@@ -0,0 +1,155 @@
+// Package sessionize splits a run's fine-grained event stream into sessions
+// separated by idle gaps and persists them to events/sessions.json so later
+// stages share one segmentation.
+package sessionize
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/events"
+)
+
+// DefaultIdleGap splits sessions when no events arrive for longer than this.
+const DefaultIdleGap = 120 * time.Second
+
+// FileName is the sessions document written next to events_fine.jsonl.
+const FileName = "sessions.json"
+
+// Options configure session splitting.
+type Options struct {
+	IdleGap time.Duration
+}
+
+// Session is a run of events with no idle gap longer than the threshold.
+type Session struct {
+	ID       string    `json:"id"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	EventIDs []string  `json:"event_ids"`
+}
+
+// File is the document persisted to events/sessions.json.
+type File struct {
+	IdleGapSeconds float64   `json:"idle_gap_seconds"`
+	EventCount     int       `json:"event_count"`
+	Sessions       []Session `json:"sessions"`
+}
+
+// Sessionizer accumulates events one at a time.
+type Sessionizer struct {
+	idleGap  time.Duration
+	count    int
+	sessions []Session
+}
+
+// New constructs a sessionizer, defaulting the idle gap when unset.
+func New(opts Options) *Sessionizer {
+	gap := opts.IdleGap
+	if gap <= 0 {
+		gap = DefaultIdleGap
+	}
+	return &Sessionizer{idleGap: gap}
+}
+
+// Add records an event. Events are expected in capture order; a timestamp
+// earlier than the current session's end never opens a new session.
+func (s *Sessionizer) Add(id string, ts time.Time) {
+	ts = ts.UTC()
+	s.count++
+	if n := len(s.sessions); n == 0 || ts.Sub(s.sessions[n-1].End) > s.idleGap {
+		s.sessions = append(s.sessions, Session{
+			ID:    sessionID(len(s.sessions) + 1),
+			Start: ts,
+			End:   ts,
+		})
+	}
+	current := &s.sessions[len(s.sessions)-1]
+	current.EventIDs = append(current.EventIDs, id)
+	if ts.Before(current.Start) {
+		current.Start = ts
+	}
+	if ts.After(current.End) {
+		current.End = ts
+	}
+}
+
+// File returns the accumulated sessions.
+func (s *Sessionizer) File() File {
+	sessions := s.sessions
+	if sessions == nil {
+		sessions = []Session{}
+	}
+	return File{
+		IdleGapSeconds: s.idleGap.Seconds(),
+		EventCount:     s.count,
+		Sessions:       sessions,
+	}
+}
+
+// Stream sessionizes a JSONL event stream using the IDs events.Scan reports.
+func Stream(r io.Reader, opts Options) (File, error) {
+	s := New(opts)
+	err := events.Scan(r, func(event events.Event) error {
+		s.Add(event.ID, event.Timestamp)
+		return nil
+	})
+	if err != nil {
+		return File{}, err
+	}
+	return s.File(), nil
+}
+
+// Run sessionizes eventsDir/events_fine.jsonl and writes eventsDir/sessions.json.
+// A missing events file yields an empty session list.
+func Run(eventsDir string, opts Options) (File, error) {
+	var doc File
+	file, err := os.Open(filepath.Join(eventsDir, "events_fine.jsonl"))
+	switch {
+	case err == nil:
+		defer file.Close()
+		doc, err = Stream(file, opts)
+		if err != nil {
+			return File{}, err
+		}
+	case errors.Is(err, os.ErrNotExist):
+		doc = New(opts).File()
+	default:
+		return File{}, fmt.Errorf("open fine events: %w", err)
+	}
+
+	if err := os.MkdirAll(eventsDir, 0o755); err != nil {
+		return File{}, fmt.Errorf("ensure events directory: %w", err)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return File{}, fmt.Errorf("marshal sessions: %w", err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(filepath.Join(eventsDir, FileName), data, 0o644); err != nil {
+		return File{}, fmt.Errorf("write sessions: %w", err)
+	}
+	return doc, nil
+}
+
+// Load reads a sessions.json document.
+func Load(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, fmt.Errorf("read sessions: %w", err)
+	}
+	var doc File
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return File{}, fmt.Errorf("decode sessions: %w", err)
+	}
+	return doc, nil
+}
+
+func sessionID(n int) string {
+	return fmt.Sprintf("session_%03d", n)
+}
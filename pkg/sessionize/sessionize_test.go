@@ -0,0 +1,95 @@
+package sessionize
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func eventLine(ts time.Time) string {
+	return `{"timestamp":"` + ts.Format(time.RFC3339) + `","category":"keyboard","action":"key_down","target":"editor"}` + "\n"
+}
+
+func TestStreamSplitsOnIdleGap(t *testing.T) {
+	base := time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+	var b strings.Builder
+	for _, offset := range []time.Duration{0, 30 * time.Second, 150 * time.Second, 5 * time.Minute, 5*time.Minute + 10*time.Second} {
+		b.WriteString(eventLine(base.Add(offset)))
+	}
+
+	doc, err := Stream(strings.NewReader(b.String()), Options{})
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if doc.IdleGapSeconds != 120 || doc.EventCount != 5 {
+		t.Fatalf("unexpected header: %+v", doc)
+	}
+	want := []Session{
+		{ID: "session_001", Start: base, End: base.Add(150 * time.Second), EventIDs: []string{"evt_0001", "evt_0002", "evt_0003"}},
+		{ID: "session_002", Start: base.Add(5 * time.Minute), End: base.Add(5*time.Minute + 10*time.Second), EventIDs: []string{"evt_0004", "evt_0005"}},
+	}
+	if !reflect.DeepEqual(doc.Sessions, want) {
+		t.Fatalf("sessions mismatch:\n got %+v\nwant %+v", doc.Sessions, want)
+	}
+
+	tight, err := Stream(strings.NewReader(b.String()), Options{IdleGap: 20 * time.Second})
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if len(tight.Sessions) != 4 {
+		t.Fatalf("expected 4 sessions with a 20s gap, got %d", len(tight.Sessions))
+	}
+}
+
+func TestStreamReportsDecodeErrors(t *testing.T) {
+	_, err := Stream(strings.NewReader(eventLine(time.Now())+"{not json\n"), Options{})
+	if err == nil || !strings.Contains(err.Error(), "decode event 2") {
+		t.Fatalf("expected decode error for line 2, got %v", err)
+	}
+}
+
+func TestRunWritesAndLoadsSessions(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2024, 5, 12, 9, 30, 0, 0, time.UTC)
+	if err := os.WriteFile(filepath.Join(dir, "events_fine.jsonl"), []byte(eventLine(base)+eventLine(base.Add(time.Hour))), 0o644); err != nil {
+		t.Fatalf("write events: %v", err)
+	}
+
+	doc, err := Run(dir, Options{})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	loaded, err := Load(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !reflect.DeepEqual(doc, loaded) || len(loaded.Sessions) != 2 {
+		t.Fatalf("unexpected round trip: %+v vs %+v", doc, loaded)
+	}
+}
+
+func TestRunWithoutEventsWritesEmptyList(t *testing.T) {
+	dir := t.TempDir()
+	doc, err := Run(dir, Options{})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if doc.Sessions == nil || len(doc.Sessions) != 0 {
+		t.Fatalf("expected empty session list, got %+v", doc.Sessions)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatalf("read sessions: %v", err)
+	}
+	if !strings.Contains(string(data), `"sessions": []`) {
+		t.Fatalf("expected empty JSON array, got %s", data)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), FileName)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not-exist error, got %v", err)
+	}
+}
@@ -1,2 +1,14 @@
-// Package tokenizer will embed offline BPE vocabularies and helpers.
+// Package tokenizer provides an offline byte-level BPE tokenizer whose
+// vocabularies are embedded at build time, so bundle token budgets are
+// measured in real tokens deterministically without network access. The
+// cl100k_base and o200k_base rank tables are the ones OpenAI publishes for
+// tiktoken at https://openaipublic.blob.core.windows.net/encodings/, so counts
+// match tiktoken's. The llama table is the tokenizer.model file Meta ships
+// with the original Llama 3 weights, which uses the same format and is
+// distributed under the Llama 3 Community License. A Registry exposes an
+// encoder for each family so bundle costs can be compared.
 package tokenizer
+
+//go:generate go run gen.go -sha256 223921b76ee99bde995b7ff738513eef100fb51d18c93597a113bcffe865b2a7 -out vocab/cl100k_base.tiktoken.gz cl100k_base.tiktoken
+//go:generate go run gen.go -sha256 446a9538cb6c348e3516120d7c08b09f57c36495e2acfffe59a5bf8b0cfb1a2d -out vocab/o200k_base.tiktoken.gz o200k_base.tiktoken
+//go:generate go run gen.go -sha256 82e9d31979e92ab929cd544440f129d9ecd797b69e327f80f17e1c50d5551b55 -out vocab/llama3.tiktoken.gz tokenizer.model
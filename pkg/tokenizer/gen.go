@@ -0,0 +1,57 @@
+//go:build ignore
+
+// gen.go checks a published rank table against its SHA-256 digest and writes
+// it gzipped under vocab/, where it is embedded. Regenerate with
+// `go generate ./pkg/tokenizer` after downloading the sources listed in doc.go.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/offlinefirst/limitless-context/pkg/tokenizer"
+)
+
+func main() {
+	out := flag.String("out", "", "Destination .tiktoken.gz file")
+	digest := flag.String("sha256", "", "Expected SHA-256 of the source file")
+	flag.Parse()
+	if *out == "" || flag.NArg() != 1 {
+		log.Fatal("usage: go run gen.go -out vocab/NAME.tiktoken.gz [-sha256 HEX] SOURCE")
+	}
+
+	data, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("read source: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	if *digest != "" && hex.EncodeToString(sum[:]) != *digest {
+		log.Fatalf("%s has SHA-256 %x, want %s", flag.Arg(0), sum, *digest)
+	}
+	tok, err := tokenizer.Parse(flag.Arg(0), bytes.NewReader(data))
+	if err != nil {
+		log.Fatalf("parse %s: %v", flag.Arg(0), err)
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := zw.Write(data); err != nil {
+		log.Fatalf("compress: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		log.Fatalf("compress: %v", err)
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		log.Fatalf("write %s: %v", *out, err)
+	}
+	fmt.Printf("wrote %d tokens to %s\n", tok.VocabSize(), *out)
+}
@@ -0,0 +1,202 @@
+package tokenizer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pretokenize splits text into the chunks BPE merges operate on, exactly as
+// the cl100k_base pattern does:
+//
+//	(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+
+//
+// Go's regexp has no lookahead, so the alternatives are matched by hand and
+// tried in pattern order.
+func Pretokenize(text string) []string {
+	return split(text, nextPiece)
+}
+
+// split cuts text into consecutive pieces whose lengths are reported by next.
+func split(text string, next func(string, int) int) []string {
+	pieces := make([]string, 0, len(text)/4+1)
+	i := 0
+	for i < len(text) {
+		n := next(text, i)
+		pieces = append(pieces, text[i:i+n])
+		i += n
+	}
+	return pieces
+}
+
+// nextPiece returns the byte length of the cl100k piece starting at offset i.
+func nextPiece(text string, i int) int {
+	if n := contraction(text, i); n > 0 {
+		return n
+	}
+	if end := prefixed(text, i, func(j int) int { return scan(text, j, unicode.IsLetter, 0) }); end > i {
+		return end - i
+	}
+	if end := scan(text, i, unicode.IsNumber, 3); end > i {
+		return end - i
+	}
+	if end := symbols(text, i, isNewline); end > i {
+		return end - i
+	}
+	return whitespace(text, i)
+}
+
+// contraction matches (?i:'s|'t|'re|'ve|'m|'ll|'d) at offset i and returns
+// its byte length, or 0.
+func contraction(text string, i int) int {
+	if i >= len(text) || text[i] != '\'' {
+		return 0
+	}
+	lower := strings.ToLower(text[i+1 : min(len(text), i+3)])
+	for _, suffix := range []string{"s", "t", "re", "ve", "m", "ll", "d"} {
+		if strings.HasPrefix(lower, suffix) {
+			return 1 + len(suffix)
+		}
+	}
+	return 0
+}
+
+// prefixed matches [^\r\n\p{L}\p{N}]?body at offset i, trying the optional
+// prefix first as the greedy quantifier does. body reports where its match
+// starting at j ends, or j when it does not match.
+func prefixed(text string, i int, body func(j int) int) int {
+	r, size := utf8.DecodeRuneInString(text[i:])
+	if !isNewline(r) && !unicode.IsLetter(r) && !unicode.IsNumber(r) && i+size < len(text) {
+		if end := body(i + size); end > i+size {
+			return end
+		}
+	}
+	return body(i)
+}
+
+// symbols matches " ?[^\s\p{L}\p{N}]+" followed by any run of trailing
+// characters at offset i, returning where the match ends, or i.
+func symbols(text string, i int, trailing func(rune) bool) int {
+	j := i
+	if text[j] == ' ' {
+		j++
+	}
+	end := scan(text, j, isSymbol, 0)
+	if end == j {
+		return i
+	}
+	return scan(text, end, trailing, 0)
+}
+
+// whitespace matches \s*[\r\n]+, then \s+(?!\S), then \s+ at offset i: a run
+// ending in a newline keeps everything up to its last newline, and otherwise
+// the final space is left to prefix the following piece.
+func whitespace(text string, i int) int {
+	end := scan(text, i, unicode.IsSpace, 0)
+	if end == i {
+		// Unreachable for valid input: every rune is a letter, number,
+		// symbol, or space. Consume one rune so splitting always advances.
+		_, size := utf8.DecodeRuneInString(text[i:])
+		return size
+	}
+	run := text[i:end]
+	if last := strings.LastIndexAny(run, "\r\n"); last >= 0 {
+		return last + 1
+	}
+	if end < len(text) && utf8.RuneCountInString(run) > 1 {
+		_, lastSize := utf8.DecodeLastRuneInString(run)
+		return len(run) - lastSize
+	}
+	return len(run)
+}
+
+// scan advances from i while match holds, stopping after limit runes when limit > 0.
+func scan(text string, i int, match func(rune) bool, limit int) int {
+	count := 0
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !match(r) {
+			break
+		}
+		i += size
+		count++
+		if limit > 0 && count == limit {
+			break
+		}
+	}
+	return i
+}
+
+func isSymbol(r rune) bool {
+	return !unicode.IsSpace(r) && !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+func isNewline(r rune) bool {
+	return r == '\r' || r == '\n'
+}
+
+// pretokenizeO200k splits text as the o200k_base pattern does:
+//
+//	[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?
+//	|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?
+//	|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+(?!\S)|\s+
+//
+// Words therefore break where lower case turns to upper case, keep a
+// trailing contraction, and may absorb one leading symbol.
+func pretokenizeO200k(text string) []string {
+	return split(text, nextPieceO200k)
+}
+
+func nextPieceO200k(text string, i int) int {
+	if end := prefixed(text, i, func(j int) int { return casedWord(text, j, true) }); end > i {
+		return end - i
+	}
+	if end := prefixed(text, i, func(j int) int { return casedWord(text, j, false) }); end > i {
+		return end - i
+	}
+	if end := scan(text, i, unicode.IsNumber, 3); end > i {
+		return end - i
+	}
+	if end := symbols(text, i, func(r rune) bool { return isNewline(r) || r == '/' }); end > i {
+		return end - i
+	}
+	return whitespace(text, i)
+}
+
+// casedWord matches an upper-case run and a lower-case run starting at j,
+// plus an optional contraction, returning where the word ends, or j. With
+// lowerRequired the runs are [upper]*[lower]+, backtracking the upper run
+// until a lower-case rune follows; otherwise they are [upper]+[lower]*.
+// Modifier letters, other letters, and marks belong to both runs.
+func casedWord(text string, j int, lowerRequired bool) int {
+	upperEnd := scan(text, j, isUpperRun, 0)
+	var end int
+	if lowerRequired {
+		end = j
+		for k := upperEnd; ; {
+			if r, _ := utf8.DecodeRuneInString(text[k:]); k < len(text) && isLowerRun(r) {
+				end = scan(text, k, isLowerRun, 0)
+				break
+			}
+			if k == j {
+				return j
+			}
+			_, size := utf8.DecodeLastRuneInString(text[j:k])
+			k -= size
+		}
+	} else {
+		if upperEnd == j {
+			return j
+		}
+		end = scan(text, upperEnd, isLowerRun, 0)
+	}
+	return end + contraction(text, end)
+}
+
+func isUpperRun(r rune) bool {
+	return unicode.In(r, unicode.Lu, unicode.Lt, unicode.Lm, unicode.Lo, unicode.M)
+}
+
+func isLowerRun(r rune) bool {
+	return unicode.In(r, unicode.Ll, unicode.Lm, unicode.Lo, unicode.M)
+}
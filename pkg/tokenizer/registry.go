@@ -0,0 +1,135 @@
+package tokenizer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultName is the tokenizer used for budgets when none is configured.
+const DefaultName = "cl100k"
+
+// Factory builds a tokenizer the first time it is looked up.
+type Factory func() (*Tokenizer, error)
+
+// Registry maps tokenizer names to lazily built encoders.
+type Registry struct {
+	mu        sync.Mutex
+	factories map[string]Factory
+	built     map[string]*Tokenizer
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[string]Factory),
+		built:     make(map[string]*Tokenizer),
+	}
+}
+
+// Register adds a named factory. Names are case-insensitive and must be unique.
+func (r *Registry) Register(name string, factory Factory) error {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" {
+		return errors.New("tokenizer name must not be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("tokenizer %q: factory must not be nil", key)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.factories[key]; exists {
+		return fmt.Errorf("tokenizer %q already registered", key)
+	}
+	r.factories[key] = factory
+	return nil
+}
+
+// Has reports whether name is registered.
+func (r *Registry) Has(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.factories[strings.ToLower(strings.TrimSpace(name))]
+	return ok
+}
+
+// Names lists the registered tokenizers in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the named tokenizer, building it on first use.
+func (r *Registry) Lookup(name string) (*Tokenizer, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tok, ok := r.built[key]; ok {
+		return tok, nil
+	}
+	factory, ok := r.factories[key]
+	if !ok {
+		known := make([]string, 0, len(r.factories))
+		for name := range r.factories {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		return nil, fmt.Errorf("unknown tokenizer %q (known: %s)", name, strings.Join(known, ", "))
+	}
+	tok, err := factory()
+	if err != nil {
+		return nil, fmt.Errorf("build tokenizer %q: %w", key, err)
+	}
+	r.built[key] = tok
+	return tok, nil
+}
+
+var (
+	defaultRegistryOnce sync.Once
+	defaultRegistry     *Registry
+)
+
+// DefaultRegistry returns the registry of built-in model families, each
+// backed by its model's published vocabulary:
+//
+//   - cl100k: OpenAI's cl100k_base, used by GPT-4 and GPT-3.5.
+//   - o200k: OpenAI's o200k_base, used by GPT-4o. Words absorb one leading
+//     symbol and break on case changes.
+//   - llama: Meta's Llama 3 vocabulary, shared by Llama 3.1 and 3.2. It
+//     extends cl100k_base to 128,000 tokens and splits text the same way.
+//
+// Special tokens such as <|endoftext|> are not recognised; they encode as
+// ordinary text.
+func DefaultRegistry() *Registry {
+	defaultRegistryOnce.Do(func() {
+		defaultRegistry = NewRegistry()
+		for name, factory := range map[string]Factory{
+			"cl100k": func() (*Tokenizer, error) { return family("cl100k", "cl100k_base.tiktoken.gz", Pretokenize) },
+			"o200k":  func() (*Tokenizer, error) { return family("o200k", "o200k_base.tiktoken.gz", pretokenizeO200k) },
+			"llama":  func() (*Tokenizer, error) { return family("llama", "llama3.tiktoken.gz", Pretokenize) },
+		} {
+			if err := defaultRegistry.Register(name, factory); err != nil {
+				panic(err)
+			}
+		}
+	})
+	return defaultRegistry
+}
+
+// family builds a tokenizer from the embedded rank table in file.
+func family(name, file string, splitter func(string) []string) (*Tokenizer, error) {
+	tok, err := loadEmbedded(name, file)
+	if err != nil {
+		return nil, err
+	}
+	tok.split = splitter
+	return tok, nil
+}
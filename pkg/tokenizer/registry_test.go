@@ -0,0 +1,93 @@
+package tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefaultRegistryFamilies(t *testing.T) {
+	reg := DefaultRegistry()
+	if got, want := reg.Names(), []string{"cl100k", "llama", "o200k"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Names() = %v, want %v", got, want)
+	}
+	if Default().Name() != DefaultName {
+		t.Fatalf("Default() = %q, want %q", Default().Name(), DefaultName)
+	}
+
+	text := "tester bundle --run 20240512_093000"
+	for _, name := range reg.Names() {
+		tok, err := reg.Lookup(name)
+		if err != nil {
+			t.Fatalf("Lookup(%q): %v", name, err)
+		}
+		if tok.Name() != name {
+			t.Fatalf("Lookup(%q) returned %q", name, tok.Name())
+		}
+		decoded, err := tok.Decode(tok.Encode(text))
+		if err != nil || decoded != text {
+			t.Fatalf("%s round trip = %q, %v", name, decoded, err)
+		}
+	}
+
+	again, err := reg.Lookup("CL100K")
+	if err != nil || again != Default() {
+		t.Fatalf("expected case-insensitive lookup to return the cached tokenizer")
+	}
+}
+
+func TestFamiliesMatchPublishedEncodings(t *testing.T) {
+	cases := []struct {
+		family    string
+		vocabSize int
+		text      string
+		want      []int
+	}{
+		{"o200k", 199998, "hello world", []int{24912, 2375}},
+		{"o200k", 199998, "The capture harness records a single offline session.", []int{976, 19374, 52139, 11722, 261, 4590, 33467, 6223, 13}},
+		{"o200k", 199998, "tester bundle --run 20240512_093000", []int{153819, 21739, 2230, 12935, 220, 1323, 29818, 899, 62, 43001, 1302}},
+		{"o200k", 199998, "naïve café 日本語 🙂", []int{1503, 9954, 737, 30469, 17428, 40909, 26192}},
+		{"llama", 128000, "hello world", []int{15339, 1917}},
+		{"llama", 128000, "00000", []int{931, 410}},
+		{"llama", 128000, "In 2024 there are 366 days", []int{644, 220, 2366, 19, 1070, 527, 220, 18044, 2919}},
+		{"llama", 128000, "naïve café 日本語 🙂", []int{3458, 38672, 588, 53050, 105180, 102158, 28584}},
+	}
+	for _, tc := range cases {
+		tok, err := DefaultRegistry().Lookup(tc.family)
+		if err != nil {
+			t.Fatalf("Lookup(%q): %v", tc.family, err)
+		}
+		if got := tok.VocabSize(); got != tc.vocabSize {
+			t.Fatalf("%s VocabSize() = %d, want %d", tc.family, got, tc.vocabSize)
+		}
+		if got := tok.Encode(tc.text); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s Encode(%q) = %v, want %v", tc.family, tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestRegistryRejectsUnknownAndDuplicates(t *testing.T) {
+	reg := NewRegistry()
+	factory := func() (*Tokenizer, error) { return Default(), nil }
+	if err := reg.Register("tiny", factory); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := reg.Register("Tiny", factory); err == nil {
+		t.Fatalf("expected duplicate registration to fail")
+	}
+	if err := reg.Register(" ", factory); err == nil {
+		t.Fatalf("expected empty name to fail")
+	}
+	if _, err := reg.Lookup("gpt2"); err == nil {
+		t.Fatalf("expected unknown tokenizer error")
+	}
+	if !reg.Has("tiny") || reg.Has("gpt2") {
+		t.Fatalf("Has reported unexpected membership")
+	}
+}
+
+func TestPretokenizeO200k(t *testing.T) {
+	text := "camelCase HTTPServer don't 12345"
+	if got, want := pretokenizeO200k(text), []string{"camel", "Case", " HTTPServer", " don't", " ", "123", "45"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("o200k pieces = %q, want %q", got, want)
+	}
+}
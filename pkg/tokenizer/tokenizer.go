@@ -0,0 +1,233 @@
+package tokenizer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// vocabFS holds the published rank tables, gzipped to keep the binary small.
+//
+//go:embed vocab/*.tiktoken.gz
+var vocabFS embed.FS
+
+// Tokenizer is a deterministic byte-level BPE encoder over a rank table in
+// the tiktoken format. Each token is a byte sequence whose rank is both its id
+// and its merge priority: adjacent parts are merged lowest rank first.
+type Tokenizer struct {
+	name  string
+	ranks map[string]int
+	vocab [][]byte
+	split func(string) []string
+}
+
+// Default returns the DefaultName tokenizer from the default registry.
+func Default() *Tokenizer {
+	tok, err := DefaultRegistry().Lookup(DefaultName)
+	if err != nil {
+		panic(fmt.Sprintf("tokenizer: embedded vocabulary is invalid: %v", err))
+	}
+	return tok
+}
+
+// Encode tokenizes text with the default tokenizer.
+func Encode(text string) []int { return Default().Encode(text) }
+
+// Decode reverses Encode with the default tokenizer.
+func Decode(ids []int) (string, error) { return Default().Decode(ids) }
+
+// Count reports the number of tokens text encodes to with the default tokenizer.
+func Count(text string) int { return Default().Count(text) }
+
+// New constructs a tokenizer whose token ids index vocab. Every single byte
+// must be a token so any input can be encoded, and tokens must be unique.
+func New(name string, vocab [][]byte) (*Tokenizer, error) {
+	t := &Tokenizer{
+		name:  name,
+		ranks: make(map[string]int, len(vocab)),
+		vocab: make([][]byte, len(vocab)),
+		split: Pretokenize,
+	}
+	for id, token := range vocab {
+		if len(token) == 0 {
+			return nil, fmt.Errorf("token %d is empty", id)
+		}
+		if _, dup := t.ranks[string(token)]; dup {
+			return nil, fmt.Errorf("token %d duplicates %q", id, token)
+		}
+		t.ranks[string(token)] = id
+		t.vocab[id] = append([]byte(nil), token...)
+	}
+	for b := 0; b < 256; b++ {
+		if _, ok := t.ranks[string([]byte{byte(b)})]; !ok {
+			return nil, fmt.Errorf("vocabulary has no token for byte 0x%02x", b)
+		}
+	}
+	return t, nil
+}
+
+// Parse reads a rank table in the tiktoken format: one base64 token and its
+// rank per line. Ranks must cover 0..n-1 without gaps.
+func Parse(name string, r io.Reader) (*Tokenizer, error) {
+	var vocab [][]byte
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected a token and a rank", lineNo)
+		}
+		token, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid token %q", lineNo, fields[0])
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil || rank < 0 {
+			return nil, fmt.Errorf("line %d: invalid rank %q", lineNo, fields[1])
+		}
+		for len(vocab) <= rank {
+			vocab = append(vocab, nil)
+		}
+		if vocab[rank] != nil {
+			return nil, fmt.Errorf("line %d: rank %d assigned twice", lineNo, rank)
+		}
+		vocab[rank] = token
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read ranks: %w", err)
+	}
+	if len(vocab) == 0 {
+		return nil, errors.New("rank table is empty")
+	}
+	for rank, token := range vocab {
+		if token == nil {
+			return nil, fmt.Errorf("rank %d is missing", rank)
+		}
+	}
+	return New(name, vocab)
+}
+
+// WriteRanks serialises vocab in the format accepted by Parse.
+func WriteRanks(w io.Writer, vocab [][]byte) error {
+	bw := bufio.NewWriter(w)
+	for rank, token := range vocab {
+		if _, err := fmt.Fprintf(bw, "%s %d\n", base64.StdEncoding.EncodeToString(token), rank); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// loadEmbedded parses the named gzipped rank table from vocab/.
+func loadEmbedded(name, file string) (*Tokenizer, error) {
+	data, err := vocabFS.ReadFile("vocab/" + file)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	defer zr.Close()
+	return Parse(name, zr)
+}
+
+// Name identifies the vocabulary in metrics output.
+func (t *Tokenizer) Name() string { return t.name }
+
+// VocabSize reports the number of distinct token ids.
+func (t *Tokenizer) VocabSize() int { return len(t.vocab) }
+
+// Encode converts text to token ids.
+func (t *Tokenizer) Encode(text string) []int {
+	ids := make([]int, 0, len(text)/3+1)
+	for _, piece := range t.split(text) {
+		ids = t.encodePiece(piece, ids)
+	}
+	return ids
+}
+
+// Count reports the number of tokens text encodes to.
+func (t *Tokenizer) Count(text string) int {
+	total := 0
+	var ids []int
+	for _, piece := range t.split(text) {
+		ids = t.encodePiece(piece, ids[:0])
+		total += len(ids)
+	}
+	return total
+}
+
+// Decode converts token ids back to text.
+func (t *Tokenizer) Decode(ids []int) (string, error) {
+	var b strings.Builder
+	for _, id := range ids {
+		if id < 0 || id >= len(t.vocab) {
+			return "", fmt.Errorf("token id %d out of range", id)
+		}
+		b.Write(t.vocab[id])
+	}
+	return b.String(), nil
+}
+
+// encodePiece appends the ids of a single pre-tokenized chunk to ids. Starting
+// from single bytes, it repeatedly merges the adjacent parts whose joined
+// bytes have the lowest rank, leftmost first, as tiktoken does.
+func (t *Tokenizer) encodePiece(piece string, ids []int) []int {
+	if id, ok := t.ranks[piece]; ok {
+		return append(ids, id)
+	}
+	// starts[k] is where part k begins; rank[k] is the rank of part k joined
+	// with part k+1, or math.MaxInt when that is not a token.
+	starts := make([]int, len(piece)+1)
+	rank := make([]int, len(piece)+1)
+	for k := range starts {
+		starts[k] = k
+	}
+	pairRank := func(k int) int {
+		if k+2 >= len(starts) {
+			return math.MaxInt
+		}
+		if id, ok := t.ranks[piece[starts[k]:starts[k+2]]]; ok {
+			return id
+		}
+		return math.MaxInt
+	}
+	for k := range rank {
+		rank[k] = pairRank(k)
+	}
+	for {
+		best := -1
+		for k := 0; k+1 < len(starts); k++ {
+			if rank[k] != math.MaxInt && (best < 0 || rank[k] < rank[best]) {
+				best = k
+			}
+		}
+		if best < 0 {
+			break
+		}
+		starts = append(starts[:best+1], starts[best+2:]...)
+		rank = append(rank[:best+1], rank[best+2:]...)
+		rank[best] = pairRank(best)
+		if best > 0 {
+			rank[best-1] = pairRank(best - 1)
+		}
+	}
+	for k := 0; k+1 < len(starts); k++ {
+		ids = append(ids, t.ranks[piece[starts[k]:starts[k+1]]])
+	}
+	return ids
+}
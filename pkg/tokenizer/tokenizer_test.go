@@ -0,0 +1,136 @@
+package tokenizer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The expected ids come from tiktoken's cl100k_base encoding.
+func TestEncodeMatchesCL100K(t *testing.T) {
+	cases := []struct {
+		text string
+		want []int
+	}{
+		{"", []int{}},
+		{"hello world", []int{15339, 1917}},
+		{"The capture harness records a single offline session.", []int{791, 12602, 33508, 7576, 264, 3254, 27258, 3882, 13}},
+		{"tester bundle --run 20240512_093000", []int{74458, 13190, 1198, 6236, 220, 2366, 16408, 717, 62, 25202, 931}},
+		{`{"id":"evt_0001","category":"keyboard"}`, []int{5018, 307, 3332, 29834, 62, 931, 16, 2247, 5588, 3332, 42813, 9388}},
+		{"naïve café 日本語 🙂", []int{3458, 38672, 588, 53050, 76502, 22656, 45918, 252, 28584}},
+	}
+	for _, tc := range cases {
+		if got := Encode(tc.text); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Encode(%q) = %v, want %v", tc.text, got, tc.want)
+		}
+		if got := Count(tc.text); got != len(tc.want) {
+			t.Errorf("Count(%q) = %d, want %d", tc.text, got, len(tc.want))
+		}
+	}
+	if got := Default().VocabSize(); got != 100256 {
+		t.Fatalf("VocabSize() = %d, want 100256", got)
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	inputs := []string{
+		"Drafting email to [REDACTED] about rollout",
+		"   leading spaces\n\n\ttabs and trailing  ",
+		"It's they'll we've I'M",
+		"mixed 12345678 digits, ünïcödé, and emoji 🙂🙃",
+		string([]byte{0xff, 0xfe, 'a'}),
+	}
+	for _, input := range inputs {
+		decoded, err := Decode(Encode(input))
+		if err != nil {
+			t.Fatalf("decode %q: %v", input, err)
+		}
+		if decoded != input {
+			t.Fatalf("round trip mismatch: %q != %q", decoded, input)
+		}
+	}
+}
+
+func TestEncodeIsDeterministic(t *testing.T) {
+	text := strings.Repeat("Sessionize events by idle gap and cluster tasks. ", 20)
+	first := Encode(text)
+	for i := 0; i < 5; i++ {
+		if !reflect.DeepEqual(first, Encode(text)) {
+			t.Fatalf("encoding differed on iteration %d", i)
+		}
+	}
+	if len(first) >= len(text)/2 {
+		t.Fatalf("expected merges to compress repeated prose, got %d tokens for %d bytes", len(first), len(text))
+	}
+}
+
+func TestDecodeRejectsUnknownIDs(t *testing.T) {
+	if _, err := Decode([]int{Default().VocabSize()}); err == nil {
+		t.Fatalf("expected error for out-of-range id")
+	}
+	if _, err := Decode([]int{-1}); err == nil {
+		t.Fatalf("expected error for negative id")
+	}
+}
+
+func TestPretokenize(t *testing.T) {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"Hi  there, it's 2024!\n\n  ok", []string{"Hi", " ", " there", ",", " it", "'s", " ", "202", "4", "!\n\n", " ", " ok"}},
+		{"\tindent \"quoted\" ('x')", []string{"\tindent", " \"", "quoted", "\"", " ('", "x", "')"}},
+		{"a \r\n\tb  ", []string{"a", " \r\n", "\tb", "  "}},
+	}
+	for _, tc := range cases {
+		got := Pretokenize(tc.text)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("Pretokenize(%q):\n got %q\nwant %q", tc.text, got, tc.want)
+		}
+		if joined := strings.Join(got, ""); joined != tc.text {
+			t.Fatalf("pieces must cover the input, got %q", joined)
+		}
+	}
+}
+
+func TestParseAndWriteRanksRoundTrip(t *testing.T) {
+	vocab := make([][]byte, 0, 258)
+	for b := 0; b < 256; b++ {
+		vocab = append(vocab, []byte{byte(b)})
+	}
+	vocab = append(vocab, []byte("lo"), []byte(" low"))
+
+	var buf bytes.Buffer
+	if err := WriteRanks(&buf, vocab); err != nil {
+		t.Fatalf("write ranks: %v", err)
+	}
+	tok, err := Parse("test", &buf)
+	if err != nil {
+		t.Fatalf("parse ranks: %v", err)
+	}
+	if tok.VocabSize() != 258 {
+		t.Fatalf("unexpected vocab size %d", tok.VocabSize())
+	}
+	// " low" is a token but cannot be reached by merging, so it is used
+	// only when the whole piece matches.
+	if got := tok.Encode(" low lows"); !reflect.DeepEqual(got, []int{257, ' ', 256, 'w', 's'}) {
+		t.Fatalf("unexpected encoding %v", got)
+	}
+}
+
+func TestParseRejectsMalformedRanks(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"YQ==\n",
+		"YQ== x\n",
+		"not-base64 0\n",
+		"YQ== 0\nYQ== 1\n",
+		"YQ== 0\nYg== 2\n",
+		"YQ== 0\n",
+	} {
+		if _, err := Parse("bad", strings.NewReader(input)); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package video
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// AVI layout constants. Sizes are chunk payloads, excluding the 8-byte
+// chunk header.
+const (
+	aviMainHeaderSize   = 56
+	aviStreamHeaderSize = 56
+	aviBitmapInfoSize   = 40
+	aviIndexEntrySize   = 16
+	aviFlagHasIndex     = 0x10
+	aviFlagKeyFrame     = 0x10
+)
+
+// writeMJPEGAVI writes frames, each a complete JPEG image, as a single-stream
+// Motion JPEG AVI playing at fps frames per second.
+func writeMJPEGAVI(w io.Writer, width, height, fps int, frames [][]byte) error {
+	if width <= 0 || height <= 0 || fps <= 0 {
+		return errors.New("avi dimensions and frame rate must be positive")
+	}
+	if len(frames) == 0 {
+		return errors.New("avi requires at least one frame")
+	}
+
+	moviSize := 4
+	maxFrame := 0
+	for _, frame := range frames {
+		moviSize += 8 + padded(len(frame))
+		if len(frame) > maxFrame {
+			maxFrame = len(frame)
+		}
+	}
+	strlSize := 4 + 8 + aviStreamHeaderSize + 8 + aviBitmapInfoSize
+	hdrlSize := 4 + 8 + aviMainHeaderSize + 8 + strlSize
+	idxSize := aviIndexEntrySize * len(frames)
+	riffSize := 4 + 8 + hdrlSize + 8 + moviSize + 8 + idxSize
+
+	b := &aviBuffer{w: bufio.NewWriter(w)}
+	b.fourCC("RIFF")
+	b.u32(riffSize)
+	b.fourCC("AVI ")
+
+	b.fourCC("LIST")
+	b.u32(hdrlSize)
+	b.fourCC("hdrl")
+	b.fourCC("avih")
+	b.u32(aviMainHeaderSize)
+	b.u32(1000000 / fps) // microseconds per frame
+	b.u32(maxFrame * fps)
+	b.u32(0)
+	b.u32(aviFlagHasIndex)
+	b.u32(len(frames))
+	b.u32(0)
+	b.u32(1) // streams
+	b.u32(maxFrame)
+	b.u32(width)
+	b.u32(height)
+	for i := 0; i < 4; i++ {
+		b.u32(0)
+	}
+
+	b.fourCC("LIST")
+	b.u32(strlSize)
+	b.fourCC("strl")
+	b.fourCC("strh")
+	b.u32(aviStreamHeaderSize)
+	b.fourCC("vids")
+	b.fourCC("MJPG")
+	b.u32(0)
+	b.u16(0)
+	b.u16(0)
+	b.u32(0)
+	b.u32(1)   // scale
+	b.u32(fps) // rate: rate/scale frames per second
+	b.u32(0)
+	b.u32(len(frames))
+	b.u32(maxFrame)
+	b.bytes([]byte{0xFF, 0xFF, 0xFF, 0xFF}) // default quality
+	b.u32(0)
+	b.u16(0)
+	b.u16(0)
+	b.u16(width)
+	b.u16(height)
+	b.fourCC("strf")
+	b.u32(aviBitmapInfoSize)
+	b.u32(aviBitmapInfoSize)
+	b.u32(width)
+	b.u32(height)
+	b.u16(1)
+	b.u16(24)
+	b.fourCC("MJPG")
+	b.u32(width * height * 3)
+	for i := 0; i < 4; i++ {
+		b.u32(0)
+	}
+
+	b.fourCC("LIST")
+	b.u32(moviSize)
+	b.fourCC("movi")
+	for _, frame := range frames {
+		b.fourCC("00dc")
+		b.u32(len(frame))
+		b.bytes(frame)
+		if len(frame)%2 == 1 {
+			b.bytes([]byte{0})
+		}
+	}
+
+	// Index offsets are relative to the "movi" list type.
+	b.fourCC("idx1")
+	b.u32(idxSize)
+	offset := 4
+	for _, frame := range frames {
+		b.fourCC("00dc")
+		b.u32(aviFlagKeyFrame)
+		b.u32(offset)
+		b.u32(len(frame))
+		offset += 8 + padded(len(frame))
+	}
+
+	if b.err != nil {
+		return b.err
+	}
+	return b.w.Flush()
+}
+
+func padded(n int) int {
+	return n + n%2
+}
+
+// aviBuffer writes little-endian fields, keeping the first error.
+type aviBuffer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (b *aviBuffer) bytes(p []byte) {
+	if b.err == nil {
+		_, b.err = b.w.Write(p)
+	}
+}
+
+func (b *aviBuffer) fourCC(code string) {
+	b.bytes([]byte(code))
+}
+
+func (b *aviBuffer) u32(v int) {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], uint32(v))
+	b.bytes(buf[:])
+}
+
+func (b *aviBuffer) u16(v int) {
+	var buf [2]byte
+	binary.LittleEndian.PutUint16(buf[:], uint16(v))
+	b.bytes(buf[:])
+}
@@ -0,0 +1,126 @@
+package video
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/jpeg"
+	"testing"
+)
+
+// aviChunk is a parsed RIFF chunk; lists carry their type and children.
+type aviChunk struct {
+	id       string
+	listType string
+	offset   int
+	data     []byte
+	children []aviChunk
+}
+
+func parseRIFF(t *testing.T, data []byte, offset int) []aviChunk {
+	t.Helper()
+	var chunks []aviChunk
+	for pos := 0; pos < len(data); {
+		if len(data)-pos < 8 {
+			t.Fatalf("truncated chunk header at %d", offset+pos)
+		}
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		if pos+8+size > len(data) {
+			t.Fatalf("chunk %s at %d overruns its parent (%d bytes)", id, offset+pos, size)
+		}
+		chunk := aviChunk{id: id, offset: offset + pos, data: data[pos+8 : pos+8+size]}
+		if id == "RIFF" || id == "LIST" {
+			chunk.listType = string(chunk.data[:4])
+			chunk.children = parseRIFF(t, chunk.data[4:], offset+pos+12)
+		}
+		chunks = append(chunks, chunk)
+		pos += 8 + padded(size)
+	}
+	return chunks
+}
+
+func findChunk(chunks []aviChunk, id string) *aviChunk {
+	for i := range chunks {
+		if chunks[i].id == id || (chunks[i].id == "LIST" && chunks[i].listType == id) {
+			return &chunks[i]
+		}
+	}
+	return nil
+}
+
+// checkAVI validates the structure written by writeMJPEGAVI and returns the
+// frame count and dimensions from the main header.
+func checkAVI(t *testing.T, data []byte) (frames, width, height int) {
+	t.Helper()
+	top := parseRIFF(t, data, 0)
+	if len(top) != 1 || top[0].id != "RIFF" || top[0].listType != "AVI " {
+		t.Fatalf("expected a single RIFF AVI chunk, got %+v", top)
+	}
+	root := top[0].children
+	hdrl, movi, idx1 := findChunk(root, "hdrl"), findChunk(root, "movi"), findChunk(root, "idx1")
+	if hdrl == nil || movi == nil || idx1 == nil {
+		t.Fatalf("missing hdrl, movi, or idx1")
+	}
+	avih := findChunk(hdrl.children, "avih")
+	strl := findChunk(hdrl.children, "strl")
+	if avih == nil || len(avih.data) != aviMainHeaderSize || strl == nil {
+		t.Fatalf("missing or malformed avih/strl")
+	}
+	if strh := findChunk(strl.children, "strh"); strh == nil || string(strh.data[:8]) != "vidsMJPG" {
+		t.Fatalf("expected an MJPG video stream header")
+	}
+	frames = int(binary.LittleEndian.Uint32(avih.data[16:20]))
+	width = int(binary.LittleEndian.Uint32(avih.data[32:36]))
+	height = int(binary.LittleEndian.Uint32(avih.data[36:40]))
+
+	if len(movi.children) != frames || len(idx1.data) != frames*aviIndexEntrySize {
+		t.Fatalf("expected %d frames, movi has %d and idx1 has %d entries", frames, len(movi.children), len(idx1.data)/aviIndexEntrySize)
+	}
+	moviStart := movi.offset + 8
+	for i, frame := range movi.children {
+		entry := idx1.data[i*aviIndexEntrySize:]
+		if frame.id != "00dc" || string(entry[:4]) != "00dc" {
+			t.Fatalf("frame %d has id %q", i, frame.id)
+		}
+		if off := int(binary.LittleEndian.Uint32(entry[8:12])); moviStart+off != frame.offset {
+			t.Fatalf("frame %d index offset %d does not match chunk at %d", i, off, frame.offset-moviStart)
+		}
+		if size := int(binary.LittleEndian.Uint32(entry[12:16])); size != len(frame.data) {
+			t.Fatalf("frame %d index size %d, chunk size %d", i, size, len(frame.data))
+		}
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(frame.data))
+		if err != nil || cfg.Width != width || cfg.Height != height {
+			t.Fatalf("frame %d is not a %dx%d JPEG: %+v, %v", i, width, height, cfg, err)
+		}
+	}
+	return frames, width, height
+}
+
+func TestWriteMJPEGAVIStructure(t *testing.T) {
+	frames := make([][]byte, 3)
+	for i := range frames {
+		frame, err := syntheticFrame(syntheticWidth, syntheticHeight, syntheticBase, i, len(frames))
+		if err != nil {
+			t.Fatalf("syntheticFrame returned error: %v", err)
+		}
+		frames[i] = frame
+	}
+	// Force an odd-sized chunk to exercise padding; JPEG decoders ignore
+	// trailing bytes.
+	if len(frames[1])%2 == 0 {
+		frames[1] = append(frames[1], 0)
+	}
+
+	var buf bytes.Buffer
+	if err := writeMJPEGAVI(&buf, syntheticWidth, syntheticHeight, 2, frames); err != nil {
+		t.Fatalf("writeMJPEGAVI returned error: %v", err)
+	}
+	count, width, height := checkAVI(t, buf.Bytes())
+	if count != 3 || width != syntheticWidth || height != syntheticHeight {
+		t.Fatalf("unexpected header: %d frames %dx%d", count, width, height)
+	}
+
+	if err := writeMJPEGAVI(&buf, syntheticWidth, syntheticHeight, 2, nil); err == nil {
+		t.Fatalf("expected an error without frames")
+	}
+}
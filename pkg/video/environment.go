@@ -13,8 +13,17 @@ const (
 	ProviderScreenCaptureKit = "screencapturekit"
 	ProviderAVFoundation     = "avfoundation"
 	ProviderStub             = "stub"
+	ProviderSynthetic        = "synthetic"
 )
 
+// backendEnv names the environment variable that overrides the recorder
+// backend.
+const backendEnv = "LIMITLESS_VIDEO_BACKEND"
+
+func backendOverride() string {
+	return strings.ToLower(strings.TrimSpace(os.Getenv(backendEnv)))
+}
+
 // Permission states for downstream tooling.
 const (
 	PermissionNotApplicable = "not_applicable"
@@ -31,7 +40,15 @@ type Environment struct {
 
 // DetectEnvironment reports the recorder backend and availability for the host platform.
 func DetectEnvironment() Environment {
-	backend := strings.ToLower(strings.TrimSpace(os.Getenv("LIMITLESS_VIDEO_BACKEND")))
+	backend := backendOverride()
+	if backend == ProviderSynthetic {
+		return Environment{
+			Provider:   ProviderSynthetic,
+			Available:  true,
+			Permission: PermissionNotApplicable,
+			Message:    "synthetic Motion JPEG recorder selected via " + backendEnv,
+		}
+	}
 	screenRecording := permissions.ProbeScreenRecording(nil)
 
 	env := Environment{
@@ -0,0 +1,410 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/offlinefirst/limitless-context/pkg/screenshots"
+)
+
+// FramesDirName is the keyframe directory inside the video directory.
+const FramesDirName = "frames"
+
+// KeyframeBackend is the backend recorded in keyframe metadata.
+const KeyframeBackend = "video_keyframe"
+
+// keyframePattern matches keyframe metadata files.
+const keyframePattern = "frame_*.json"
+
+// Scene detection compares frames as small grayscale thumbnails so noise and
+// compression artefacts average out.
+const (
+	thumbWidth  = 32
+	thumbHeight = 18
+)
+
+// KeyframeOptions configure ExtractKeyframes.
+type KeyframeOptions struct {
+	// Interval is the longest gap between kept frames within a segment.
+	Interval time.Duration
+	// SceneThreshold keeps a frame whose mean brightness difference from
+	// the last kept frame is at least this fraction (0-1). Zero disables
+	// scene detection.
+	SceneThreshold float64
+	// FFmpegBinary decodes containers other than Motion JPEG AVI. It
+	// defaults to "ffmpeg".
+	FFmpegBinary string
+	LookPath     func(string) (string, error)
+}
+
+// KeyframeResult lists the frames written by ExtractKeyframes.
+type KeyframeResult struct {
+	Dir           string
+	Files         []string
+	MetadataFiles []string
+	SceneChanges  int
+	// Skipped notes segments that could not be decoded.
+	Skipped []string
+}
+
+// ExtractKeyframes samples frames from every segment in videoDir's index and
+// writes them to videoDir/frames as PNG files with screenshots.Metadata
+// sidecars, so they can be fed to the OCR worker like screenshots. Each
+// segment keeps its first frame, any frame that differs from the last kept
+// one by SceneThreshold, and otherwise one frame per Interval. Segments
+// that cannot be decoded are noted in Skipped rather than failing the run.
+func ExtractKeyframes(ctx context.Context, videoDir string, opts KeyframeOptions) (KeyframeResult, error) {
+	if opts.Interval <= 0 {
+		return KeyframeResult{}, errors.New("keyframe interval must be positive")
+	}
+	if opts.SceneThreshold < 0 || opts.SceneThreshold > 1 {
+		return KeyframeResult{}, errors.New("scene threshold must be between 0 and 1")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	idx, err := LoadIndex(filepath.Join(videoDir, IndexName))
+	if err != nil {
+		return KeyframeResult{}, err
+	}
+
+	dir := filepath.Join(videoDir, FramesDirName)
+	if err := os.RemoveAll(dir); err != nil {
+		return KeyframeResult{}, fmt.Errorf("clear keyframe directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return KeyframeResult{}, fmt.Errorf("ensure keyframe directory: %w", err)
+	}
+
+	x := &extractor{opts: opts, result: KeyframeResult{Dir: dir}}
+	for _, segment := range idx.Segments {
+		if err := ctx.Err(); err != nil {
+			return KeyframeResult{}, err
+		}
+		if err := x.segment(ctx, segment); err != nil {
+			if ctx.Err() != nil {
+				return KeyframeResult{}, ctx.Err()
+			}
+			var skip skipError
+			if !errors.As(err, &skip) {
+				return KeyframeResult{}, err
+			}
+			x.result.Skipped = append(x.result.Skipped, fmt.Sprintf("%s: %v", filepath.Base(segment.File), skip.err))
+		}
+	}
+	return x.result, nil
+}
+
+// ReadKeyframes loads the keyframe metadata under videoDir, ordered by name.
+func ReadKeyframes(videoDir string) ([]screenshots.Record, error) {
+	return screenshots.ReadMatching(filepath.Join(videoDir, FramesDirName), keyframePattern)
+}
+
+// skipError marks a segment that cannot be decoded; extraction moves on.
+type skipError struct {
+	err error
+}
+
+func (e skipError) Error() string { return e.err.Error() }
+
+// extractor carries selection state across segments so frame names stay
+// unique within a run.
+type extractor struct {
+	opts   KeyframeOptions
+	result KeyframeResult
+}
+
+// segment decodes one segment and writes the frames it selects.
+func (x *extractor) segment(ctx context.Context, segment Segment) error {
+	var (
+		last     []uint8
+		lastKept time.Duration
+		kept     int
+	)
+	visit := func(offset time.Duration, img image.Image) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d := segment.Duration(); d > 0 && offset >= d {
+			return nil
+		}
+		thumb := thumbnail(img)
+		reason := ""
+		switch {
+		case kept == 0:
+			reason = "segment start"
+		case x.opts.SceneThreshold > 0 && difference(last, thumb) >= x.opts.SceneThreshold:
+			reason = "scene change"
+			x.result.SceneChanges++
+		case offset-lastKept >= x.opts.Interval:
+			reason = "interval"
+		default:
+			return nil
+		}
+		if err := x.write(segment, offset, img, reason); err != nil {
+			return err
+		}
+		last, lastKept = thumb, offset
+		kept++
+		return nil
+	}
+
+	if strings.EqualFold(filepath.Ext(segment.File), ".avi") {
+		return decodeAVI(segment.File, visit)
+	}
+	return x.decodeFFmpeg(ctx, segment.File, visit)
+}
+
+func (x *extractor) write(segment Segment, offset time.Duration, img image.Image, reason string) error {
+	name := fmt.Sprintf("frame_%04d", len(x.result.Files)+1)
+	imagePath := filepath.Join(x.result.Dir, name+".png")
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("encode keyframe %q: %w", name, err)
+	}
+	if err := os.WriteFile(imagePath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write keyframe %q: %w", name, err)
+	}
+
+	bounds := img.Bounds()
+	meta := screenshots.Metadata{
+		CapturedAt: segment.Started.Add(offset).UTC(),
+		Backend:    KeyframeBackend,
+		Width:      bounds.Dx(),
+		Height:     bounds.Dy(),
+		ImagePath:  filepath.Base(imagePath),
+		Notes:      []string{fmt.Sprintf("%s of %s at +%s", reason, filepath.Base(segment.File), offset.Round(time.Second))},
+	}
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal keyframe metadata %q: %w", name, err)
+	}
+	metaPath := filepath.Join(x.result.Dir, name+".json")
+	if err := os.WriteFile(metaPath, data, 0o644); err != nil {
+		return fmt.Errorf("write keyframe metadata %q: %w", name, err)
+	}
+	x.result.Files = append(x.result.Files, imagePath)
+	x.result.MetadataFiles = append(x.result.MetadataFiles, metaPath)
+	return nil
+}
+
+// decodeAVI visits about one frame per second of a Motion JPEG AVI without
+// loading the whole file.
+func decodeAVI(path string, visit func(time.Duration, image.Image) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return skipError{err}
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return skipError{err}
+	}
+	perFrame, frames, err := readAVIIndex(file, info.Size())
+	if err != nil {
+		return skipError{err}
+	}
+	stride := int(math.Round(float64(time.Second) / float64(perFrame)))
+	if stride < 1 {
+		stride = 1
+	}
+	for i := 0; i < len(frames); i += stride {
+		data := make([]byte, frames[i].size)
+		if _, err := file.ReadAt(data, frames[i].offset); err != nil {
+			return skipError{fmt.Errorf("read frame %d: %w", i, err)}
+		}
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return skipError{fmt.Errorf("decode frame %d: %w", i, err)}
+		}
+		if err := visit(time.Duration(i)*perFrame, img); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type aviFrame struct {
+	offset int64
+	size   int
+}
+
+// readAVIIndex walks the RIFF chunks of an AVI file and returns the frame
+// duration from the main header and the location of every compressed video
+// frame in the movi list.
+func readAVIIndex(r io.ReaderAt, size int64) (time.Duration, []aviFrame, error) {
+	header := make([]byte, 12)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return 0, nil, fmt.Errorf("read avi header: %w", err)
+	}
+	if string(header[:4]) != "RIFF" || string(header[8:12]) != "AVI " {
+		return 0, nil, errors.New("not an avi file")
+	}
+	end := 8 + int64(binary.LittleEndian.Uint32(header[4:8]))
+	if end > size {
+		// Truncated recordings keep whatever frames were written.
+		end = size
+	}
+
+	var perFrame time.Duration
+	var frames []aviFrame
+	var walk func(start, end int64, inMovi bool) error
+	walk = func(start, end int64, inMovi bool) error {
+		for pos := start; pos+8 <= end; {
+			chunk := make([]byte, 12)
+			n, err := r.ReadAt(chunk, pos)
+			if n < 8 {
+				return fmt.Errorf("read chunk at %d: %w", pos, err)
+			}
+			id := string(chunk[:4])
+			body := pos + 8
+			length := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+			bodyEnd := body + length
+			if bodyEnd > end {
+				bodyEnd = end
+			}
+			switch {
+			case id == "LIST" && n == 12:
+				listType := string(chunk[8:12])
+				if listType == "hdrl" || listType == "movi" || listType == "rec " {
+					if err := walk(body+4, bodyEnd, inMovi || listType == "movi"); err != nil {
+						return err
+					}
+				}
+			case id == "avih" && bodyEnd-body >= 4:
+				field := make([]byte, 4)
+				if _, err := r.ReadAt(field, body); err != nil {
+					return fmt.Errorf("read avi main header: %w", err)
+				}
+				perFrame = time.Duration(binary.LittleEndian.Uint32(field)) * time.Microsecond
+			case inMovi && strings.HasSuffix(id, "dc") && body+length <= end:
+				frames = append(frames, aviFrame{offset: body, size: int(length)})
+			}
+			pos = body + length + length%2
+		}
+		return nil
+	}
+	if err := walk(12, end, false); err != nil {
+		return 0, nil, err
+	}
+	if perFrame <= 0 {
+		return 0, nil, errors.New("avi main header missing frame rate")
+	}
+	if len(frames) == 0 {
+		return 0, nil, errors.New("avi has no video frames")
+	}
+	return perFrame, frames, nil
+}
+
+// decodeFFmpeg has ffmpeg dump one JPEG per second of path into a temporary
+// directory and visits them in order.
+func (x *extractor) decodeFFmpeg(ctx context.Context, path string, visit func(time.Duration, image.Image) error) error {
+	name := strings.TrimSpace(x.opts.FFmpegBinary)
+	if name == "" {
+		name = "ffmpeg"
+	}
+	lookPath := x.opts.LookPath
+	if lookPath == nil {
+		lookPath = exec.LookPath
+	}
+	resolved, err := lookPath(name)
+	if err != nil {
+		return skipError{fmt.Errorf("%s not found; install it to extract keyframes from %s segments", name, strings.TrimPrefix(filepath.Ext(path), "."))}
+	}
+
+	tmp, err := os.MkdirTemp("", "keyframes-")
+	if err != nil {
+		return fmt.Errorf("create keyframe scratch directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	cmd := exec.CommandContext(ctx, resolved, "-nostdin", "-v", "error", "-i", path, "-vf", "fps=1", "-q:v", "3", filepath.Join(tmp, "%06d.jpg"))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return skipError{fmt.Errorf("ffmpeg: %v: %s", err, strings.TrimSpace(string(out)))}
+	}
+	paths, err := filepath.Glob(filepath.Join(tmp, "*.jpg"))
+	if err != nil {
+		return fmt.Errorf("list decoded frames: %w", err)
+	}
+	sort.Strings(paths)
+	for i, framePath := range paths {
+		data, err := os.ReadFile(framePath)
+		if err != nil {
+			return fmt.Errorf("read decoded frame: %w", err)
+		}
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return skipError{fmt.Errorf("decode frame %d: %w", i, err)}
+		}
+		if err := visit(time.Duration(i)*time.Second, img); err != nil {
+			return err
+		}
+	}
+	if len(paths) == 0 {
+		return skipError{errors.New("ffmpeg produced no frames")}
+	}
+	return nil
+}
+
+// thumbnail reduces img to thumbWidth x thumbHeight brightness values,
+// averaging a grid of samples per cell.
+func thumbnail(img image.Image) []uint8 {
+	const samples = 4
+	bounds := img.Bounds()
+	ycc, _ := img.(*image.YCbCr)
+	out := make([]uint8, thumbWidth*thumbHeight)
+	for ty := 0; ty < thumbHeight; ty++ {
+		for tx := 0; tx < thumbWidth; tx++ {
+			sum := 0
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					x := bounds.Min.X + ((tx*samples+sx)*2+1)*bounds.Dx()/(2*thumbWidth*samples)
+					y := bounds.Min.Y + ((ty*samples+sy)*2+1)*bounds.Dy()/(2*thumbHeight*samples)
+					if ycc != nil {
+						sum += int(ycc.Y[ycc.YOffset(x, y)])
+					} else {
+						sum += int(color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y)
+					}
+				}
+			}
+			out[ty*thumbWidth+tx] = uint8(sum / (samples * samples))
+		}
+	}
+	return out
+}
+
+// difference returns the mean absolute difference of two thumbnails as a
+// fraction of full brightness.
+func difference(a, b []uint8) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 1
+	}
+	total := 0
+	for i := range a {
+		d := int(a[i]) - int(b[i])
+		if d < 0 {
+			d = -d
+		}
+		total += d
+	}
+	return float64(total) / float64(255*len(a))
+}
@@ -0,0 +1,200 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func solidJPEG(t *testing.T, gray uint8) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 64, 36))
+	fill(img, img.Bounds(), color.RGBA{R: gray, G: gray, B: gray, A: 255})
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// writeSegmentFixture writes data as a segment file and indexes it.
+func writeSegmentFixture(t *testing.T, dir, name string, data []byte, length time.Duration) {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		t.Fatalf("write segment: %v", err)
+	}
+	idx := Index{SchemaVersion: IndexVersion, Format: strings.TrimPrefix(filepath.Ext(name), "."), ChunkSeconds: 300, Segments: []Segment{
+		{File: file, Started: syntheticBase, Ended: syntheticBase.Add(length)},
+	}}
+	if err := SaveIndex(filepath.Join(dir, IndexName), idx); err != nil {
+		t.Fatalf("save index: %v", err)
+	}
+}
+
+func TestExtractKeyframesFromSyntheticSegments(t *testing.T) {
+	t.Setenv(backendEnv, "synthetic")
+	clock, sleeper := steppingClock(syntheticBase)
+	recorder, err := NewRecorder(Options{
+		ChunkSeconds: 5,
+		Format:       "mp4",
+		Clock:        clock,
+		Sleeper:      sleeper,
+		Deadline:     syntheticBase.Add(8 * time.Second),
+		FPS:          2,
+		Width:        160,
+		Height:       90,
+	})
+	if err != nil {
+		t.Fatalf("new recorder: %v", err)
+	}
+	dir := t.TempDir()
+	if _, err := recorder.Record(context.Background(), dir); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	result, err := ExtractKeyframes(context.Background(), dir, KeyframeOptions{Interval: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("ExtractKeyframes returned error: %v", err)
+	}
+	if len(result.Files) != 5 || len(result.MetadataFiles) != 5 || len(result.Skipped) != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.Files[4] != filepath.Join(dir, FramesDirName, "frame_0005.png") {
+		t.Fatalf("unexpected frame path %s", result.Files[4])
+	}
+	img, err := png.Decode(bytes.NewReader(mustRead(t, result.Files[0])))
+	if err != nil || img.Bounds().Dx() != 160 || img.Bounds().Dy() != 90 {
+		t.Fatalf("expected a 160x90 png: %v", err)
+	}
+
+	records, err := ReadKeyframes(dir)
+	if err != nil {
+		t.Fatalf("ReadKeyframes returned error: %v", err)
+	}
+	if len(records) != 5 {
+		t.Fatalf("expected 5 keyframe records, got %d", len(records))
+	}
+	for i, offset := range []time.Duration{0, 2, 4, 5, 7} {
+		meta := records[i].Metadata
+		if !meta.CapturedAt.Equal(syntheticBase.Add(offset*time.Second)) || meta.Backend != KeyframeBackend || meta.Width != 160 || meta.ImagePath != records[i].Name+".png" {
+			t.Fatalf("frame %d has unexpected metadata: %+v", i, meta)
+		}
+	}
+	if note := records[3].Metadata.Notes[0]; note != "segment start of segment_0002_20240201T120005.avi at +0s" {
+		t.Fatalf("unexpected note %q", note)
+	}
+}
+
+func TestExtractKeyframesDetectsSceneChanges(t *testing.T) {
+	frames := make([][]byte, 0, 6)
+	for _, gray := range []uint8{20, 22, 20, 220, 221, 220} {
+		frames = append(frames, solidJPEG(t, gray))
+	}
+	var buf bytes.Buffer
+	if err := writeMJPEGAVI(&buf, 64, 36, 1, frames); err != nil {
+		t.Fatalf("writeMJPEGAVI returned error: %v", err)
+	}
+	dir := t.TempDir()
+	writeSegmentFixture(t, dir, "segment.avi", buf.Bytes(), 6*time.Second)
+
+	result, err := ExtractKeyframes(context.Background(), dir, KeyframeOptions{Interval: time.Hour, SceneThreshold: 0.2})
+	if err != nil {
+		t.Fatalf("ExtractKeyframes returned error: %v", err)
+	}
+	if len(result.Files) != 2 || result.SceneChanges != 1 {
+		t.Fatalf("expected the first frame and one scene change, got %+v", result)
+	}
+	records, err := ReadKeyframes(dir)
+	if err != nil || len(records) != 2 || !records[1].Metadata.CapturedAt.Equal(syntheticBase.Add(3*time.Second)) {
+		t.Fatalf("unexpected keyframes: %+v, %v", records, err)
+	}
+
+	// A recording cut off mid-frame still yields the frames before the cut.
+	truncated := buf.Bytes()[:buf.Len()-len(frames[5])-aviIndexEntrySize*len(frames)]
+	writeSegmentFixture(t, dir, "segment.avi", truncated, 6*time.Second)
+	result, err = ExtractKeyframes(context.Background(), dir, KeyframeOptions{Interval: time.Hour, SceneThreshold: 0.2})
+	if err != nil || len(result.Files) != 2 || len(result.Skipped) != 0 {
+		t.Fatalf("unexpected result for a truncated segment: %+v, %v", result, err)
+	}
+}
+
+func TestExtractKeyframesSkipsUndecodableSegments(t *testing.T) {
+	dir := t.TempDir()
+	writeSegmentFixture(t, dir, "segment.mp4", []byte("not a video"), time.Minute)
+
+	result, err := ExtractKeyframes(context.Background(), dir, KeyframeOptions{
+		Interval: time.Second,
+		LookPath: func(string) (string, error) { return "", errors.New("not found") },
+	})
+	if err != nil {
+		t.Fatalf("ExtractKeyframes returned error: %v", err)
+	}
+	if len(result.Files) != 0 || len(result.Skipped) != 1 || !strings.Contains(result.Skipped[0], "ffmpeg not found") {
+		t.Fatalf("expected the segment to be skipped, got %+v", result)
+	}
+
+	writeSegmentFixture(t, dir, "segment.avi", []byte("RIFF"), time.Minute)
+	result, err = ExtractKeyframes(context.Background(), dir, KeyframeOptions{Interval: time.Second})
+	if err != nil || len(result.Skipped) != 1 {
+		t.Fatalf("expected a corrupt avi to be skipped, got %+v, %v", result, err)
+	}
+
+	if _, err := ExtractKeyframes(context.Background(), t.TempDir(), KeyframeOptions{Interval: time.Second}); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected a missing index error, got %v", err)
+	}
+	if _, err := ExtractKeyframes(context.Background(), dir, KeyframeOptions{}); err == nil {
+		t.Fatalf("expected an error without an interval")
+	}
+}
+
+func TestExtractKeyframesUsesFFmpegForOtherContainers(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ffmpeg is a shell script")
+	}
+	dir := t.TempDir()
+	writeSegmentFixture(t, dir, "segment.mp4", []byte("mp4"), 3*time.Second)
+
+	fixture := filepath.Join(t.TempDir(), "frame.jpg")
+	if err := os.WriteFile(fixture, solidJPEG(t, 128), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	// The fake copies the fixture into the output pattern's directory as
+	// three one-second frames.
+	script := "#!/bin/sh\nfor last; do :; done\nout=$(dirname \"$last\")\nfor n in 1 2 3; do cp " + fixture + " \"$out/00000$n.jpg\"; done\n"
+	binary := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(binary, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake ffmpeg: %v", err)
+	}
+
+	result, err := ExtractKeyframes(context.Background(), dir, KeyframeOptions{
+		Interval:     2 * time.Second,
+		FFmpegBinary: binary,
+		LookPath:     func(name string) (string, error) { return name, nil },
+	})
+	if err != nil {
+		t.Fatalf("ExtractKeyframes returned error: %v", err)
+	}
+	if len(result.Files) != 2 || len(result.Skipped) != 0 {
+		t.Fatalf("expected frames at 0s and 2s, got %+v", result)
+	}
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return data
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -15,13 +16,61 @@ type Options struct {
 	ChunkSeconds int
 	Format       string
 	Clock        func() time.Time
+	// Deadline, when set, ends recording once segments reach it; the last
+	// segment is shortened to fit.
+	Deadline time.Time
+	// Wait is called before each segment and blocks while capture is paused.
+	// An error ends recording.
+	Wait func(context.Context) error
+	// Pauses receives when capture pauses and cuts the current segment short.
+	Pauses <-chan struct{}
+	// FPS, Width, and Height request a frame rate and size from backends
+	// that support them. Zero leaves the backend default.
+	FPS    int
+	Width  int
+	Height int
+	// Backend names the provider recorded in segment metadata.
+	Backend string
+	// Sleeper waits for the next frame in backends that pace themselves to
+	// Clock. It defaults to a timer that returns early when ctx ends.
+	Sleeper func(context.Context, time.Duration) error
 }
 
-// Result summarises recorder output.
+// Settings are the frame rate and frame size a backend records at.
+type Settings struct {
+	FPS    int
+	Width  int
+	Height int
+}
+
+// configurable is implemented by backends that honour requested Settings.
+// Configure returns the settings the backend will record at.
+type configurable interface {
+	Configure(Settings) Settings
+}
+
+// rendering is implemented by backends that know how much video they wrote,
+// which the clock cannot measure exactly: the synthetic backend renders ahead
+// of real time, and the macOS stream starts after setup. LastDuration reports
+// how much video the most recent Record call wrote.
+type rendering interface {
+	LastDuration() time.Duration
+}
+
+// pacing is implemented by backends that render frames themselves and wait
+// for each frame's time on the recorder's clock.
+type pacing interface {
+	Pace(clock func() time.Time, sleeper func(context.Context, time.Duration) error)
+}
+
+// Result summarises recorder output. File, Started, and Ended describe the
+// first segment and the span of the whole recording.
 type Result struct {
-	File    string
-	Started time.Time
-	Ended   time.Time
+	File      string
+	Started   time.Time
+	Ended     time.Time
+	Segments  []Segment
+	IndexPath string
 }
 
 // Recorder coordinates platform specific capture implementations.
@@ -29,6 +78,12 @@ type Recorder struct {
 	chunkDuration time.Duration
 	format        string
 	clock         func() time.Time
+	deadline      time.Time
+	wait          func(context.Context) error
+	pauses        <-chan struct{}
+	backend       string
+	// settings is zero when the backend does not report what it records at.
+	settings Settings
 
 	native NativeRecorder
 }
@@ -77,16 +132,38 @@ func NewRecorder(opts Options) (*Recorder, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Backends that always write one container name segments after it.
+	if container, ok := native.(interface{ ContainerFormat() string }); ok {
+		format = container.ContainerFormat()
+	}
+	var settings Settings
+	if backend, ok := native.(configurable); ok {
+		settings = backend.Configure(Settings{FPS: opts.FPS, Width: opts.Width, Height: opts.Height})
+	}
+	if backend, ok := native.(pacing); ok {
+		sleeper := opts.Sleeper
+		if sleeper == nil {
+			sleeper = defaultSleeper
+		}
+		backend.Pace(clock, sleeper)
+	}
 
 	return &Recorder{
 		chunkDuration: time.Duration(opts.ChunkSeconds) * time.Second,
 		format:        format,
 		clock:         clock,
+		deadline:      opts.Deadline,
+		wait:          opts.Wait,
+		pauses:        opts.Pauses,
+		backend:       opts.Backend,
+		settings:      settings,
 		native:        native,
 	}, nil
 }
 
-// Record captures a single segment to the destination directory.
+// Record captures consecutive segments of ChunkSeconds into destDir until
+// ctx ends, Wait fails, or the deadline is reached, rewriting the segment
+// index after each one. A stop after the first segment is not an error.
 func (r *Recorder) Record(ctx context.Context, destDir string) (Result, error) {
 	if destDir == "" {
 		return Result{}, errors.New("destination directory must not be empty")
@@ -94,23 +171,153 @@ func (r *Recorder) Record(ctx context.Context, destDir string) (Result, error) {
 	if err := os.MkdirAll(destDir, 0o755); err != nil {
 		return Result{}, fmt.Errorf("ensure destination: %w", err)
 	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	indexPath := filepath.Join(destDir, IndexName)
+	var segments []Segment
+	for {
+		if err := ctx.Err(); err != nil {
+			if len(segments) == 0 {
+				return Result{}, err
+			}
+			break
+		}
+		if r.wait != nil {
+			if err := r.wait(ctx); err != nil {
+				if len(segments) == 0 {
+					return Result{}, err
+				}
+				break
+			}
+		}
+		r.drainPauses()
 
-	started := r.clock().UTC()
-	filename := fmt.Sprintf("segment_%s.%s", started.Format("20060102T150405"), r.format)
+		// Segments never overlap, even when the clock lags the recorder.
+		started := r.clock().UTC()
+		if n := len(segments); n > 0 && started.Before(segments[n-1].Ended) {
+			started = segments[n-1].Ended
+		}
+		length := r.chunkDuration
+		if !r.deadline.IsZero() {
+			remaining := r.deadline.Sub(started)
+			if remaining <= 0 {
+				break
+			}
+			if remaining < length {
+				length = remaining
+			}
+		}
 
-	if ctx != nil && ctx.Err() != nil {
-		return Result{}, ctx.Err()
+		segment, ok, err := r.recordSegment(ctx, destDir, len(segments)+1, started, length)
+		if err != nil {
+			return Result{}, err
+		}
+		if !ok {
+			continue
+		}
+		sidecar, err := r.writeSidecar(segment)
+		if err != nil {
+			return Result{}, err
+		}
+		segment.Sidecar = sidecar
+		segments = append(segments, segment)
+		if err := SaveIndex(indexPath, Index{SchemaVersion: IndexVersion, Format: r.format, ChunkSeconds: int(r.chunkDuration / time.Second), Segments: segments}); err != nil {
+			return Result{}, err
+		}
 	}
 
-	file, err := r.native.Record(ctx, destDir, filename, started, r.chunkDuration)
+	if len(segments) == 0 {
+		return Result{}, nil
+	}
+	return Result{
+		File:      segments[0].File,
+		Started:   segments[0].Started,
+		Ended:     segments[len(segments)-1].Ended,
+		Segments:  segments,
+		IndexPath: indexPath,
+	}, nil
+}
+
+// recordSegment records segment number seq. A segment cut short by a pause or
+// stop is kept, marked truncated, when the backend left a file behind; ok is
+// false when it did not. The sequence number keeps names unique when two
+// segments start within the same second.
+func (r *Recorder) recordSegment(ctx context.Context, destDir string, seq int, started time.Time, length time.Duration) (Segment, bool, error) {
+	filename := fmt.Sprintf("segment_%04d_%s.%s", seq, started.Format("20060102T150405"), r.format)
+
+	segCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if r.pauses != nil {
+		go func() {
+			select {
+			case <-r.pauses:
+				cancel()
+			case <-segCtx.Done():
+			}
+		}()
+	}
+
+	file, err := r.native.Record(segCtx, destDir, filename, started, length)
 	if err != nil {
-		return Result{}, err
+		if segCtx.Err() == nil {
+			return Segment{}, false, err
+		}
+		file = filepath.Join(destDir, filename)
+		if _, statErr := os.Stat(file); statErr != nil {
+			return Segment{}, false, nil
+		}
+	}
+	return Segment{File: file, Started: started, Ended: r.segmentEnd(started, length), Truncated: err != nil}, true, nil
+}
+
+// segmentEnd measures when a segment that started at started actually ended,
+// within its requested length.
+func (r *Recorder) segmentEnd(started time.Time, length time.Duration) time.Time {
+	ended := r.clock().UTC()
+	if backend, ok := r.native.(rendering); ok {
+		ended = started.Add(backend.LastDuration())
+	}
+	switch {
+	case ended.Before(started):
+		ended = started
+	case ended.After(started.Add(length)):
+		ended = started.Add(length)
+	}
+	return ended
+}
+
+func (r *Recorder) drainPauses() {
+	if r.pauses == nil {
+		return
+	}
+	for {
+		select {
+		case <-r.pauses:
+		default:
+			return
+		}
 	}
+}
 
-	ended := started.Add(r.chunkDuration)
-	return Result{File: file, Started: started, Ended: ended}, nil
+func defaultSleeper(ctx context.Context, wait time.Duration) error {
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 func defaultNativeFactory(format string) (NativeRecorder, error) {
+	if backendOverride() == ProviderSynthetic {
+		return newSyntheticRecorder()
+	}
 	return newNativeRecorder(format)
 }
@@ -23,9 +23,14 @@ import (
 
 const permissionErrorPrefix = "SCREEN_RECORDING_PERMISSION_REQUIRED:"
 
+// macDefaultFPS is the frame rate used when none is requested.
+const macDefaultFPS = 60
+
 type macRecorder struct {
-	format string
-	mu     sync.Mutex
+	format   string
+	mu       sync.Mutex
+	settings Settings
+	last     time.Duration
 }
 
 func newNativeRecorder(format string) (NativeRecorder, error) {
@@ -38,9 +43,38 @@ func newNativeRecorder(format string) (NativeRecorder, error) {
 	return &macRecorder{format: format}, nil
 }
 
+// Configure records at the requested frame rate and size. Zero values use
+// 60 fps and the main display's size at the time of the call.
+func (m *macRecorder) Configure(requested Settings) Settings {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var width, height C.int
+	C.recorder_main_display_size(&width, &height)
+	settings := Settings{FPS: macDefaultFPS, Width: int(width), Height: int(height)}
+	if requested.FPS > 0 {
+		settings.FPS = requested.FPS
+	}
+	if requested.Width > 0 && requested.Height > 0 {
+		settings.Width, settings.Height = requested.Width, requested.Height
+	}
+	m.settings = settings
+	return settings
+}
+
+// LastDuration reports the span of frames the last Record call wrote. The
+// stream starts after setup and only delivers frames when the screen
+// changes, so this can be shorter than the time Record took.
+func (m *macRecorder) LastDuration() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.last
+}
+
 func (m *macRecorder) Record(ctx context.Context, dest string, filename string, started time.Time, duration time.Duration) (string, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.last = 0
 
 	absDir, err := filepath.Abs(dest)
 	if err != nil {
@@ -56,10 +90,19 @@ func (m *macRecorder) Record(ctx context.Context, dest string, filename string,
 	cPath := C.CString(absFile)
 	defer C.free(unsafe.Pointer(cPath))
 
+	settings := C.recorder_settings{
+		fps:    C.int(m.settings.FPS),
+		width:  C.int(m.settings.Width),
+		height: C.int(m.settings.Height),
+	}
+	// recorded is written by the native call, which has returned by the
+	// time any path below does.
+	var recorded C.double
+	defer func() { m.last = time.Duration(float64(recorded) * float64(time.Second)) }()
 	errCh := make(chan error, 1)
 	go func() {
 		var cerr *C.char
-		rc := C.recorder_record_screen(cPath, C.double(durationSeconds), &cerr)
+		rc := C.recorder_record_screen(cPath, C.double(durationSeconds), settings, &recorded, &cerr)
 		if cerr != nil {
 			errMsg := C.GoString(cerr)
 			C.recorder_free_string(cerr)
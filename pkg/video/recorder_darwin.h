@@ -5,8 +5,17 @@
 extern "C" {
 #endif
 
+// recorder_settings requests a frame rate and output size. Zero values use
+// 60 fps and the main display's size.
+typedef struct {
+    int fps;
+    int width;
+    int height;
+} recorder_settings;
+
 int recorder_initialize(void);
-int recorder_record_screen(const char *path, double duration, char **error_out);
+void recorder_main_display_size(int *width, int *height);
+int recorder_record_screen(const char *path, double duration, recorder_settings settings, double *recorded_out, char **error_out);
 void recorder_cancel_active(void);
 void recorder_free_string(char *ptr);
 
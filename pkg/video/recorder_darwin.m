@@ -14,6 +14,7 @@
 @property(nonatomic, strong) AVAssetWriter *writer;
 @property(nonatomic, strong) AVAssetWriterInput *videoInput;
 @property(nonatomic) CMTime startTime;
+@property(nonatomic) CMTime lastTime;
 @property(nonatomic) BOOL started;
 @property(nonatomic) NSTimeInterval duration;
 @property(nonatomic) dispatch_semaphore_t finishSemaphore;
@@ -44,6 +45,7 @@
     NSDictionary *settings = @{AVVideoCodecKey: AVVideoCodecTypeH264,
                                AVVideoWidthKey: @(MAX(1.0, size.width)),
                                AVVideoHeightKey: @(MAX(1.0, size.height)),
+                               AVVideoScalingModeKey: AVVideoScalingModeResizeAspect,
                                AVVideoCompressionPropertiesKey: compression};
 
     _writer = [AVAssetWriter assetWriterWithURL:url fileType:AVFileTypeMPEG4 error:error];
@@ -131,8 +133,18 @@
                                                                       code:-3
                                                                   userInfo:@{NSLocalizedDescriptionKey : @"Failed to append sample buffer"}];
             [self finishWithError:error];
+            return;
         }
+        self.lastTime = relative;
+    }
+}
+
+// recordedDuration is the span between the first and last frames written.
+- (NSTimeInterval)recordedDuration {
+    if (!self.started || !CMTIME_IS_VALID(self.lastTime)) {
+        return 0;
     }
+    return MAX(0.0, CMTimeGetSeconds(CMTimeSubtract(self.lastTime, self.startTime)));
 }
 
 - (void)stream:(SCStream *)stream didOutputSampleBuffer:(CMSampleBufferRef)sampleBuffer ofType:(SCStreamOutputType)type API_AVAILABLE(macos(12.3)) {
@@ -165,16 +177,27 @@
 @property(nonatomic) dispatch_semaphore_t stopSemaphore;
 @end
 
+static CGSize recorder_output_size(recorder_settings settings, CGSize display) {
+    if (settings.width > 0 && settings.height > 0) {
+        return CGSizeMake(settings.width, settings.height);
+    }
+    return display;
+}
+
+static CMTime recorder_frame_interval(recorder_settings settings) {
+    return CMTimeMake(1, settings.fps > 0 ? settings.fps : 60);
+}
+
 @implementation RecorderController
 
-- (BOOL)recordToURL:(NSURL *)url duration:(NSTimeInterval)duration error:(NSError **)error {
+- (BOOL)recordToURL:(NSURL *)url duration:(NSTimeInterval)duration settings:(recorder_settings)settings error:(NSError **)error {
     if (@available(macOS 12.3, *)) {
-        return [self recordWithScreenCaptureKitToURL:url duration:duration error:error];
+        return [self recordWithScreenCaptureKitToURL:url duration:duration settings:settings error:error];
     }
-    return [self recordWithAVFoundationToURL:url duration:duration error:error];
+    return [self recordWithAVFoundationToURL:url duration:duration settings:settings error:error];
 }
 
-- (BOOL)recordWithScreenCaptureKitToURL:(NSURL *)url duration:(NSTimeInterval)duration error:(NSError **)error API_AVAILABLE(macos(12.3)) {
+- (BOOL)recordWithScreenCaptureKitToURL:(NSURL *)url duration:(NSTimeInterval)duration settings:(recorder_settings)settings error:(NSError **)error API_AVAILABLE(macos(12.3)) {
     __block SCShareableContent *content = nil;
     __block NSError *contentError = nil;
     dispatch_semaphore_t sema = dispatch_semaphore_create(0);
@@ -209,7 +232,7 @@
         return NO;
     }
 
-    CGSize size = CGSizeMake(display.width, display.height);
+    CGSize size = recorder_output_size(settings, CGSizeMake(display.width, display.height));
     NSError *writerError = nil;
     self.writer = [[RecorderSampleWriter alloc] initWithURL:url duration:duration size:size error:&writerError];
     if (!self.writer) {
@@ -234,7 +257,7 @@
     SCStreamConfiguration *configuration = [[SCStreamConfiguration alloc] init];
     configuration.width = size.width;
     configuration.height = size.height;
-    configuration.minimumFrameInterval = CMTimeMake(1, 60);
+    configuration.minimumFrameInterval = recorder_frame_interval(settings);
     configuration.queueDepth = 8;
     configuration.showsCursor = YES;
     configuration.colorSpaceName = kCGColorSpaceSRGB;
@@ -295,9 +318,9 @@
     dispatch_semaphore_signal(self.stopSemaphore);
 }
 
-- (BOOL)recordWithAVFoundationToURL:(NSURL *)url duration:(NSTimeInterval)duration error:(NSError **)error {
+- (BOOL)recordWithAVFoundationToURL:(NSURL *)url duration:(NSTimeInterval)duration settings:(recorder_settings)settings error:(NSError **)error {
     CGDirectDisplayID displayID = CGMainDisplayID();
-    CGSize size = CGSizeMake(CGDisplayPixelsWide(displayID), CGDisplayPixelsHigh(displayID));
+    CGSize size = recorder_output_size(settings, CGSizeMake(CGDisplayPixelsWide(displayID), CGDisplayPixelsHigh(displayID)));
 
     NSError *writerError = nil;
     self.writer = [[RecorderSampleWriter alloc] initWithURL:url duration:duration size:size error:&writerError];
@@ -323,7 +346,7 @@
     self.session.sessionPreset = AVCaptureSessionPresetHigh;
 
     AVCaptureScreenInput *screenInput = [[AVCaptureScreenInput alloc] initWithDisplayID:displayID];
-    screenInput.minFrameDuration = CMTimeMake(1, 60);
+    screenInput.minFrameDuration = recorder_frame_interval(settings);
     screenInput.capturesCursor = YES;
     screenInput.capturesMouseClicks = YES;
 
@@ -432,8 +455,21 @@ int recorder_initialize(void) {
     }
 }
 
-int recorder_record_screen(const char *path, double duration, char **error_out) {
+void recorder_main_display_size(int *width, int *height) {
+    CGDirectDisplayID displayID = CGMainDisplayID();
+    if (width) {
+        *width = (int)CGDisplayPixelsWide(displayID);
+    }
+    if (height) {
+        *height = (int)CGDisplayPixelsHigh(displayID);
+    }
+}
+
+int recorder_record_screen(const char *path, double duration, recorder_settings settings, double *recorded_out, char **error_out) {
     @autoreleasepool {
+        if (recorded_out) {
+            *recorded_out = 0;
+        }
         if (!gStateQueue) {
             gStateQueue = dispatch_queue_create("com.limitless-context.recorder.state", DISPATCH_QUEUE_SERIAL);
         }
@@ -456,7 +492,10 @@ int recorder_record_screen(const char *path, double duration, char **error_out)
         }
 
         NSError *error = nil;
-        BOOL success = [controller recordToURL:url duration:duration error:&error];
+        BOOL success = [controller recordToURL:url duration:duration settings:settings error:&error];
+        if (recorded_out) {
+            *recorded_out = [controller.writer recordedDuration];
+        }
 
         dispatch_sync(gStateQueue, ^{
             gActiveController = nil;
@@ -28,7 +28,7 @@ func TestRecorderWritesSegment(t *testing.T) {
 	t.Cleanup(func() { SetNativeFactory(nil) })
 
 	base := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
-	recorder, err := NewRecorder(Options{ChunkSeconds: 120, Format: "mp4", Clock: func() time.Time { return base }})
+	recorder, err := NewRecorder(Options{ChunkSeconds: 120, Format: "mp4", Clock: func() time.Time { return base }, Deadline: base.Add(120 * time.Second)})
 	if err != nil {
 		t.Fatalf("new recorder: %v", err)
 	}
@@ -39,7 +39,7 @@ func TestRecorderWritesSegment(t *testing.T) {
 		t.Fatalf("record: %v", err)
 	}
 
-	expected := filepath.Join(dir, "segment_20240201T120000.mp4")
+	expected := filepath.Join(dir, "segment_0001_20240201T120000.mp4")
 	if result.File != expected {
 		t.Fatalf("unexpected file path %q", result.File)
 	}
@@ -70,8 +70,232 @@ func TestRecorderCancellation(t *testing.T) {
 	}
 }
 
+func TestRecorderLoopsSegmentsUntilDeadline(t *testing.T) {
+	SetNativeFactory(func(format string) (NativeRecorder, error) {
+		return &fakeNativeRecorder{format: format}, nil
+	})
+	t.Cleanup(func() { SetNativeFactory(nil) })
+
+	base := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	recorder, err := NewRecorder(Options{ChunkSeconds: 120, Format: "mp4", Clock: func() time.Time { return base }, Deadline: base.Add(5 * time.Minute)})
+	if err != nil {
+		t.Fatalf("new recorder: %v", err)
+	}
+
+	dir := t.TempDir()
+	result, err := recorder.Record(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	var lengths []time.Duration
+	for _, segment := range result.Segments {
+		lengths = append(lengths, segment.Duration())
+	}
+	if fmt.Sprint(lengths) != "[2m0s 2m0s 1m0s]" {
+		t.Fatalf("unexpected segment lengths: %v", lengths)
+	}
+	if result.File != filepath.Join(dir, "segment_0001_20240201T120000.mp4") || !result.Ended.Equal(base.Add(5*time.Minute)) {
+		t.Fatalf("unexpected result span: %+v", result)
+	}
+	if result.Segments[2].File != filepath.Join(dir, "segment_0003_20240201T120400.mp4") {
+		t.Fatalf("unexpected last segment: %+v", result.Segments[2])
+	}
+
+	idx, err := LoadIndex(result.IndexPath)
+	if err != nil {
+		t.Fatalf("load index: %v", err)
+	}
+	if idx.ChunkSeconds != 120 || len(idx.Segments) != 3 || idx.Segments[1].File != result.Segments[1].File || idx.Segments[1].Sidecar != filepath.Join(dir, "segment_0002_20240201T120200.json") {
+		t.Fatalf("unexpected index: %+v", idx)
+	}
+	// The fake backend does not report its settings, so the sidecar omits them.
+	meta, err := LoadSegmentMetadata(idx.Segments[2].Sidecar)
+	if err != nil {
+		t.Fatalf("load sidecar: %v", err)
+	}
+	if meta.FPS != 0 || meta.Resolution != "" || meta.DurationSeconds != 60 || meta.Bytes == 0 {
+		t.Fatalf("unexpected sidecar: %+v", meta)
+	}
+}
+
+func TestRecorderPausesBetweenSegments(t *testing.T) {
+	base := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	now := base
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pauses := make(chan struct{}, 1)
+	resumed := make(chan struct{})
+
+	calls := 0
+	SetNativeFactory(func(format string) (NativeRecorder, error) {
+		return nativeFunc(func(ctx context.Context, dest, filename string, started time.Time, duration time.Duration) (string, error) {
+			calls++
+			switch calls {
+			case 1:
+				// Record part of the segment, then pause mid-way.
+				if err := os.WriteFile(filepath.Join(dest, filename), []byte("partial"), 0o644); err != nil {
+					return "", err
+				}
+				now = started.Add(30 * time.Second)
+				pauses <- struct{}{}
+				<-ctx.Done()
+				return "", ctx.Err()
+			case 2:
+				// Stop capture after the post-resume segment.
+				cancel()
+			}
+			return (&fakeNativeRecorder{format: format}).Record(context.Background(), dest, filename, started, duration)
+		}), nil
+	})
+	t.Cleanup(func() { SetNativeFactory(nil) })
+
+	waits := 0
+	recorder, err := NewRecorder(Options{
+		ChunkSeconds: 60,
+		Format:       "mp4",
+		Clock:        func() time.Time { return now },
+		Pauses:       pauses,
+		Wait: func(ctx context.Context) error {
+			waits++
+			if waits == 2 {
+				// Paused: the clock moves on until resume.
+				now = now.Add(10 * time.Minute)
+				close(resumed)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("new recorder: %v", err)
+	}
+
+	result, err := recorder.Record(ctx, t.TempDir())
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	select {
+	case <-resumed:
+	default:
+		t.Fatalf("expected the recorder to wait before the second segment")
+	}
+	if len(result.Segments) != 2 {
+		t.Fatalf("expected two segments, got %+v", result.Segments)
+	}
+	first, second := result.Segments[0], result.Segments[1]
+	if !first.Truncated || first.Duration() != 30*time.Second {
+		t.Fatalf("expected a truncated 30s first segment, got %+v", first)
+	}
+	if second.Truncated || !second.Started.Equal(base.Add(10*time.Minute+30*time.Second)) {
+		t.Fatalf("expected the second segment to start after the pause, got %+v", second)
+	}
+}
+
+func TestRecorderMeasuresSegmentsThatRunShort(t *testing.T) {
+	base := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	now := base
+	// A real-time backend that always stops after 40 seconds.
+	SetNativeFactory(func(format string) (NativeRecorder, error) {
+		return nativeFunc(func(ctx context.Context, dest, filename string, started time.Time, duration time.Duration) (string, error) {
+			now = now.Add(40 * time.Second)
+			path := filepath.Join(dest, filename)
+			return path, os.WriteFile(path, []byte("short"), 0o644)
+		}), nil
+	})
+	t.Cleanup(func() { SetNativeFactory(nil) })
+
+	recorder, err := NewRecorder(Options{ChunkSeconds: 60, Format: "mp4", Clock: func() time.Time { return now }, Deadline: base.Add(100 * time.Second)})
+	if err != nil {
+		t.Fatalf("new recorder: %v", err)
+	}
+	result, err := recorder.Record(context.Background(), t.TempDir())
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	var lengths []time.Duration
+	for _, segment := range result.Segments {
+		if segment.Truncated {
+			t.Fatalf("a short segment is not a truncated one: %+v", segment)
+		}
+		lengths = append(lengths, segment.Duration())
+	}
+	if fmt.Sprint(lengths) != "[40s 40s 20s]" {
+		t.Fatalf("unexpected segment lengths: %v", lengths)
+	}
+	meta, err := LoadSegmentMetadata(result.Segments[0].Sidecar)
+	if err != nil {
+		t.Fatalf("load sidecar: %v", err)
+	}
+	if meta.DurationSeconds != 40 || !meta.Ended.Equal(base.Add(40*time.Second)) {
+		t.Fatalf("expected the sidecar to record the measured length, got %+v", meta)
+	}
+}
+
+func TestRecorderKeepsSegmentsStartedInTheSameSecond(t *testing.T) {
+	base := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	now := base
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pauses := make(chan struct{}, 1)
+
+	calls := 0
+	SetNativeFactory(func(format string) (NativeRecorder, error) {
+		return nativeFunc(func(segCtx context.Context, dest, filename string, started time.Time, duration time.Duration) (string, error) {
+			calls++
+			if calls == 1 {
+				// Pause and resume within the first second.
+				if err := os.WriteFile(filepath.Join(dest, filename), []byte("first"), 0o644); err != nil {
+					return "", err
+				}
+				now = started.Add(200 * time.Millisecond)
+				pauses <- struct{}{}
+				<-segCtx.Done()
+				return "", segCtx.Err()
+			}
+			cancel()
+			return (&fakeNativeRecorder{format: format}).Record(context.Background(), dest, filename, started, duration)
+		}), nil
+	})
+	t.Cleanup(func() { SetNativeFactory(nil) })
+
+	recorder, err := NewRecorder(Options{ChunkSeconds: 60, Format: "mp4", Clock: func() time.Time { return now }, Pauses: pauses})
+	if err != nil {
+		t.Fatalf("new recorder: %v", err)
+	}
+	dir := t.TempDir()
+	result, err := recorder.Record(ctx, dir)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if len(result.Segments) != 2 {
+		t.Fatalf("expected two segments, got %+v", result.Segments)
+	}
+	first, second := result.Segments[0], result.Segments[1]
+	if first.Started.Truncate(time.Second) != second.Started.Truncate(time.Second) {
+		t.Fatalf("expected both segments to start in the same second: %+v", result.Segments)
+	}
+	if first.File == second.File || first.Sidecar == second.Sidecar {
+		t.Fatalf("expected distinct segment files, got %s twice", first.File)
+	}
+	if data, err := os.ReadFile(first.File); err != nil || string(data) != "first" {
+		t.Fatalf("expected the first segment to survive, got %q (%v)", data, err)
+	}
+}
+
+type nativeFunc func(ctx context.Context, dest, filename string, started time.Time, duration time.Duration) (string, error)
+
+func (f nativeFunc) Record(ctx context.Context, dest, filename string, started time.Time, duration time.Duration) (string, error) {
+	return f(ctx, dest, filename, started, duration)
+}
+
 type fakeNativeRecorder struct {
 	format string
+	last   time.Duration
+}
+
+// LastDuration reports the requested length: the fake writes instantly, so
+// the clock cannot measure it.
+func (f *fakeNativeRecorder) LastDuration() time.Duration {
+	return f.last
 }
 
 func (f *fakeNativeRecorder) Record(ctx context.Context, dest, filename string, started time.Time, duration time.Duration) (string, error) {
@@ -86,6 +310,7 @@ func (f *fakeNativeRecorder) Record(ctx context.Context, dest, filename string,
 	if err := os.WriteFile(path, []byte(payload), 0o644); err != nil {
 		return "", err
 	}
+	f.last = duration
 	return path, nil
 }
 
@@ -0,0 +1,188 @@
+package video
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// IndexName is the segment index file name in the video directory.
+const IndexName = "segments.json"
+
+// IndexVersion is the schema version written to the segment index.
+const IndexVersion = 1
+
+// Segment describes one recorded video file.
+type Segment struct {
+	File    string    `json:"file"`
+	Started time.Time `json:"started"`
+	Ended   time.Time `json:"ended"`
+	// Truncated marks a segment cut short by a pause or stop.
+	Truncated bool `json:"truncated,omitempty"`
+	// Sidecar is the segment's metadata file; see SegmentMetadata.
+	Sidecar string `json:"sidecar,omitempty"`
+}
+
+// Duration returns the segment's recorded length.
+func (s Segment) Duration() time.Duration {
+	return s.Ended.Sub(s.Started)
+}
+
+// Index lists a run's video segments in recording order. Segment files are
+// stored relative to the index.
+type Index struct {
+	SchemaVersion int       `json:"schema_version"`
+	Format        string    `json:"format"`
+	ChunkSeconds  int       `json:"chunk_seconds"`
+	Segments      []Segment `json:"segments"`
+}
+
+// SaveIndex writes idx to path, storing segment files relative to it.
+func SaveIndex(path string, idx Index) error {
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("resolve segment index directory: %w", err)
+	}
+	stored := idx
+	stored.Segments = make([]Segment, len(idx.Segments))
+	for i, segment := range idx.Segments {
+		segment.File = relativeTo(dir, segment.File)
+		segment.Sidecar = relativeTo(dir, segment.Sidecar)
+		stored.Segments[i] = segment
+	}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal segment index: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write segment index: %w", err)
+	}
+	return nil
+}
+
+// LoadIndex reads the index at path and resolves segment files against its
+// directory.
+func LoadIndex(path string) (Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Index{}, fmt.Errorf("read segment index: %w", err)
+	}
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return Index{}, fmt.Errorf("decode segment index: %w", err)
+	}
+	dir := filepath.Dir(path)
+	for i := range idx.Segments {
+		idx.Segments[i].File = resolveFrom(dir, idx.Segments[i].File)
+		idx.Segments[i].Sidecar = resolveFrom(dir, idx.Segments[i].Sidecar)
+	}
+	return idx, nil
+}
+
+func relativeTo(dir, path string) string {
+	if path == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(dir, abs)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func resolveFrom(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, filepath.FromSlash(path))
+}
+
+// MetadataVersion is the schema version written to segment sidecars.
+const MetadataVersion = 1
+
+// SegmentMetadata is the sidecar written next to each segment. FPS and
+// Resolution are omitted for backends that do not report what they record
+// at.
+type SegmentMetadata struct {
+	SchemaVersion   int       `json:"schema_version"`
+	File            string    `json:"file"`
+	Backend         string    `json:"backend,omitempty"`
+	Format          string    `json:"format"`
+	FPS             int       `json:"fps,omitempty"`
+	Resolution      string    `json:"resolution,omitempty"`
+	Started         time.Time `json:"started"`
+	Ended           time.Time `json:"ended"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Truncated       bool      `json:"truncated,omitempty"`
+	Bytes           int64     `json:"bytes"`
+	SHA256          string    `json:"sha256"`
+}
+
+// SidecarPath returns the metadata path for a segment file.
+func SidecarPath(segmentFile string) string {
+	return strings.TrimSuffix(segmentFile, filepath.Ext(segmentFile)) + ".json"
+}
+
+// LoadSegmentMetadata reads a segment sidecar.
+func LoadSegmentMetadata(path string) (SegmentMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SegmentMetadata{}, fmt.Errorf("read segment metadata: %w", err)
+	}
+	var meta SegmentMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return SegmentMetadata{}, fmt.Errorf("decode segment metadata: %w", err)
+	}
+	return meta, nil
+}
+
+// writeSidecar hashes the segment file and writes its metadata, returning
+// the sidecar path.
+func (r *Recorder) writeSidecar(segment Segment) (string, error) {
+	file, err := os.Open(segment.File)
+	if err != nil {
+		return "", fmt.Errorf("open segment for metadata: %w", err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", fmt.Errorf("hash segment: %w", err)
+	}
+
+	meta := SegmentMetadata{
+		SchemaVersion:   MetadataVersion,
+		File:            filepath.Base(segment.File),
+		Backend:         r.backend,
+		Format:          r.format,
+		FPS:             r.settings.FPS,
+		Started:         segment.Started,
+		Ended:           segment.Ended,
+		DurationSeconds: segment.Duration().Seconds(),
+		Truncated:       segment.Truncated,
+		Bytes:           size,
+		SHA256:          hex.EncodeToString(hash.Sum(nil)),
+	}
+	if r.settings.Width > 0 && r.settings.Height > 0 {
+		meta.Resolution = fmt.Sprintf("%dx%d", r.settings.Width, r.settings.Height)
+	}
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal segment metadata: %w", err)
+	}
+	path := SidecarPath(segment.File)
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("write segment metadata: %w", err)
+	}
+	return path, nil
+}
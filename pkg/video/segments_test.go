@@ -0,0 +1,45 @@
+package video
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIndexStoresRelativeFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(dir, IndexName)
+	idx := Index{
+		SchemaVersion: IndexVersion,
+		Format:        "mp4",
+		ChunkSeconds:  60,
+		Segments: []Segment{
+			{File: filepath.Join(dir, "segment_a.mp4"), Started: base, Ended: base.Add(time.Minute)},
+			{File: filepath.Join(dir, "segment_b.mp4"), Started: base.Add(time.Minute), Ended: base.Add(90 * time.Second), Truncated: true},
+		},
+	}
+	if err := SaveIndex(path, idx); err != nil {
+		t.Fatalf("SaveIndex returned error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read index: %v", err)
+	}
+	if strings.Contains(string(data), dir) || !strings.Contains(string(data), `"file": "segment_a.mp4"`) {
+		t.Fatalf("expected relative segment files:\n%s", data)
+	}
+
+	loaded, err := LoadIndex(path)
+	if err != nil {
+		t.Fatalf("LoadIndex returned error: %v", err)
+	}
+	if len(loaded.Segments) != 2 || loaded.Segments[1].File != idx.Segments[1].File || !loaded.Segments[1].Truncated || loaded.Segments[1].Duration() != 30*time.Second {
+		t.Fatalf("unexpected round trip: %+v", loaded)
+	}
+	if _, err := LoadIndex(filepath.Join(dir, "missing.json")); err == nil {
+		t.Fatalf("expected an error for a missing index")
+	}
+}
@@ -0,0 +1,179 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Synthetic recorder defaults, used for settings the recorder is not given.
+// They keep frames small and sparse so CI runs stay cheap while still
+// producing files any player can open.
+const (
+	syntheticWidth   = 320
+	syntheticHeight  = 180
+	syntheticFPS     = 1
+	syntheticQuality = 60
+	syntheticFormat  = "avi"
+)
+
+// syntheticRecorder renders generated frames into Motion JPEG AVI files. It
+// needs no platform APIs, so it runs anywhere; select it with
+// LIMITLESS_VIDEO_BACKEND=synthetic.
+type syntheticRecorder struct {
+	settings Settings
+	last     time.Duration
+	clock    func() time.Time
+	sleeper  func(context.Context, time.Duration) error
+}
+
+func newSyntheticRecorder() (NativeRecorder, error) {
+	return &syntheticRecorder{
+		settings: Settings{FPS: syntheticFPS, Width: syntheticWidth, Height: syntheticHeight},
+		clock:    time.Now,
+		sleeper:  defaultSleeper,
+	}, nil
+}
+
+// ContainerFormat reports that segments are AVI regardless of the configured
+// format.
+func (*syntheticRecorder) ContainerFormat() string {
+	return syntheticFormat
+}
+
+// Configure adopts the requested frame rate and size; zero values keep the
+// defaults.
+func (s *syntheticRecorder) Configure(requested Settings) Settings {
+	if requested.FPS > 0 {
+		s.settings.FPS = requested.FPS
+	}
+	if requested.Width > 0 && requested.Height > 0 {
+		s.settings.Width, s.settings.Height = requested.Width, requested.Height
+	}
+	return s.settings
+}
+
+// Pace makes Record wait on clock, via sleeper, until each frame is due.
+func (s *syntheticRecorder) Pace(clock func() time.Time, sleeper func(context.Context, time.Duration) error) {
+	s.clock, s.sleeper = clock, sleeper
+}
+
+// LastDuration reports the length of video the last Record call wrote.
+func (s *syntheticRecorder) LastDuration() time.Duration {
+	return s.last
+}
+
+// Record renders each frame when it is due, so a segment takes as long to
+// record as the video it holds. A canceled context ends the segment early;
+// frames rendered so far are still written so the partial file stays
+// playable.
+func (s *syntheticRecorder) Record(ctx context.Context, dest string, filename string, started time.Time, duration time.Duration) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	settings := s.settings
+	s.last = 0
+	count := int(math.Ceil(duration.Seconds() * float64(settings.FPS)))
+	if count < 1 {
+		count = 1
+	}
+	frames := make([][]byte, 0, count)
+	var stopErr error
+	for i := 0; i < count; i++ {
+		if err := ctx.Err(); err != nil {
+			stopErr = err
+			break
+		}
+		if err := s.waitUntil(ctx, started.Add(frameTime(i, settings.FPS))); err != nil {
+			stopErr = err
+			break
+		}
+		frame, err := syntheticFrame(settings.Width, settings.Height, started, i, count)
+		if err != nil {
+			return "", err
+		}
+		frames = append(frames, frame)
+	}
+	if len(frames) == 0 {
+		return "", stopErr
+	}
+
+	path := filepath.Join(dest, filename)
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("create segment: %w", err)
+	}
+	if err := writeMJPEGAVI(file, settings.Width, settings.Height, settings.FPS, frames); err != nil {
+		file.Close()
+		return "", fmt.Errorf("write segment: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("close segment: %w", err)
+	}
+	s.last = frameTime(len(frames), settings.FPS)
+	if stopErr != nil {
+		return "", stopErr
+	}
+	// Hold the last frame for its display time. The segment is already
+	// complete, so a stop while waiting is left for the caller to notice.
+	_ = s.waitUntil(ctx, started.Add(s.last))
+	return path, nil
+}
+
+// waitUntil sleeps until the clock reaches at.
+func (s *syntheticRecorder) waitUntil(ctx context.Context, at time.Time) error {
+	if wait := at.Sub(s.clock()); wait > 0 {
+		return s.sleeper(ctx, wait)
+	}
+	return nil
+}
+
+// frameTime is the offset of frame i from the start of a segment.
+func frameTime(i, fps int) time.Duration {
+	return time.Duration(i) * time.Second / time.Duration(fps)
+}
+
+// syntheticFrame draws a background tinted by the segment start, a bar that
+// sweeps across the segment, and the frame index as a row of binary blocks.
+func syntheticFrame(width, height int, started time.Time, index, count int) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	shade := uint8(started.Unix() % 64)
+	background := color.RGBA{R: 32 + shade, G: 48, B: 96 - shade/2, A: 255}
+	fill(img, img.Bounds(), background)
+
+	barWidth := width/40 + 1
+	barX := index * (width - barWidth) / count
+	fill(img, image.Rect(barX, 0, barX+barWidth, height), color.RGBA{R: 240, G: 200, B: 64, A: 255})
+
+	block := width/28 + 1
+	for bit := 0; bit < 16; bit++ {
+		c := color.RGBA{R: 16, G: 16, B: 16, A: 255}
+		if index&(1<<bit) != 0 {
+			c = color.RGBA{R: 240, G: 240, B: 240, A: 255}
+		}
+		x := block + (15-bit)*(block+block/6+1)
+		fill(img, image.Rect(x, height-2*block, x+block, height-block), c)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: syntheticQuality}); err != nil {
+		return nil, fmt.Errorf("encode frame: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func fill(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	rect = rect.Intersect(img.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var syntheticBase = time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+
+// steppingClock returns a clock and a sleeper that advances it instead of
+// waiting, so paced recordings finish instantly.
+func steppingClock(start time.Time) (func() time.Time, func(context.Context, time.Duration) error) {
+	now := start
+	clock := func() time.Time { return now }
+	sleeper := func(ctx context.Context, wait time.Duration) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		now = now.Add(wait)
+		return nil
+	}
+	return clock, sleeper
+}
+
+func TestSyntheticBackendRecordsPlayableSegments(t *testing.T) {
+	t.Setenv(backendEnv, "synthetic")
+
+	env := DetectEnvironment()
+	if env.Provider != ProviderSynthetic || !env.Available || env.Permission != PermissionNotApplicable {
+		t.Fatalf("unexpected environment: %+v", env)
+	}
+
+	clock, sleeper := steppingClock(syntheticBase)
+	recorder, err := NewRecorder(Options{
+		ChunkSeconds: 5,
+		Format:       "mp4",
+		Clock:        clock,
+		Sleeper:      sleeper,
+		Deadline:     syntheticBase.Add(8 * time.Second),
+		FPS:          2,
+		Width:        160,
+		Height:       90,
+		Backend:      ProviderSynthetic,
+	})
+	if err != nil {
+		t.Fatalf("new recorder: %v", err)
+	}
+	dir := t.TempDir()
+	result, err := recorder.Record(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if len(result.Segments) != 2 || result.File != filepath.Join(dir, "segment_0001_20240201T120000.avi") {
+		t.Fatalf("unexpected segments: %+v", result.Segments)
+	}
+	for i, want := range []int{5, 3} {
+		data, err := os.ReadFile(result.Segments[i].File)
+		if err != nil {
+			t.Fatalf("read segment: %v", err)
+		}
+		if frames, width, height := checkAVI(t, data); frames != want*2 || width != 160 || height != 90 {
+			t.Fatalf("segment %d is %d frames at %dx%d, want %d at 160x90", i, frames, width, height, want*2)
+		}
+	}
+
+	meta, err := LoadSegmentMetadata(result.Segments[1].Sidecar)
+	if err != nil {
+		t.Fatalf("load sidecar: %v", err)
+	}
+	if meta.Backend != ProviderSynthetic || meta.FPS != 2 || meta.Resolution != "160x90" || meta.Format != "avi" || meta.DurationSeconds != 3 {
+		t.Fatalf("unexpected sidecar: %+v", meta)
+	}
+	info, err := os.Stat(result.Segments[1].File)
+	if err != nil || meta.Bytes != info.Size() || len(meta.SHA256) != 64 || meta.File != "segment_0002_20240201T120005.avi" {
+		t.Fatalf("sidecar does not describe the segment file: %+v (%v)", meta, err)
+	}
+	idx, err := LoadIndex(result.IndexPath)
+	if err != nil || idx.Format != "avi" {
+		t.Fatalf("unexpected index: %+v, %v", idx, err)
+	}
+}
+
+func TestSyntheticRecorderPacesToTheClock(t *testing.T) {
+	t.Setenv(backendEnv, "synthetic")
+	clock, step := steppingClock(syntheticBase)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The run is stopped five minutes in, as a user would with tester stop.
+	stopAt := syntheticBase.Add(5 * time.Minute)
+	sleeper := func(ctx context.Context, wait time.Duration) error {
+		if err := step(ctx, wait); err != nil {
+			return err
+		}
+		if !clock().Before(stopAt) {
+			cancel()
+			return ctx.Err()
+		}
+		return nil
+	}
+	recorder, err := NewRecorder(Options{
+		ChunkSeconds: 60,
+		Format:       "mp4",
+		Clock:        clock,
+		Sleeper:      sleeper,
+		Deadline:     syntheticBase.Add(time.Hour),
+		FPS:          1,
+		Width:        16,
+		Height:       9,
+	})
+	if err != nil {
+		t.Fatalf("new recorder: %v", err)
+	}
+	result, err := recorder.Record(ctx, t.TempDir())
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if len(result.Segments) != 5 || !result.Ended.Equal(stopAt) {
+		t.Fatalf("expected five minutes of segments ending at %s, got %d ending at %s", stopAt, len(result.Segments), result.Ended)
+	}
+	for _, segment := range result.Segments {
+		if segment.Truncated || segment.Duration() != time.Minute {
+			t.Fatalf("expected complete one-minute segments, got %+v", segment)
+		}
+	}
+}
+
+func TestSyntheticRecorderCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	dir := t.TempDir()
+	native, err := newSyntheticRecorder()
+	if err != nil {
+		t.Fatalf("newSyntheticRecorder returned error: %v", err)
+	}
+	if _, err := native.Record(ctx, dir, "segment.avi", syntheticBase, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "segment.avi")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no file for a segment canceled before its first frame, got %v", err)
+	}
+}
+
+func TestSyntheticBackendKeepsCheapDefaultsWhenUnset(t *testing.T) {
+	t.Setenv(backendEnv, "synthetic")
+	recorder, err := NewRecorder(Options{ChunkSeconds: 5, Format: "mp4"})
+	if err != nil {
+		t.Fatalf("new recorder: %v", err)
+	}
+	if want := (Settings{FPS: syntheticFPS, Width: syntheticWidth, Height: syntheticHeight}); recorder.settings != want {
+		t.Fatalf("expected synthetic defaults %+v, got %+v", want, recorder.settings)
+	}
+}